@@ -3,16 +3,70 @@ package main
 import (
 	"context"
 	"fmt"
+	"os"
+	"path/filepath"
 	"strings"
+	"sync"
+	"time"
 
 	"chat-explorer/models"
 
+	"github.com/wailsapp/wails/v2/pkg/options"
 	"github.com/wailsapp/wails/v2/pkg/runtime"
 )
 
+// appDataDirName is the folder below the user config directory holding local state.
+const appDataDirName = "chat-explorer"
+
+// exportOpenedEvent tells the frontend an export was opened from outside the app: by
+// "open with" while it runs, or by a file dropped on the window.
+const exportOpenedEvent = "export:opened"
+
+// sessionRestoredEvent tells the frontend the export opened on startup, or the one of
+// the last session, has finished loading in the background.
+const sessionRestoredEvent = "session:restored"
+
+// defaultWindowWidth and defaultWindowHeight size the window until a size was saved.
+const (
+	defaultWindowWidth  = 1024
+	defaultWindowHeight = 768
+)
+
+// supportedExportExtensions are the files accepted from "open with" and drops, matching
+// the filters of the open dialog.
+var supportedExportExtensions = []string{".json", ".jsonl", ".zip", ".gz", ".zst", ".csv", ".html"}
+
+// exportOpened is the payload of exportOpenedEvent. Error is set when loading failed.
+type exportOpened struct {
+	Path  string `json:"path"`
+	Error string `json:"error,omitempty"`
+}
+
 // App struct
 type App struct {
+	// ctx is set by startup under mu; read it with runtimeContext.
 	ctx context.Context
+	// dataDir overrides where local state is kept; blank uses the user config directory.
+	dataDir string
+
+	storeOnce sync.Once
+	store     *models.LocalStore
+	storeErr  error
+
+	mu            sync.RWMutex
+	sourcePath    string
+	conversations []models.Conversation
+	warnings      []models.ParseWarning
+	snippets      []models.Snippet
+	links         []models.Link
+
+	// sessionRestored is set once the export of the last session was reopened, or
+	// found missing, so an unlock later in the session does not reload it.
+	sessionRestored bool
+	restoreErr      error
+	// pendingPath is an export to open on startup, from the command line or from a
+	// macOS "open with" that arrived before the window existed.
+	pendingPath string
 }
 
 // NewApp creates a new App application struct
@@ -23,26 +77,194 @@ func NewApp() *App {
 // startup is called when the app starts. The context is saved
 // so we can call the runtime methods
 func (a *App) startup(ctx context.Context) {
+	a.mu.Lock()
 	a.ctx = ctx
+	pendingPath := a.pendingPath
+	a.pendingPath = ""
+	a.mu.Unlock()
+
+	runtime.OnFileDrop(ctx, func(_, _ int, paths []string) {
+		a.announceOpened(a.openDroppedPaths(paths))
+	})
+
+	// A large export would hold the window blank while it loads.
+	go func() {
+		a.restoreOnStartup(pendingPath)
+		runtime.EventsEmit(ctx, sessionRestoredEvent)
+	}()
+}
+
+// restoreOnStartup opens the export named on launch, which replaces the one of the
+// last session, or else reopens the last session.
+func (a *App) restoreOnStartup(pendingPath string) {
+	if pendingPath != "" {
+		a.openStartupPath(pendingPath)
+	}
+	_ = a.restoreSession()
+}
+
+// initialWindowSize returns the window size saved by the last session, or the default
+// size when none was saved or the settings cannot be read, such as in a locked library.
+func (a *App) initialWindowSize() (int, int) {
+	settings, err := a.GetSettings()
+	if err != nil || settings.Window.Width <= 0 || settings.Window.Height <= 0 {
+		return defaultWindowWidth, defaultWindowHeight
+	}
+
+	return settings.Window.Width, settings.Window.Height
+}
+
+// openStartupPath opens the export named on launch. A failure is reported through
+// GetSession like a failed restore.
+func (a *App) openStartupPath(path string) {
+	a.mu.Lock()
+	a.sessionRestored = true
+	a.mu.Unlock()
+
+	if _, err := a.LoadConversationsFromPath(path); err != nil {
+		a.mu.Lock()
+		a.restoreErr = err
+		a.mu.Unlock()
+	}
+}
+
+// openFile handles a macOS "open with", which arrives before startup when it launched
+// the app.
+func (a *App) openFile(path string) {
+	a.mu.Lock()
+	started := a.ctx != nil
+	if !started {
+		a.pendingPath = path
+	}
+	a.mu.Unlock()
+
+	if started {
+		a.announceOpened(a.openExternalPath(path))
+	}
+}
+
+// onSecondInstanceLaunch handles "open with" on Windows and Linux while the app runs:
+// the second process hands its arguments over and exits.
+func (a *App) onSecondInstanceLaunch(data options.SecondInstanceData) {
+	if ctx := a.runtimeContext(); ctx != nil {
+		runtime.WindowUnminimise(ctx)
+		runtime.Show(ctx)
+	}
+
+	if path := exportPathFromArgs(data.Args, data.WorkingDirectory); path != "" {
+		a.announceOpened(a.openExternalPath(path))
+	}
+}
+
+// openDroppedPaths opens the first supported export among the files dropped on the
+// window.
+func (a *App) openDroppedPaths(paths []string) exportOpened {
+	for _, path := range paths {
+		if isSupportedExportPath(path) {
+			return a.openExternalPath(path)
+		}
+	}
+
+	return exportOpened{Error: fmt.Sprintf("drop a conversations export (%s)", strings.Join(supportedExportExtensions, ", "))}
+}
+
+func (a *App) openExternalPath(path string) exportOpened {
+	if _, err := a.LoadConversationsFromPath(path); err != nil {
+		return exportOpened{Path: path, Error: err.Error()}
+	}
+
+	return exportOpened{Path: path}
+}
+
+func (a *App) announceOpened(opened exportOpened) {
+	if ctx := a.runtimeContext(); ctx != nil {
+		runtime.EventsEmit(ctx, exportOpenedEvent, opened)
+	}
+}
+
+// runtimeContext returns the context saved by startup, or nil before it ran. Callbacks
+// such as onSecondInstanceLaunch run on their own goroutines, so it is read under a.mu.
+func (a *App) runtimeContext() context.Context {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	return a.ctx
+}
+
+func isSupportedExportPath(path string) bool {
+	extension := strings.ToLower(filepath.Ext(path))
+	for _, supported := range supportedExportExtensions {
+		if extension == supported {
+			return true
+		}
+	}
+
+	return false
+}
+
+// exportPathFromArgs returns the export named by the command-line arguments: the first
+// argument that is not a flag, such as the -psn_ argument macOS adds. Relative paths
+// are resolved against workingDir, or the current directory when it is blank.
+func exportPathFromArgs(args []string, workingDir string) string {
+	for _, arg := range args {
+		trimmedArg := strings.TrimSpace(arg)
+		if trimmedArg == "" || strings.HasPrefix(trimmedArg, "-") {
+			continue
+		}
+		if filepath.IsAbs(trimmedArg) || workingDir == "" {
+			if absolutePath, err := filepath.Abs(trimmedArg); err == nil {
+				return absolutePath
+			}
+			return trimmedArg
+		}
+		return filepath.Join(workingDir, trimmedArg)
+	}
+
+	return ""
+}
+
+// beforeClose records the window size for the next start. It never prevents closing.
+func (a *App) beforeClose(ctx context.Context) bool {
+	width, height := runtime.WindowGetSize(ctx)
+	// A locked library cannot be written; the previous size is kept then.
+	_ = a.updateSettings(func(settings models.Settings) models.Settings {
+		settings.Window = models.WindowSettings{Width: width, Height: height}
+		return settings
+	})
+
+	return false
 }
 
 func (a *App) OpenConversationsFile() ([]models.ConversationEntry, error) {
-	if a.ctx == nil {
+	ctx := a.runtimeContext()
+	if ctx == nil {
 		return nil, fmt.Errorf("application is not initialized")
 	}
 
-	path, err := runtime.OpenFileDialog(a.ctx, runtime.OpenDialogOptions{
-		Title:           "Open conversations export (.json or .zip)",
+	path, err := runtime.OpenFileDialog(ctx, runtime.OpenDialogOptions{
+		Title:           "Open conversations export (.json, .jsonl, .csv, .zip, .gz or .zst)",
 		DefaultFilename: "conversations.json",
 		Filters: []runtime.FileFilter{
 			{
-				DisplayName: "JSON Files (*.json)",
-				Pattern:     "*.json",
+				DisplayName: "JSON Files (*.json, *.jsonl)",
+				Pattern:     "*.json;*.jsonl",
 			},
 			{
 				DisplayName: "ZIP Files (*.zip)",
 				Pattern:     "*.zip",
 			},
+			{
+				DisplayName: "Compressed Files (*.gz, *.zst)",
+				Pattern:     "*.gz;*.zst",
+			},
+			{
+				DisplayName: "Google Takeout My Activity (*.html)",
+				Pattern:     "*.html",
+			},
+			{
+				DisplayName: "CSV Exports (*.csv)",
+				Pattern:     "*.csv",
+			},
 		},
 	})
 	if err != nil {
@@ -50,17 +272,680 @@ func (a *App) OpenConversationsFile() ([]models.ConversationEntry, error) {
 	}
 
 	if strings.TrimSpace(path) == "" {
+		a.setLoadResult("", models.ParseResult{})
 		return []models.ConversationEntry{}, nil
 	}
 
 	return a.LoadConversationsFromPath(path)
 }
 
+// LoadConversationsFromPath parses leniently: malformed conversations are skipped
+// and reported through GetParseWarnings instead of failing the whole export.
 func (a *App) LoadConversationsFromPath(path string) ([]models.ConversationEntry, error) {
-	entries, err := models.LoadConversationEntries(path)
+	result, err := models.LoadConversations(path, models.ParseOptions{Lenient: true})
 	if err != nil {
+		a.setLoadResult("", models.ParseResult{})
 		return nil, fmt.Errorf("load conversations from %s: %w", path, err)
 	}
 
-	return entries, nil
+	a.setLoadResult(path, result)
+	// Remembering the path is best effort: a locked library must not fail the load.
+	_ = a.updateSettings(func(settings models.Settings) models.Settings {
+		return models.RememberOpenedPath(settings, path)
+	})
+	return result.Entries(), nil
+}
+
+// GetParseWarnings returns the conversations skipped during the most recent load.
+func (a *App) GetParseWarnings() []models.ParseWarning {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	warnings := make([]models.ParseWarning, len(a.warnings))
+	copy(warnings, a.warnings)
+	return warnings
+}
+
+// GetCustomInstructionsTimeline returns the distinct custom-instruction versions found
+// in the most recently loaded export, oldest first.
+func (a *App) GetCustomInstructionsTimeline() []models.CustomInstructionsVersion {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	return models.CustomInstructionsTimeline(a.conversations)
+}
+
+// GetStatistics aggregates the most recently loaded export in the local timezone.
+func (a *App) GetStatistics() models.Statistics {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	return models.ComputeStatistics(a.conversations, time.Local)
+}
+
+// GetActivityTimeline buckets the messages of the most recent load by granularity,
+// "day" or "hour", in the local timezone, for an activity heatmap and a monthly chart.
+func (a *App) GetActivityTimeline(granularity string) (models.ActivityTimeline, error) {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	return models.ComputeActivityTimeline(a.conversations, granularity, time.Local)
+}
+
+// GetLinks returns the catalog of links mentioned or cited in the most recent load,
+// most referenced first.
+func (a *App) GetLinks() []models.Link {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	links := make([]models.Link, len(a.links))
+	copy(links, a.links)
+	return links
+}
+
+// GetTopics clusters the conversations of the most recent load by content. A topicCount
+// of zero picks the number of topics from the size of the export.
+func (a *App) GetTopics(topicCount int) []models.Topic {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	return models.ClusterConversations(a.conversations, topicCount)
+}
+
+// ScanSensitiveData looks for credentials and personal data in the messages and
+// attachments of the most recent load, most severe first.
+func (a *App) ScanSensitiveData() []models.SensitiveFinding {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	return models.ScanSensitiveData(a.conversations)
+}
+
+// ExportRedacted asks where to save a redacted copy of the most recently loaded export
+// and writes it there. It returns the number of values redacted.
+func (a *App) ExportRedacted() (int, error) {
+	ctx := a.runtimeContext()
+	if ctx == nil {
+		return 0, fmt.Errorf("application is not initialized")
+	}
+
+	path, err := runtime.SaveFileDialog(ctx, runtime.SaveDialogOptions{
+		Title:           "Save redacted copy of the export",
+		DefaultFilename: "conversations.redacted.json",
+		Filters: []runtime.FileFilter{
+			{
+				DisplayName: "JSON Files (*.json)",
+				Pattern:     "*.json",
+			},
+		},
+	})
+	if err != nil {
+		return 0, fmt.Errorf("save file dialog: %w", err)
+	}
+	if strings.TrimSpace(path) == "" {
+		return 0, nil
+	}
+
+	return a.ExportRedactedToPath(path)
+}
+
+// ExportRedactedToPath writes the most recently loaded export to path with every
+// sensitive value replaced by a typed placeholder, in the export's original schema.
+func (a *App) ExportRedactedToPath(path string) (int, error) {
+	a.mu.RLock()
+	sourcePath := a.sourcePath
+	a.mu.RUnlock()
+
+	if sourcePath == "" {
+		return 0, fmt.Errorf("no export is loaded")
+	}
+
+	count, err := models.WriteRedactedExport(sourcePath, path)
+	if err != nil {
+		return 0, fmt.Errorf("export redacted copy to %s: %w", path, err)
+	}
+
+	return count, nil
+}
+
+// GetStoreStatus reports whether the local library is encrypted and still locked, so
+// the frontend knows to ask for the passphrase on startup.
+func (a *App) GetStoreStatus() (models.StoreStatus, error) {
+	store, err := a.localStore()
+	if err != nil {
+		return models.StoreStatus{}, err
+	}
+
+	return store.Status(), nil
+}
+
+// Unlock opens the encrypted local library with passphrase.
+func (a *App) Unlock(passphrase string) error {
+	store, err := a.localStore()
+	if err != nil {
+		return err
+	}
+
+	if err := store.Unlock(passphrase); err != nil {
+		return err
+	}
+
+	// The settings naming the last export were unreadable until now.
+	_ = a.restoreSession()
+	return nil
+}
+
+// EnableEncryption encrypts the local library with a key derived from passphrase,
+// including the files already saved in it.
+func (a *App) EnableEncryption(passphrase string) error {
+	store, err := a.localStore()
+	if err != nil {
+		return err
+	}
+
+	if err := store.EnableEncryption(passphrase); err != nil {
+		return fmt.Errorf("enable encryption: %w", err)
+	}
+	return nil
+}
+
+// GetSession returns the saved settings and the export reopened from the last
+// session, for the frontend to restore on start.
+func (a *App) GetSession() (models.Session, error) {
+	settings, err := a.GetSettings()
+	if err != nil {
+		return models.Session{}, err
+	}
+
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	session := models.Session{
+		Settings:   settings,
+		SourcePath: a.sourcePath,
+		Entries:    models.FlattenConversations(a.conversations),
+	}
+	if a.restoreErr != nil {
+		session.RestoreError = a.restoreErr.Error()
+	}
+	return session, nil
+}
+
+// GetSettings returns the saved settings.
+func (a *App) GetSettings() (models.Settings, error) {
+	store, err := a.localStore()
+	if err != nil {
+		return models.Settings{}, err
+	}
+
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	return models.LoadSettings(store)
+}
+
+// SaveSettings saves the preferences the frontend owns: the sort mode, the expanded
+// conversations and the theme. The opened paths and the window size are recorded by
+// the app itself and kept as saved.
+func (a *App) SaveSettings(settings models.Settings) (models.Settings, error) {
+	var saved models.Settings
+	err := a.updateSettings(func(current models.Settings) models.Settings {
+		current.SortMode = settings.SortMode
+		current.ExpandedConversations = settings.ExpandedConversations
+		current.Theme = settings.Theme
+		saved = models.NormalizeSettings(current)
+		return saved
+	})
+	if err != nil {
+		return models.Settings{}, err
+	}
+
+	return saved, nil
+}
+
+func (a *App) updateSettings(update func(settings models.Settings) models.Settings) error {
+	store, err := a.localStore()
+	if err != nil {
+		return err
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	settings, err := models.LoadSettings(store)
+	if err != nil {
+		return err
+	}
+
+	return models.SaveSettings(store, update(settings))
+}
+
+// restoreSession reopens the export of the last session, once. It reports why the
+// settings could not be read, such as a locked library.
+func (a *App) restoreSession() error {
+	settings, err := a.GetSettings()
+	if err != nil {
+		return err
+	}
+
+	a.mu.Lock()
+	alreadyRestored := a.sessionRestored || a.sourcePath != ""
+	a.sessionRestored = true
+	a.mu.Unlock()
+	if alreadyRestored || len(settings.LastOpenedPaths) == 0 {
+		return nil
+	}
+
+	lastPath := settings.LastOpenedPaths[0]
+	if _, err := a.LoadConversationsFromPath(lastPath); err != nil {
+		a.mu.Lock()
+		a.restoreErr = fmt.Errorf("reopen last export: %w", err)
+		a.mu.Unlock()
+	}
+
+	return nil
+}
+
+// GetRecentFiles lists the recently opened exports, newest first. They are opened with
+// LoadConversationsFromPath.
+func (a *App) GetRecentFiles() ([]models.RecentFile, error) {
+	settings, err := a.GetSettings()
+	if err != nil {
+		return nil, err
+	}
+
+	return models.RecentFiles(settings), nil
+}
+
+// ClearRecentFiles forgets the recently opened exports.
+func (a *App) ClearRecentFiles() error {
+	return a.updateSettings(func(settings models.Settings) models.Settings {
+		settings.LastOpenedPaths = []string{}
+		return settings
+	})
+}
+
+// GetDeletionPlan returns the saved deletion plan.
+func (a *App) GetDeletionPlan() (models.DeletionPlan, error) {
+	store, err := a.localStore()
+	if err != nil {
+		return models.DeletionPlan{}, err
+	}
+
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	return models.LoadDeletionPlan(store)
+}
+
+// AddToDeletionPlan adds the conversations of the most recent load that criteria
+// selects to the saved deletion plan and returns the updated plan.
+func (a *App) AddToDeletionPlan(criteria models.DeletionCriteria) (models.DeletionPlan, error) {
+	return a.updateDeletionPlan(func(plan models.DeletionPlan, now time.Time) (models.DeletionPlan, error) {
+		items, err := models.SelectForDeletion(a.conversations, criteria, now)
+		if err != nil {
+			return plan, err
+		}
+		return models.AddToDeletionPlan(plan, items), nil
+	})
+}
+
+// SetDeletionDone records whether a planned conversation was deleted at its provider.
+func (a *App) SetDeletionDone(provider string, conversationID string, done bool) (models.DeletionPlan, error) {
+	return a.updateDeletionPlan(func(plan models.DeletionPlan, now time.Time) (models.DeletionPlan, error) {
+		updatedPlan, found := models.SetDeletionItemDone(plan, provider, conversationID, done, now)
+		if !found {
+			return plan, fmt.Errorf("conversation %s is not in the deletion plan", conversationID)
+		}
+		return updatedPlan, nil
+	})
+}
+
+// RemoveFromDeletionPlan drops a conversation from the saved deletion plan.
+func (a *App) RemoveFromDeletionPlan(provider string, conversationID string) (models.DeletionPlan, error) {
+	return a.updateDeletionPlan(func(plan models.DeletionPlan, _ time.Time) (models.DeletionPlan, error) {
+		return models.RemoveFromDeletionPlan(plan, provider, conversationID), nil
+	})
+}
+
+// ExportDeletionPlan asks where to save the deletion plan and writes it there as
+// Markdown or CSV. It returns the chosen path, or "" when the dialog was cancelled.
+func (a *App) ExportDeletionPlan(format string) (string, error) {
+	ctx := a.runtimeContext()
+	if ctx == nil {
+		return "", fmt.Errorf("application is not initialized")
+	}
+
+	defaultFilename, filter := "deletion-plan.md", runtime.FileFilter{DisplayName: "Markdown Files (*.md)", Pattern: "*.md"}
+	if format == models.DeletionPlanFormatCSV {
+		defaultFilename, filter = "deletion-plan.csv", runtime.FileFilter{DisplayName: "CSV Files (*.csv)", Pattern: "*.csv"}
+	}
+
+	path, err := runtime.SaveFileDialog(ctx, runtime.SaveDialogOptions{
+		Title:           "Export deletion plan",
+		DefaultFilename: defaultFilename,
+		Filters:         []runtime.FileFilter{filter},
+	})
+	if err != nil {
+		return "", fmt.Errorf("save file dialog: %w", err)
+	}
+	if strings.TrimSpace(path) == "" {
+		return "", nil
+	}
+
+	if err := a.ExportDeletionPlanToPath(path, format); err != nil {
+		return "", err
+	}
+	return path, nil
+}
+
+// ExportDeletionPlanToPath writes the saved deletion plan to path as Markdown or CSV.
+func (a *App) ExportDeletionPlanToPath(path string, format string) error {
+	plan, err := a.GetDeletionPlan()
+	if err != nil {
+		return err
+	}
+
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("create %s: %w", path, err)
+	}
+	writeErr := models.WriteDeletionPlan(file, plan, format)
+	closeErr := file.Close()
+	if writeErr != nil {
+		return fmt.Errorf("export deletion plan to %s: %w", path, writeErr)
+	}
+	if closeErr != nil {
+		return fmt.Errorf("close %s: %w", path, closeErr)
+	}
+
+	return nil
+}
+
+func (a *App) updateDeletionPlan(update func(plan models.DeletionPlan, now time.Time) (models.DeletionPlan, error)) (models.DeletionPlan, error) {
+	store, err := a.localStore()
+	if err != nil {
+		return models.DeletionPlan{}, err
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	plan, err := models.LoadDeletionPlan(store)
+	if err != nil {
+		return models.DeletionPlan{}, err
+	}
+
+	now := time.Now()
+	plan, err = update(plan, now)
+	if err != nil {
+		return models.DeletionPlan{}, err
+	}
+	plan.UpdatedAt = now.UTC().Format(time.RFC3339)
+	if err := models.SaveDeletionPlan(store, plan); err != nil {
+		return models.DeletionPlan{}, err
+	}
+
+	return plan, nil
+}
+
+// GetAnnotations returns the saved stars, tags and notes, with message notes moved to
+// follow their messages in the most recent load.
+func (a *App) GetAnnotations() (models.Annotations, error) {
+	store, err := a.localStore()
+	if err != nil {
+		return models.Annotations{}, err
+	}
+
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	annotations, err := models.LoadAnnotations(store)
+	if err != nil {
+		return models.Annotations{}, err
+	}
+
+	return models.ReattachMessageNotes(annotations, a.conversations), nil
+}
+
+// StarConversation stars or unstars a conversation.
+func (a *App) StarConversation(provider string, conversationID string, starred bool) (models.Annotations, error) {
+	return a.updateAnnotation(provider, conversationID, func(annotation *models.ConversationAnnotation) {
+		annotation.Starred = starred
+	})
+}
+
+// SetConversationTags replaces the tags of a conversation.
+func (a *App) SetConversationTags(provider string, conversationID string, tags []string) (models.Annotations, error) {
+	return a.updateAnnotation(provider, conversationID, func(annotation *models.ConversationAnnotation) {
+		annotation.Tags = models.NormalizeTags(tags)
+	})
+}
+
+// SetConversationNote replaces the note on a conversation; a blank note removes it.
+func (a *App) SetConversationNote(provider string, conversationID string, note string) (models.Annotations, error) {
+	return a.updateAnnotation(provider, conversationID, func(annotation *models.ConversationAnnotation) {
+		annotation.Note = strings.TrimSpace(note)
+	})
+}
+
+// SetMessageNote replaces the note on a message of a conversation; a blank note
+// removes it.
+func (a *App) SetMessageNote(provider string, conversationID string, messageIndex int, note string) (models.Annotations, error) {
+	if messageIndex < 0 {
+		return models.Annotations{}, fmt.Errorf("message index must not be negative")
+	}
+
+	return a.updateAnnotation(provider, conversationID, func(annotation *models.ConversationAnnotation) {
+		annotation.SetMessageNote(messageIndex, a.loadedMessage(provider, conversationID, messageIndex), note)
+	})
+}
+
+// updateAnnotation loads the saved annotations, applies update to one conversation and
+// saves them again.
+func (a *App) updateAnnotation(provider string, conversationID string, update func(annotation *models.ConversationAnnotation)) (models.Annotations, error) {
+	store, err := a.localStore()
+	if err != nil {
+		return models.Annotations{}, err
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	annotations, err := models.LoadAnnotations(store)
+	if err != nil {
+		return models.Annotations{}, err
+	}
+
+	annotations, err = models.UpdateConversationAnnotation(
+		models.ReattachMessageNotes(annotations, a.conversations),
+		provider,
+		conversationID,
+		time.Now(),
+		update,
+	)
+	if err != nil {
+		return models.Annotations{}, err
+	}
+	if err := models.SaveAnnotations(store, annotations); err != nil {
+		return models.Annotations{}, err
+	}
+
+	return annotations, nil
+}
+
+// loadedMessage returns a message of the most recent load, or a zero message when it
+// is not loaded. Callers hold a.mu.
+func (a *App) loadedMessage(provider string, conversationID string, messageIndex int) models.Message {
+	for _, conversation := range a.conversations {
+		if conversation.Provider == provider && conversation.ID == conversationID && messageIndex >= 0 && messageIndex < len(conversation.Messages) {
+			return conversation.Messages[messageIndex]
+		}
+	}
+
+	return models.Message{}
+}
+
+// FilterConversations returns the conversations of the most recent load matching
+// query, see models.SearchQuery for its syntax.
+func (a *App) FilterConversations(query string) ([]models.CollectionConversation, error) {
+	parsedQuery, err := models.ParseSearchQuery(query)
+	if err != nil {
+		return nil, fmt.Errorf("parse query: %w", err)
+	}
+	annotations, err := a.GetAnnotations()
+	if err != nil {
+		return nil, err
+	}
+
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	return models.SelectConversations(a.conversations, parsedQuery, annotations), nil
+}
+
+// GetSmartCollections evaluates the saved searches against the most recent load.
+func (a *App) GetSmartCollections() ([]models.SmartCollection, error) {
+	store, err := a.localStore()
+	if err != nil {
+		return nil, err
+	}
+	annotations, err := a.GetAnnotations()
+	if err != nil {
+		return nil, err
+	}
+
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	searches, err := models.LoadSavedSearches(store)
+	if err != nil {
+		return nil, err
+	}
+
+	return models.EvaluateSavedSearches(searches, a.conversations, annotations), nil
+}
+
+// SaveSearch saves query as a smart collection named name, replacing the query of a
+// collection with the same name, and returns the evaluated collections.
+func (a *App) SaveSearch(name string, query string) ([]models.SmartCollection, error) {
+	return a.updateSavedSearches(func(searches models.SavedSearches, now time.Time) (models.SavedSearches, error) {
+		return models.AddSavedSearch(searches, name, query, now)
+	})
+}
+
+// DeleteSavedSearch removes a smart collection and returns the remaining ones.
+func (a *App) DeleteSavedSearch(id string) ([]models.SmartCollection, error) {
+	return a.updateSavedSearches(func(searches models.SavedSearches, now time.Time) (models.SavedSearches, error) {
+		searches = models.RemoveSavedSearch(searches, id)
+		searches.UpdatedAt = now.UTC().Format(time.RFC3339)
+		return searches, nil
+	})
+}
+
+func (a *App) updateSavedSearches(update func(searches models.SavedSearches, now time.Time) (models.SavedSearches, error)) ([]models.SmartCollection, error) {
+	store, err := a.localStore()
+	if err != nil {
+		return nil, err
+	}
+
+	a.mu.Lock()
+	searches, err := models.LoadSavedSearches(store)
+	if err == nil {
+		searches, err = update(searches, time.Now())
+	}
+	if err == nil {
+		err = models.SaveSavedSearches(store, searches)
+	}
+	a.mu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+
+	return a.GetSmartCollections()
+}
+
+// localStore opens the store holding everything the app persists, once per session.
+func (a *App) localStore() (*models.LocalStore, error) {
+	a.storeOnce.Do(func() {
+		dir, err := a.localDataDir()
+		if err != nil {
+			a.storeErr = err
+			return
+		}
+		a.store, a.storeErr = models.OpenLocalStore(dir)
+	})
+
+	return a.store, a.storeErr
+}
+
+// localDataDir is where state that outlives a session, such as the deletion plan, is
+// kept: os.UserConfigDir()/chat-explorer.
+func (a *App) localDataDir() (string, error) {
+	if a.dataDir != "" {
+		return a.dataDir, nil
+	}
+
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("locate user config directory: %w", err)
+	}
+
+	return filepath.Join(configDir, appDataDirName), nil
+}
+
+// GetSnippets returns the code snippets of the most recent load that match query,
+// optionally restricted to one language.
+func (a *App) GetSnippets(query string, language string) []models.Snippet {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	return models.SearchSnippets(a.snippets, query, language)
+}
+
+// ExportSnippets asks for a directory and writes every snippet of the most recent load
+// into it, one folder per language. It returns the number of files written.
+func (a *App) ExportSnippets() (int, error) {
+	ctx := a.runtimeContext()
+	if ctx == nil {
+		return 0, fmt.Errorf("application is not initialized")
+	}
+
+	dir, err := runtime.OpenDirectoryDialog(ctx, runtime.OpenDialogOptions{
+		Title:                "Export code snippets to folder",
+		CanCreateDirectories: true,
+	})
+	if err != nil {
+		return 0, fmt.Errorf("open directory dialog: %w", err)
+	}
+	if strings.TrimSpace(dir) == "" {
+		return 0, nil
+	}
+
+	return a.ExportSnippetsToDirectory(dir)
+}
+
+// ExportSnippetsToDirectory writes every snippet of the most recent load below dir.
+func (a *App) ExportSnippetsToDirectory(dir string) (int, error) {
+	a.mu.RLock()
+	snippets := a.snippets
+	a.mu.RUnlock()
+
+	count, err := models.ExportSnippets(snippets, dir)
+	if err != nil {
+		return count, fmt.Errorf("export snippets to %s: %w", dir, err)
+	}
+
+	return count, nil
+}
+
+func (a *App) setLoadResult(path string, result models.ParseResult) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.sourcePath = strings.TrimSpace(path)
+	a.conversations = result.Conversations
+	a.warnings = result.Warnings
+	a.snippets = models.ExtractSnippets(result.Conversations)
+	a.links = models.ExtractLinks(result.Conversations)
 }
@@ -3,17 +3,70 @@ import {cleanup, fireEvent, render, screen, waitFor, within} from '@testing-libr
 import {afterEach, beforeEach, describe, expect, it, vi} from 'vitest';
 
 import App from './App';
-import {OpenConversationsFile} from '../wailsjs/go/main/App';
+import {
+    GetCustomInstructionsTimeline,
+    GetParseWarnings,
+    GetSession,
+    GetSmartCollections,
+    GetTopics,
+    OpenConversationsFile,
+    SaveSettings
+} from '../wailsjs/go/main/App';
+import {EventsOn} from '../wailsjs/runtime/runtime';
 import {formatConversationTimestamp, formatMessageTimestamp} from './utils/timestamps';
 import type {models} from '../wailsjs/go/models';
+import type {ConversationEntry} from './models/conversations';
 
 vi.mock('../wailsjs/go/main/App', () => ({
-    OpenConversationsFile: vi.fn()
+    ClearRecentFiles: vi.fn(),
+    DeleteSavedSearch: vi.fn(),
+    EnableEncryption: vi.fn(),
+    ExportRedacted: vi.fn(),
+    ExportSnippets: vi.fn(),
+    FilterConversations: vi.fn(),
+    GetAnnotations: vi.fn().mockResolvedValue({conversations: []}),
+    GetCustomInstructionsTimeline: vi.fn(),
+    GetDeletionPlan: vi.fn().mockResolvedValue({items: []}),
+    GetLinks: vi.fn().mockResolvedValue([]),
+    GetSmartCollections: vi.fn().mockResolvedValue([]),
+    GetSnippets: vi.fn().mockResolvedValue([]),
+    GetTopics: vi.fn().mockResolvedValue([]),
+    GetStoreStatus: vi.fn().mockResolvedValue({encrypted: false, locked: false}),
+    GetParseWarnings: vi.fn(),
+    GetRecentFiles: vi.fn().mockResolvedValue([]),
+    GetSession: vi.fn().mockResolvedValue({settings: undefined, entries: []}),
+    GetSettings: vi.fn().mockResolvedValue(undefined),
+    SaveSearch: vi.fn(),
+    SaveSettings: vi.fn().mockResolvedValue(undefined),
+    ScanSensitiveData: vi.fn().mockResolvedValue([]),
+    SetConversationNote: vi.fn(),
+    SetConversationTags: vi.fn(),
+    SetMessageNote: vi.fn(),
+    StarConversation: vi.fn(),
+    LoadConversationsFromPath: vi.fn(),
+    OpenConversationsFile: vi.fn(),
+    Unlock: vi.fn()
 }));
 
+vi.mock('../wailsjs/runtime/runtime', () => ({
+    BrowserOpenURL: vi.fn(),
+    EventsOn: vi.fn(() => () => undefined)
+}));
+
+const mockedEventsOn = vi.mocked(EventsOn);
+
+const mockedGetCustomInstructionsTimeline = vi.mocked(GetCustomInstructionsTimeline);
+const mockedGetParseWarnings = vi.mocked(GetParseWarnings);
+const mockedGetSession = vi.mocked(GetSession);
+const mockedGetSmartCollections = vi.mocked(GetSmartCollections);
+const mockedGetTopics = vi.mocked(GetTopics);
 const mockedOpenConversationsFile = vi.mocked(OpenConversationsFile);
+const mockedSaveSettings = vi.mocked(SaveSettings);
 
-type ConversationEntry = models.ConversationEntry;
+// Bound methods resolve to generated model classes; the fixtures are plain objects.
+function asGeneratedEntries(entries: ConversationEntry[]): models.ConversationEntry[] {
+    return entries as models.ConversationEntry[];
+}
 
 function escapeRegExp(value: string): string {
     return value.replace(/[.*+?^${}()|[\]\\]/g, '\\$&');
@@ -24,6 +77,7 @@ const sortableEntries: ConversationEntry[] = [
         conversationId: 'conv-zulu',
         conversationName: 'Zulu',
         conversationCreatedAt: '2026-01-04T00:00:00Z',
+        messageIndex: 0,
         speaker: 'assistant',
         message: 'Message Zulu',
         messageTimestamp: '2026-01-04T00:00:00Z'
@@ -32,6 +86,7 @@ const sortableEntries: ConversationEntry[] = [
         conversationId: 'conv-empty',
         conversationName: '   ',
         conversationCreatedAt: '2026-01-01T00:00:00Z',
+        messageIndex: 0,
         speaker: 'assistant',
         message: 'Message Untitled',
         messageTimestamp: '2026-01-01T00:00:00Z'
@@ -40,6 +95,7 @@ const sortableEntries: ConversationEntry[] = [
         conversationId: 'conv-symbol',
         conversationName: '#Hash',
         conversationCreatedAt: '2026-01-02T00:00:00Z',
+        messageIndex: 0,
         speaker: 'assistant',
         message: 'Message Symbol',
         messageTimestamp: '2026-01-02T00:00:00Z'
@@ -48,6 +104,7 @@ const sortableEntries: ConversationEntry[] = [
         conversationId: 'conv-utf8',
         conversationName: 'Álpha',
         conversationCreatedAt: '2026-01-03T00:00:00Z',
+        messageIndex: 0,
         speaker: 'assistant',
         message: 'Message UTF8',
         messageTimestamp: '2026-01-03T00:00:00Z'
@@ -57,6 +114,13 @@ const sortableEntries: ConversationEntry[] = [
 describe('App happy path', () => {
     beforeEach(() => {
         mockedOpenConversationsFile.mockReset();
+        mockedGetParseWarnings.mockReset();
+        mockedGetParseWarnings.mockResolvedValue([]);
+        mockedGetCustomInstructionsTimeline.mockReset();
+        mockedGetCustomInstructionsTimeline.mockResolvedValue([]);
+        mockedGetSmartCollections.mockResolvedValue([]);
+        mockedGetSession.mockResolvedValue({entries: []} as unknown as models.Session);
+        mockedSaveSettings.mockClear();
     });
 
     afterEach(() => {
@@ -71,11 +135,12 @@ describe('App happy path', () => {
     });
 
     it('loads conversations collapsed by default and expands on demand', async () => {
-        mockedOpenConversationsFile.mockResolvedValue([
+        mockedOpenConversationsFile.mockResolvedValue(asGeneratedEntries([
             {
                 conversationId: 'conv-1',
                 conversationName: 'Setup',
                 conversationCreatedAt: '2025-09-19T04:41:47.942021Z',
+                messageIndex: 0,
                 speaker: 'human',
                 message: 'How do I export data?',
                 messageTimestamp: '2025-09-19T04:41:47.942021Z'
@@ -84,6 +149,7 @@ describe('App happy path', () => {
                 conversationId: 'conv-1',
                 conversationName: 'Setup',
                 conversationCreatedAt: '2025-09-19T04:41:47.942021Z',
+                messageIndex: 1,
                 speaker: 'assistant',
                 message: 'Open Settings and click Export data.',
                 messageTimestamp: '2025-09-19T04:42:10.100000Z'
@@ -92,11 +158,12 @@ describe('App happy path', () => {
                 conversationId: 'conv-2',
                 conversationName: 'Another chat',
                 conversationCreatedAt: '2025-09-19T05:01:00.000000Z',
+                messageIndex: 0,
                 speaker: 'assistant',
                 message: 'Separate conversation.',
                 messageTimestamp: '2025-09-19T05:01:00.000000Z'
             }
-        ]);
+        ]));
 
         render(<App />);
 
@@ -134,7 +201,7 @@ describe('App happy path', () => {
     });
 
     it('defaults to created oldest sorting and cycles all sort modes', async () => {
-        mockedOpenConversationsFile.mockResolvedValue(sortableEntries);
+        mockedOpenConversationsFile.mockResolvedValue(asGeneratedEntries(sortableEntries));
 
         render(<App />);
         fireEvent.click(screen.getByRole('button', {name: 'Open conversations export'}));
@@ -170,7 +237,7 @@ describe('App happy path', () => {
     it.each(Array.from({length: 16}, (_, mask) => mask))(
         'keeps expanded states and visible messages when sorting (mask %s)',
         async (mask) => {
-            mockedOpenConversationsFile.mockResolvedValue(sortableEntries);
+            mockedOpenConversationsFile.mockResolvedValue(asGeneratedEntries(sortableEntries));
 
             render(<App />);
             fireEvent.click(screen.getByRole('button', {name: 'Open conversations export'}));
@@ -225,17 +292,18 @@ describe('App happy path', () => {
 
     it('collapses all conversations again when a new file is loaded', async () => {
         mockedOpenConversationsFile
-            .mockResolvedValueOnce(sortableEntries)
-            .mockResolvedValueOnce([
+            .mockResolvedValueOnce(asGeneratedEntries(sortableEntries))
+            .mockResolvedValueOnce(asGeneratedEntries([
                 {
                     conversationId: 'conv-next',
                     conversationName: 'Next export',
                     conversationCreatedAt: '2026-02-01T00:00:00Z',
+                    messageIndex: 0,
                     speaker: 'assistant',
                     message: 'Fresh message',
                     messageTimestamp: '2026-02-01T00:00:00Z'
                 }
-            ]);
+            ]));
 
         render(<App />);
         fireEvent.click(screen.getByRole('button', {name: 'Open conversations export'}));
@@ -257,6 +325,158 @@ describe('App happy path', () => {
         expect(screen.queryByText('Fresh message')).toBeNull();
     });
 
+    it('shows diagnostics for conversations skipped during parsing', async () => {
+        mockedOpenConversationsFile.mockResolvedValue(asGeneratedEntries(sortableEntries));
+        mockedGetParseWarnings.mockResolvedValue([
+            {index: 2, conversationId: 'conv-broken', message: 'decode claude conversation: bad chat_messages'}
+        ]);
+
+        render(<App />);
+        fireEvent.click(screen.getByRole('button', {name: 'Open conversations export'}));
+
+        await waitFor(() => {
+            expect(screen.getByRole('region', {name: 'Load diagnostics'})).toBeTruthy();
+        });
+
+        expect(screen.getByText('1 conversation could not be parsed and was skipped.')).toBeTruthy();
+        expect(screen.getByText('Index 2 · conv-broken')).toBeTruthy();
+        expect(screen.getAllByTestId('conversation-title').length).toBe(4);
+    });
+
+    it('shows the custom instructions found in the export', async () => {
+        mockedOpenConversationsFile.mockResolvedValue(asGeneratedEntries(sortableEntries));
+        mockedGetCustomInstructionsTimeline.mockResolvedValue([
+            {userInstructions: 'Be concise.', firstSeen: '', lastSeen: '', conversationIds: ['conv-zulu']}
+        ]);
+
+        render(<App />);
+        fireEvent.click(screen.getByRole('button', {name: 'Open conversations export'}));
+
+        await waitFor(() => {
+            expect(screen.getByRole('region', {name: 'Custom instructions'})).toBeTruthy();
+        });
+
+        expect(screen.getByText('1 version of custom instructions found in this export.')).toBeTruthy();
+        expect(screen.getByText('Be concise.')).toBeTruthy();
+    });
+
+    it('hides tool activity when requested', async () => {
+        mockedOpenConversationsFile.mockResolvedValue(asGeneratedEntries([
+            {conversationId: 'conv-tools', conversationName: 'Tools', conversationCreatedAt: '', messageIndex: 0, speaker: 'user', message: 'Weather?', messageTimestamp: '', kind: 'prompt'},
+            {conversationId: 'conv-tools', conversationName: 'Tools', conversationCreatedAt: '', messageIndex: 1, speaker: 'assistant', message: 'search oslo', messageTimestamp: '', kind: 'tool_call', recipient: 'web.run'},
+            {conversationId: 'conv-tools', conversationName: 'Tools', conversationCreatedAt: '', messageIndex: 2, speaker: 'tool', message: '4°C', messageTimestamp: '', kind: 'tool_result', toolName: 'web.run'},
+            {conversationId: 'conv-tools', conversationName: 'Tools', conversationCreatedAt: '', messageIndex: 3, speaker: 'assistant', message: 'It is 4°C.', messageTimestamp: '', kind: 'answer'}
+        ]));
+
+        render(<App />);
+        fireEvent.click(screen.getByRole('button', {name: 'Open conversations export'}));
+
+        await waitFor(() => {
+            expect(screen.getByRole('button', {name: /Tools/i})).toBeTruthy();
+        });
+        fireEvent.click(screen.getByRole('button', {name: /Tools/i}));
+        expect(screen.getByText('search oslo')).toBeTruthy();
+
+        fireEvent.click(screen.getByRole('checkbox', {name: 'Hide tool activity'}));
+
+        expect(screen.queryByText('search oslo')).toBeNull();
+        expect(screen.queryByText('4°C')).toBeNull();
+        expect(screen.getByText('Weather?')).toBeTruthy();
+        expect(screen.getByText('It is 4°C.')).toBeTruthy();
+    });
+
+    it('groups conversations by topic when requested', async () => {
+        mockedOpenConversationsFile.mockResolvedValue(asGeneratedEntries(sortableEntries));
+        mockedGetTopics.mockResolvedValue([
+            {
+                label: 'greek, letters',
+                keywords: ['greek', 'letters'],
+                conversations: [
+                    {conversationId: 'conv-utf8', conversationName: 'Álpha'},
+                    {conversationId: 'conv-zulu', conversationName: 'Zulu'}
+                ]
+            }
+        ] as models.Topic[]);
+
+        render(<App />);
+        fireEvent.click(screen.getByRole('button', {name: 'Open conversations export'}));
+
+        await waitFor(() => {
+            expect(screen.getAllByTestId('conversation-title').length).toBe(4);
+        });
+        fireEvent.click(screen.getByRole('checkbox', {name: 'Group by topic'}));
+
+        await waitFor(() => {
+            expect(screen.getByText('greek, letters (2)')).toBeTruthy();
+        });
+        expect(mockedGetTopics).toHaveBeenCalledWith(0);
+        const topicTitles = within(screen.getByRole('region', {name: 'Topic: greek, letters'}))
+            .getAllByTestId('conversation-title')
+            .map((element) => element.textContent);
+        expect(topicTitles).toEqual(['Álpha', 'Zulu']);
+        expect(screen.getByText('Other (2)')).toBeTruthy();
+    });
+
+    it('restores the last session and saves setting changes', async () => {
+        mockedGetSession.mockResolvedValue({
+            settings: {
+                lastOpenedPaths: ['/exports/conversations.json'],
+                sortMode: 'name-desc',
+                expandedConversations: ['id:conv-zulu'],
+                window: {},
+                theme: 'dark'
+            },
+            sourcePath: '/exports/conversations.json',
+            entries: sortableEntries
+        } as unknown as models.Session);
+
+        render(<App />);
+
+        await waitFor(() => {
+            expect(screen.getAllByTestId('conversation-title').length).toBe(4);
+        });
+        const titles = screen.getAllByTestId('conversation-title').map((element) => element.textContent);
+        expect(titles).toEqual(['Untitled conversation', 'Zulu', 'Álpha', '#Hash']);
+        expect(screen.getByRole('button', {name: /Zulu/}).getAttribute('aria-expanded')).toBe('true');
+        expect(screen.getByText('Message Zulu')).toBeTruthy();
+        expect(mockedOpenConversationsFile).not.toHaveBeenCalled();
+
+        fireEvent.click(screen.getByRole('button', {name: 'Theme: Dark'}));
+        expect(mockedSaveSettings).toHaveBeenLastCalledWith(expect.objectContaining({theme: 'system', sortMode: 'name-desc'}));
+
+        fireEvent.click(screen.getByRole('button', {name: /Álpha/}));
+        expect(mockedSaveSettings).toHaveBeenLastCalledWith(
+            expect.objectContaining({expandedConversations: ['id:conv-zulu', 'id:conv-utf8']})
+        );
+
+        fireEvent.click(screen.getByRole('button', {name: /sorted by/i}));
+        expect(mockedSaveSettings).toHaveBeenLastCalledWith(expect.objectContaining({sortMode: 'created-asc'}));
+    });
+
+    it('limits the list to a smart collection', async () => {
+        mockedOpenConversationsFile.mockResolvedValue(asGeneratedEntries(sortableEntries));
+        mockedGetSmartCollections.mockResolvedValue([
+            {id: 'greek', name: 'Greek', query: 'alpha', count: 1, conversations: [{conversationId: 'conv-utf8'}]}
+        ] as models.SmartCollection[]);
+
+        render(<App />);
+        fireEvent.click(screen.getByRole('button', {name: 'Open conversations export'}));
+
+        await waitFor(() => {
+            expect(screen.getByRole('button', {name: 'Greek (1)'})).toBeTruthy();
+        });
+        expect(screen.getAllByTestId('conversation-title').length).toBe(4);
+
+        fireEvent.click(screen.getByRole('button', {name: 'Greek (1)'}));
+
+        const titles = screen.getAllByTestId('conversation-title').map((element) => element.textContent);
+        expect(titles).toEqual(['Álpha']);
+        expect(screen.getByText('Showing Greek (1)')).toBeTruthy();
+
+        fireEvent.click(screen.getByRole('button', {name: 'Clear filter'}));
+        expect(screen.getAllByTestId('conversation-title').length).toBe(4);
+    });
+
     it('displays error message when loading fails', async () => {
         mockedOpenConversationsFile.mockRejectedValue(new Error('Failed to read file'));
 
@@ -296,6 +516,7 @@ describe('App happy path', () => {
                 conversationId: 'conv-1',
                 conversationName: 'Loaded',
                 conversationCreatedAt: '2025-09-19T04:41:47.942021Z',
+                messageIndex: 0,
                 speaker: 'human',
                 message: 'Content',
                 messageTimestamp: '2025-09-19T04:41:47.942021Z'
@@ -309,4 +530,48 @@ describe('App happy path', () => {
         expect(button.textContent).toBe('Open conversations export');
         expect(screen.getByText(/Last load:/)).toBeTruthy();
     });
+
+    it('shows the session once the startup restore finished', async () => {
+        mockedEventsOn.mockClear();
+        render(<App />);
+
+        const sessionRestoredCall = mockedEventsOn.mock.calls.find(([eventName]) => eventName === 'session:restored');
+        expect(sessionRestoredCall).toBeTruthy();
+        await waitFor(() => {
+            expect(mockedGetSession).toHaveBeenCalled();
+        });
+        expect(screen.queryAllByTestId('conversation-title').length).toBe(0);
+
+        mockedGetSession.mockResolvedValue({
+            sourcePath: '/exports/conversations.json',
+            entries: [sortableEntries[0]]
+        } as unknown as models.Session);
+        sessionRestoredCall![1]();
+
+        await waitFor(() => {
+            expect(screen.getByText('1 messages loaded across 1 conversations.')).toBeTruthy();
+        });
+    });
+
+    it('shows exports opened from outside the app', async () => {
+        mockedEventsOn.mockClear();
+        render(<App />);
+
+        const exportOpenedCall = mockedEventsOn.mock.calls.find(([eventName]) => eventName === 'export:opened');
+        expect(exportOpenedCall).toBeTruthy();
+        const onExportOpened = exportOpenedCall![1];
+
+        onExportOpened({path: '/exports/notes.txt', error: 'drop a conversations export (.json, .zip)'});
+        await waitFor(() => {
+            expect(screen.getByText('drop a conversations export (.json, .zip)')).toBeTruthy();
+        });
+
+        mockedGetSession.mockResolvedValue({entries: [sortableEntries[0]]} as unknown as models.Session);
+        onExportOpened({path: '/exports/conversations.json'});
+
+        await waitFor(() => {
+            expect(screen.getByText('1 messages loaded across 1 conversations.')).toBeTruthy();
+        });
+        expect(screen.queryByText('drop a conversations export (.json, .zip)')).toBeNull();
+    });
 });
@@ -1,83 +1,323 @@
-import React, {useMemo, useState} from 'react';
+import React, {useCallback, useEffect, useMemo, useRef, useState} from 'react';
 import {
     Alert,
     Box,
     Button,
     Container,
     CssBaseline,
+    FormControlLabel,
     Paper,
     Stack,
+    Switch,
     ThemeProvider,
     Typography,
-    createTheme
+    createTheme,
+    useMediaQuery
 } from '@mui/material';
-import {OpenConversationsFile} from "../wailsjs/go/main/App";
+import {
+    GetAnnotations,
+    GetCustomInstructionsTimeline,
+    GetLinks,
+    GetParseWarnings,
+    GetSession,
+    GetSettings,
+    GetTopics,
+    LoadConversationsFromPath,
+    OpenConversationsFile,
+    SaveSettings
+} from "../wailsjs/go/main/App";
 import type {models} from "../wailsjs/go/models";
+import {EventsOn} from "../wailsjs/runtime/runtime";
 import {
     defaultConversationSort,
+    filterThreadsToConversations,
     getConversationSortLabel,
     groupConversationEntries,
+    groupThreadsByTopic,
+    hideToolChatter,
     nextConversationSort,
+    parseConversationSort,
     sortConversations,
-    type ConversationSort
+    type ConversationEntry,
+    type Topic
 } from './models/conversations';
 import {ConversationList} from './components/ConversationList';
+import {emptyAnnotations, type Annotations} from './components/ConversationAnnotations';
+import {CustomInstructionsPanel} from './components/CustomInstructionsPanel';
+import {DeletionPlanPanel} from './components/DeletionPlanPanel';
+import {LibraryLock} from './components/LibraryLock';
+import {DiagnosticsPanel} from './components/DiagnosticsPanel';
+import {LinksPanel, type Link} from './components/LinksPanel';
+import {RecentFilesMenu} from './components/RecentFilesMenu';
+import {SensitiveDataPanel} from './components/SensitiveDataPanel';
+import {SmartCollectionsPanel, type ConversationFilter} from './components/SmartCollectionsPanel';
+import {SnippetsPanel} from './components/SnippetsPanel';
+
+type ParseWarning = models.ParseWarning;
+type CustomInstructionsVersion = models.CustomInstructionsVersion;
+type Settings = Omit<models.Settings, 'convertValues'>;
+type ThemePreference = 'system' | 'light' | 'dark';
+
+// ExportOpened is sent by the backend when an export was opened from outside the app,
+// by "open with" or by a file dropped on the window.
+type ExportOpened = {
+    path: string;
+    error?: string;
+};
 
-type ConversationEntry = models.ConversationEntry;
+const defaultSettings: Settings = {
+    lastOpenedPaths: [],
+    sortMode: defaultConversationSort,
+    expandedConversations: [],
+    window: {},
+    theme: 'system'
+};
 
-const lightTheme = createTheme({
-    palette: {
-        mode: 'light',
-        primary: {
-            main: '#5f6b67'
+const themePreferences: ThemePreference[] = ['system', 'light', 'dark'];
+
+const themePreferenceLabels: Record<ThemePreference, string> = {
+    system: 'Theme: System',
+    light: 'Theme: Light',
+    dark: 'Theme: Dark'
+};
+
+function parseThemePreference(value: string | undefined): ThemePreference {
+    return themePreferences.find((preference) => preference === value) ?? 'system';
+}
+
+function createAppTheme(mode: 'light' | 'dark') {
+    return createTheme({
+        palette: mode === 'light'
+            ? {
+                mode,
+                primary: {
+                    main: '#5f6b67'
+                },
+                background: {
+                    default: '#f6f5f0',
+                    paper: '#ffffff'
+                }
+            }
+            : {
+                mode,
+                primary: {
+                    main: '#a9b8b3'
+                },
+                background: {
+                    default: '#1b2636',
+                    paper: '#223044'
+                }
+            },
+        shape: {
+            borderRadius: 10
         },
-        background: {
-            default: '#f6f5f0',
-            paper: '#ffffff'
+        typography: {
+            fontFamily: '"Nunito", -apple-system, BlinkMacSystemFont, "Segoe UI", sans-serif'
         }
-    },
-    shape: {
-        borderRadius: 10
-    },
-    typography: {
-        fontFamily: '"Nunito", -apple-system, BlinkMacSystemFont, "Segoe UI", sans-serif'
-    }
-});
+    });
+}
 
 function App() {
     const [entries, setEntries] = useState<ConversationEntry[]>([]);
+    const [parseWarnings, setParseWarnings] = useState<ParseWarning[]>([]);
+    const [customInstructions, setCustomInstructions] = useState<CustomInstructionsVersion[]>([]);
+    const [links, setLinks] = useState<Link[]>([]);
     const [error, setError] = useState('');
     const [isLoading, setIsLoading] = useState(false);
     const [lastLoadedAt, setLastLoadedAt] = useState('');
-    const [conversationSort, setConversationSort] = useState<ConversationSort>(defaultConversationSort);
+    const [settings, setSettings] = useState<Settings>(defaultSettings);
     const [conversationSetVersion, setConversationSetVersion] = useState(0);
+    const [hideToolActivity, setHideToolActivity] = useState(false);
+    const [libraryVersion, setLibraryVersion] = useState(0);
+    const [sessionVersion, setSessionVersion] = useState(0);
+    const [groupByTopic, setGroupByTopic] = useState(false);
+    const [topics, setTopics] = useState<Topic[]>([]);
+    const [annotations, setAnnotations] = useState<Annotations>(emptyAnnotations);
+    const [conversationFilter, setConversationFilter] = useState<ConversationFilter | null>(null);
+    const settingsRef = useRef(settings);
+    settingsRef.current = settings;
+    const hasRestoredSessionRef = useRef(false);
+
+    const conversationSort = parseConversationSort(settings.sortMode);
+    const themePreference = parseThemePreference(settings.theme);
+    const prefersDarkMode = useMediaQuery('(prefers-color-scheme: dark)');
+    const themeMode = themePreference === 'dark' || (themePreference === 'system' && prefersDarkMode) ? 'dark' : 'light';
+    const theme = useMemo(() => createAppTheme(themeMode), [themeMode]);
+
+    // Settings are saved on every change. Saving is best effort: while the library is
+    // locked the change only lasts for this session.
+    const saveSettings = useCallback((change: Partial<Settings>) => {
+        const nextSettings = {...settingsRef.current, ...change};
+        settingsRef.current = nextSettings;
+        setSettings(nextSettings);
+        // Bound methods take generated model classes; the settings are plain objects.
+        SaveSettings(nextSettings as models.Settings).catch(() => undefined);
+    }, []);
+
+    const handlePanelToggle = useCallback((panelKey: string, isExpanded: boolean) => {
+        const otherPanelKeys = settingsRef.current.expandedConversations.filter((key) => key !== panelKey);
+        saveSettings({expandedConversations: isExpanded ? [...otherPanelKeys, panelKey] : otherPanelKeys});
+    }, [saveSettings]);
 
-    const loadConversations = async () => {
+    // showLoadedEntries fetches what the backend derived from a load and shows it all at
+    // once, with the settings the load updated.
+    const showLoadedEntries = async (loadedEntries: ConversationEntry[]) => {
+        const loadedWarnings = await GetParseWarnings();
+        const loadedCustomInstructions = await GetCustomInstructionsTimeline();
+        const loadedLinks = await GetLinks();
+        const loadedSettings = await GetSettings().catch(() => settingsRef.current);
+        setSettings(loadedSettings ?? settingsRef.current);
+        setEntries(loadedEntries);
+        setParseWarnings(loadedWarnings ?? []);
+        setCustomInstructions(loadedCustomInstructions ?? []);
+        setLinks(loadedLinks ?? []);
+        setConversationSetVersion((previousVersion) => previousVersion + 1);
+        setLastLoadedAt(new Date().toLocaleTimeString());
+    };
+
+    // The backend reopens the last session's export in the background on startup and
+    // announces when it is done. The listener is registered before the first fetch, so
+    // a restore finishing in between is not missed.
+    useEffect(() => EventsOn('session:restored', () => {
+        setSessionVersion((previousVersion) => previousVersion + 1);
+    }), []);
+
+    // The session is fetched on mount, once the startup restore finished, and after an
+    // unlock makes the settings naming the last export readable.
+    useEffect(() => {
+        let isCurrent = true;
+        GetSession()
+            .then(async (session) => {
+                if (!isCurrent || !session) {
+                    return;
+                }
+                setSettings(session.settings ?? defaultSettings);
+                if (hasRestoredSessionRef.current || (session.entries ?? []).length === 0) {
+                    if (!hasRestoredSessionRef.current && session.restoreError) {
+                        setError(session.restoreError);
+                    }
+                    return;
+                }
+                hasRestoredSessionRef.current = true;
+                await showLoadedEntries(session.entries);
+            })
+            .catch(() => undefined);
+
+        return () => {
+            isCurrent = false;
+        };
+    }, [libraryVersion, sessionVersion]);
+
+    const showLoadError = (message: string) => {
+        setEntries([]);
+        setParseWarnings([]);
+        setCustomInstructions([]);
+        setLinks([]);
+        setConversationSetVersion((previousVersion) => previousVersion + 1);
+        setError(message);
+    };
+
+    const openExport = async (load: () => Promise<ConversationEntry[]>) => {
         setIsLoading(true);
         setError('');
+        hasRestoredSessionRef.current = true;
 
         try {
-            const loadedEntries = await OpenConversationsFile();
-            setEntries(loadedEntries ?? []);
-            setConversationSetVersion((previousVersion) => previousVersion + 1);
-            setLastLoadedAt(new Date().toLocaleTimeString());
+            const loadedEntries = await load();
+            await showLoadedEntries(loadedEntries ?? []);
         } catch (loadError: unknown) {
-            const message = loadError instanceof Error ? loadError.message : 'Failed to open conversations export.';
-            setEntries([]);
-            setConversationSetVersion((previousVersion) => previousVersion + 1);
-            setError(message);
+            showLoadError(loadError instanceof Error ? loadError.message : 'Failed to open conversations export.');
         } finally {
             setIsLoading(false);
         }
     };
 
+    const loadConversations = () => openExport(OpenConversationsFile);
+
+    const loadRecentFile = (path: string) => openExport(() => LoadConversationsFromPath(path));
+
+    // Exports opened by "open with" or a drop are already loaded by the backend, which
+    // reports the outcome.
+    const showOpenedExportRef = useRef<(opened: ExportOpened) => Promise<void>>(async () => undefined);
+    showOpenedExportRef.current = async (opened: ExportOpened) => {
+        hasRestoredSessionRef.current = true;
+        if (opened.error) {
+            showLoadError(opened.error);
+            return;
+        }
+
+        setError('');
+        const session = await GetSession();
+        await showLoadedEntries(session?.entries ?? []);
+    };
+
+    useEffect(() => EventsOn('export:opened', (opened: ExportOpened) => {
+        showOpenedExportRef.current(opened).catch(() => undefined);
+    }), []);
+
+    const visibleEntries = useMemo(
+        () => (hideToolActivity ? hideToolChatter(entries) : entries),
+        [entries, hideToolActivity]
+    );
     const groupedConversations = useMemo(
-        () => groupConversationEntries(entries),
-        [entries]
+        () => groupConversationEntries(visibleEntries),
+        [visibleEntries]
     );
-    const conversations = useMemo(
-        () => sortConversations(groupedConversations, conversationSort),
-        [groupedConversations, conversationSort]
+    const conversations = useMemo(() => {
+        const sortedConversations = sortConversations(groupedConversations, conversationSort);
+        return conversationFilter
+            ? filterThreadsToConversations(sortedConversations, conversationFilter.conversations)
+            : sortedConversations;
+    }, [groupedConversations, conversationSort, conversationFilter]);
+
+    // Annotations are refetched after each load so message notes follow their messages,
+    // and after an unlock makes them readable.
+    useEffect(() => {
+        let isCurrent = true;
+        GetAnnotations()
+            .then((loadedAnnotations) => {
+                if (isCurrent) {
+                    setAnnotations(loadedAnnotations ?? emptyAnnotations);
+                }
+            })
+            .catch(() => {
+                if (isCurrent) {
+                    setAnnotations(emptyAnnotations);
+                }
+            });
+
+        return () => {
+            isCurrent = false;
+        };
+    }, [conversationSetVersion, libraryVersion]);
+
+    // Clustering runs over the whole export, so it is only requested once grouping is on.
+    useEffect(() => {
+        if (!groupByTopic) {
+            return;
+        }
+
+        let isCurrent = true;
+        GetTopics(0)
+            .then((loadedTopics) => {
+                if (isCurrent) {
+                    setTopics(loadedTopics ?? []);
+                }
+            })
+            .catch(() => {
+                if (isCurrent) {
+                    setTopics([]);
+                }
+            });
+
+        return () => {
+            isCurrent = false;
+        };
+    }, [groupByTopic, conversationSetVersion]);
+
+    const topicGroups = useMemo(
+        () => (groupByTopic ? groupThreadsByTopic(conversations, topics) : []),
+        [groupByTopic, conversations, topics]
     );
 
     const statusLabel = entries.length === 0
@@ -85,7 +325,7 @@ function App() {
         : `${entries.length} messages loaded across ${groupedConversations.length} conversations.`;
 
     return (
-        <ThemeProvider theme={lightTheme}>
+        <ThemeProvider theme={theme}>
             <CssBaseline />
             <Box sx={{minHeight: '100%', py: {xs: 2, md: 4}}}>
                 <Container maxWidth="lg" sx={{height: '100%', display: 'flex', flexDirection: 'column', gap: 2}}>
@@ -96,8 +336,8 @@ function App() {
                                     Chat Explorer
                                 </Typography>
                                 <Typography variant="body1" color="text.secondary">
-                                    Load a Claude or ChatGPT conversations export (<code>.json</code> or <code>.zip</code>) and review
-                                    speaker/message history.
+                                    Load a Claude, ChatGPT, Gemini (Google Takeout), Copilot or Perplexity conversations export, or an OpenAI/Anthropic API log (<code>.json</code>, <code>.jsonl</code>, <code>.csv</code>, <code>.zip</code>, <code>.gz</code> or <code>.zst</code>) and review
+                                    speaker/message history. Exports can also be dropped on the window.
                                 </Typography>
                             </Box>
 
@@ -105,6 +345,7 @@ function App() {
                                 <Button variant="contained" onClick={loadConversations} disabled={isLoading}>
                                     {isLoading ? 'Loading...' : 'Open conversations export'}
                                 </Button>
+                                <RecentFilesMenu disabled={isLoading} onOpen={loadRecentFile} />
                                 <Typography variant="body2" color="text.secondary">
                                     {statusLabel}
                                 </Typography>
@@ -113,6 +354,15 @@ function App() {
                                         Last load: {lastLoadedAt}
                                     </Typography>
                                 )}
+                                <LibraryLock onUnlocked={() => setLibraryVersion((version) => version + 1)} />
+                                <Button
+                                    size="small"
+                                    onClick={() => saveSettings({
+                                        theme: themePreferences[(themePreferences.indexOf(themePreference) + 1) % themePreferences.length]
+                                    })}
+                                >
+                                    {themePreferenceLabels[themePreference]}
+                                </Button>
                             </Stack>
 
                             {error && (
@@ -123,17 +373,61 @@ function App() {
                         </Stack>
                     </Paper>
 
+                    <DiagnosticsPanel warnings={parseWarnings} />
+
+                    <CustomInstructionsPanel versions={customInstructions} />
+
+                    <SensitiveDataPanel conversationSetVersion={conversationSetVersion} hasConversations={entries.length > 0} />
+
+                    <DeletionPlanPanel
+                        key={libraryVersion}
+                        conversationSetVersion={conversationSetVersion}
+                        hasConversations={entries.length > 0}
+                    />
+
+                    <SnippetsPanel conversationSetVersion={conversationSetVersion} />
+
+                    <LinksPanel links={links} />
+
+                    <SmartCollectionsPanel
+                        conversationSetVersion={conversationSetVersion}
+                        hasConversations={entries.length > 0}
+                        annotations={annotations}
+                        filter={conversationFilter}
+                        onFilterChange={setConversationFilter}
+                    />
+
                     <Paper
                         variant="outlined"
                         role="list"
                         aria-label="Conversations"
                         sx={{p: 2, flex: 1, minHeight: 0, overflowY: 'auto'}}
                     >
-                        <Stack direction="row" justifyContent="flex-end" sx={{mb: 1.5}}>
+                        <Stack direction="row" justifyContent="flex-end" alignItems="center" spacing={1} sx={{mb: 1.5}}>
+                            <FormControlLabel
+                                control={
+                                    <Switch
+                                        size="small"
+                                        checked={hideToolActivity}
+                                        onChange={(event) => setHideToolActivity(event.target.checked)}
+                                    />
+                                }
+                                label={<Typography variant="body2">Hide tool activity</Typography>}
+                            />
+                            <FormControlLabel
+                                control={
+                                    <Switch
+                                        size="small"
+                                        checked={groupByTopic}
+                                        onChange={(event) => setGroupByTopic(event.target.checked)}
+                                    />
+                                }
+                                label={<Typography variant="body2">Group by topic</Typography>}
+                            />
                             <Button
                                 size="small"
                                 variant="outlined"
-                                onClick={() => setConversationSort((previousSort) => nextConversationSort(previousSort))}
+                                onClick={() => saveSettings({sortMode: nextConversationSort(conversationSort)})}
                             >
                                 {getConversationSortLabel(conversationSort)}
                             </Button>
@@ -143,7 +437,34 @@ function App() {
                             <Typography color="text.secondary">Choose a file to start exploring conversations.</Typography>
                         )}
 
-                        <ConversationList conversations={conversations} conversationSetVersion={conversationSetVersion} />
+                        {groupByTopic ? (
+                            <Stack spacing={2}>
+                                {topicGroups.map((topicGroup, topicIndex) => (
+                                    <Box key={`${topicIndex}-${topicGroup.label}`} component="section" aria-label={`Topic: ${topicGroup.label}`}>
+                                        <Typography variant="subtitle2" sx={{mb: 1}}>
+                                            {topicGroup.label} ({topicGroup.threads.length})
+                                        </Typography>
+                                        <ConversationList
+                                            conversations={topicGroup.threads}
+                                            conversationSetVersion={conversationSetVersion}
+                                            annotations={annotations}
+                                            onAnnotationsChange={setAnnotations}
+                                            restoredPanelKeys={settings.expandedConversations}
+                                            onPanelToggle={handlePanelToggle}
+                                        />
+                                    </Box>
+                                ))}
+                            </Stack>
+                        ) : (
+                            <ConversationList
+                                conversations={conversations}
+                                conversationSetVersion={conversationSetVersion}
+                                annotations={annotations}
+                                onAnnotationsChange={setAnnotations}
+                                restoredPanelKeys={settings.expandedConversations}
+                                onPanelToggle={handlePanelToggle}
+                            />
+                        )}
                     </Paper>
                 </Container>
             </Box>
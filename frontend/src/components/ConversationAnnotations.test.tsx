@@ -0,0 +1,134 @@
+import React from 'react';
+import {cleanup, fireEvent, render, screen, waitFor} from '@testing-library/react';
+import {afterEach, beforeEach, describe, expect, it, vi} from 'vitest';
+
+import {ConversationAnnotationEditor, MessageNoteEditor, findConversationAnnotation} from './ConversationAnnotations';
+import {SetConversationNote, SetConversationTags, SetMessageNote, StarConversation} from '../../wailsjs/go/main/App';
+import type {models} from '../../wailsjs/go/models';
+
+vi.mock('../../wailsjs/go/main/App', () => ({
+    SetConversationNote: vi.fn(),
+    SetConversationTags: vi.fn(),
+    SetMessageNote: vi.fn(),
+    StarConversation: vi.fn()
+}));
+
+const mockedSetConversationNote = vi.mocked(SetConversationNote);
+const mockedSetConversationTags = vi.mocked(SetConversationTags);
+const mockedSetMessageNote = vi.mocked(SetMessageNote);
+const mockedStarConversation = vi.mocked(StarConversation);
+
+const starredAnnotation = {
+    provider: 'claude',
+    conversationId: 'conv-1',
+    starred: true,
+    tags: ['japan', 'travel'],
+    note: 'Book hotels'
+};
+
+// Bound methods resolve to generated model classes; the fixtures are plain objects.
+function asAnnotations(conversations: object[]): models.Annotations {
+    return {conversations} as models.Annotations;
+}
+
+describe('findConversationAnnotation', () => {
+    it('matches on provider and conversation id', () => {
+        const annotations = {conversations: [starredAnnotation]};
+
+        expect(findConversationAnnotation(annotations, 'claude', 'conv-1')).toBe(starredAnnotation);
+        expect(findConversationAnnotation(annotations, 'chatgpt', 'conv-1')).toBeUndefined();
+    });
+});
+
+describe('ConversationAnnotationEditor', () => {
+    beforeEach(() => {
+        mockedSetConversationNote.mockReset();
+        mockedSetConversationTags.mockReset();
+        mockedStarConversation.mockReset();
+    });
+
+    afterEach(() => {
+        cleanup();
+    });
+
+    it('stars the conversation and saves tags and notes on blur', async () => {
+        const onChange = vi.fn();
+        mockedStarConversation.mockResolvedValue(asAnnotations([starredAnnotation]));
+        mockedSetConversationTags.mockResolvedValue(asAnnotations([starredAnnotation]));
+        mockedSetConversationNote.mockResolvedValue(asAnnotations([starredAnnotation]));
+
+        render(<ConversationAnnotationEditor provider="claude" conversationId="conv-1" onChange={onChange} />);
+
+        fireEvent.click(screen.getByRole('button', {name: '☆ Star'}));
+        await waitFor(() => {
+            expect(onChange).toHaveBeenCalledTimes(1);
+        });
+        expect(mockedStarConversation).toHaveBeenCalledWith('claude', 'conv-1', true);
+
+        const tagsField = screen.getByLabelText('Tags');
+        fireEvent.change(tagsField, {target: {value: 'travel, japan, '}});
+        fireEvent.blur(tagsField);
+        await waitFor(() => {
+            expect(mockedSetConversationTags).toHaveBeenCalledWith('claude', 'conv-1', ['travel', 'japan']);
+        });
+
+        const noteField = screen.getByLabelText('Conversation note');
+        fireEvent.blur(noteField);
+        expect(mockedSetConversationNote).not.toHaveBeenCalled();
+        fireEvent.change(noteField, {target: {value: 'Book hotels'}});
+        fireEvent.blur(noteField);
+        await waitFor(() => {
+            expect(mockedSetConversationNote).toHaveBeenCalledWith('claude', 'conv-1', 'Book hotels');
+        });
+    });
+
+    it('shows a starred conversation and save failures', async () => {
+        mockedStarConversation.mockRejectedValue(new Error('local library is locked'));
+
+        render(
+            <ConversationAnnotationEditor provider="claude" conversationId="conv-1" annotation={starredAnnotation} onChange={vi.fn()} />
+        );
+
+        expect((screen.getByLabelText('Tags') as HTMLInputElement).value).toBe('japan, travel');
+        fireEvent.click(screen.getByRole('button', {name: '★ Starred'}));
+
+        await waitFor(() => {
+            expect(screen.getByText('local library is locked')).toBeTruthy();
+        });
+        expect(mockedStarConversation).toHaveBeenCalledWith('claude', 'conv-1', false);
+    });
+});
+
+describe('MessageNoteEditor', () => {
+    beforeEach(() => {
+        mockedSetMessageNote.mockReset();
+    });
+
+    afterEach(() => {
+        cleanup();
+    });
+
+    it('adds a note to a message', async () => {
+        const onChange = vi.fn();
+        mockedSetMessageNote.mockResolvedValue(asAnnotations([]));
+
+        render(<MessageNoteEditor provider="claude" conversationId="conv-1" messageIndex={2} note="" onChange={onChange} />);
+
+        fireEvent.click(screen.getByRole('button', {name: 'Add note'}));
+        fireEvent.change(screen.getByLabelText('Note on message 3'), {target: {value: 'Good itinerary'}});
+        fireEvent.click(screen.getByRole('button', {name: 'Save note'}));
+
+        await waitFor(() => {
+            expect(onChange).toHaveBeenCalledTimes(1);
+        });
+        expect(mockedSetMessageNote).toHaveBeenCalledWith('claude', 'conv-1', 2, 'Good itinerary');
+        expect(screen.queryByLabelText('Note on message 3')).toBeNull();
+    });
+
+    it('shows an existing note', () => {
+        render(<MessageNoteEditor provider="claude" conversationId="conv-1" messageIndex={0} note="Check later" onChange={vi.fn()} />);
+
+        expect(screen.getByText('Note: Check later')).toBeTruthy();
+        expect(screen.getByRole('button', {name: 'Edit note'})).toBeTruthy();
+    });
+});
@@ -0,0 +1,196 @@
+import React, {useEffect, useState} from 'react';
+import {Alert, Button, Stack, TextField, Typography} from '@mui/material';
+
+import {
+    SetConversationNote,
+    SetConversationTags,
+    SetMessageNote,
+    StarConversation
+} from '../../wailsjs/go/main/App';
+import type {models} from '../../wailsjs/go/models';
+
+// Generated classes with nested structs carry convertValues; annotations arrive as plain
+// JSON objects.
+export type ConversationAnnotation = Omit<models.ConversationAnnotation, 'convertValues'>;
+export type Annotations = {
+    updatedAt?: string;
+    conversations: ConversationAnnotation[];
+};
+
+export const emptyAnnotations: Annotations = {conversations: []};
+
+export function findConversationAnnotation(
+    annotations: Annotations,
+    provider: string,
+    conversationId: string
+): ConversationAnnotation | undefined {
+    return annotations.conversations.find(
+        (annotation) => (annotation.provider ?? '') === provider && annotation.conversationId === conversationId
+    );
+}
+
+function parseTags(value: string): string[] {
+    return value.split(',').map((tag) => tag.trim()).filter((tag) => tag !== '');
+}
+
+function errorMessage(failure: unknown): string {
+    return failure instanceof Error ? failure.message : 'Failed to save annotation.';
+}
+
+type ConversationAnnotationEditorProps = {
+    provider: string;
+    conversationId: string;
+    annotation?: ConversationAnnotation;
+    onChange: (annotations: Annotations) => void;
+};
+
+// ConversationAnnotationEditor stars a conversation and edits its tags and note. Text
+// fields save when they lose focus.
+export function ConversationAnnotationEditor({
+    provider,
+    conversationId,
+    annotation,
+    onChange
+}: ConversationAnnotationEditorProps) {
+    const savedTags = (annotation?.tags ?? []).join(', ');
+    const savedNote = annotation?.note ?? '';
+    const [tags, setTags] = useState(savedTags);
+    const [note, setNote] = useState(savedNote);
+    const [saveError, setSaveError] = useState('');
+
+    useEffect(() => {
+        setTags(savedTags);
+    }, [savedTags]);
+
+    useEffect(() => {
+        setNote(savedNote);
+    }, [savedNote]);
+
+    const save = async (request: () => Promise<models.Annotations>) => {
+        setSaveError('');
+        try {
+            onChange(await request());
+        } catch (saveFailure: unknown) {
+            setSaveError(errorMessage(saveFailure));
+        }
+    };
+
+    const isStarred = annotation?.starred ?? false;
+    return (
+        <Stack spacing={1} sx={{mb: 1.5}}>
+            <Stack direction={{xs: 'column', sm: 'row'}} spacing={1} useFlexGap alignItems={{sm: 'center'}}>
+                <Button
+                    size="small"
+                    variant={isStarred ? 'contained' : 'outlined'}
+                    aria-pressed={isStarred}
+                    onClick={() => save(() => StarConversation(provider, conversationId, !isStarred))}
+                >
+                    {isStarred ? '★ Starred' : '☆ Star'}
+                </Button>
+                <TextField
+                    size="small"
+                    label="Tags"
+                    placeholder="comma separated"
+                    value={tags}
+                    onChange={(event) => setTags(event.target.value)}
+                    onBlur={() => {
+                        if (tags !== savedTags) {
+                            void save(() => SetConversationTags(provider, conversationId, parseTags(tags)));
+                        }
+                    }}
+                    sx={{flex: 1}}
+                />
+            </Stack>
+            <TextField
+                size="small"
+                label="Conversation note"
+                multiline
+                minRows={1}
+                value={note}
+                onChange={(event) => setNote(event.target.value)}
+                onBlur={() => {
+                    if (note !== savedNote) {
+                        void save(() => SetConversationNote(provider, conversationId, note));
+                    }
+                }}
+            />
+            {saveError && (
+                <Alert severity="error" variant="outlined">
+                    {saveError}
+                </Alert>
+            )}
+        </Stack>
+    );
+}
+
+type MessageNoteEditorProps = {
+    provider: string;
+    conversationId: string;
+    messageIndex: number;
+    note: string;
+    onChange: (annotations: Annotations) => void;
+};
+
+// MessageNoteEditor shows the note on a message and edits it on request.
+export function MessageNoteEditor({provider, conversationId, messageIndex, note, onChange}: MessageNoteEditorProps) {
+    const [isEditing, setIsEditing] = useState(false);
+    const [draft, setDraft] = useState(note);
+    const [saveError, setSaveError] = useState('');
+
+    const save = async () => {
+        setSaveError('');
+        try {
+            onChange(await SetMessageNote(provider, conversationId, messageIndex, draft));
+            setIsEditing(false);
+        } catch (saveFailure: unknown) {
+            setSaveError(errorMessage(saveFailure));
+        }
+    };
+
+    if (!isEditing) {
+        return (
+            <Stack direction="row" spacing={1} alignItems="center" sx={{mt: 1}}>
+                {note && (
+                    <Typography variant="body2" color="text.secondary" sx={{fontStyle: 'italic', whiteSpace: 'pre-wrap'}}>
+                        Note: {note}
+                    </Typography>
+                )}
+                <Button
+                    size="small"
+                    onClick={() => {
+                        setDraft(note);
+                        setIsEditing(true);
+                    }}
+                >
+                    {note ? 'Edit note' : 'Add note'}
+                </Button>
+            </Stack>
+        );
+    }
+
+    return (
+        <Stack spacing={1} sx={{mt: 1}}>
+            <TextField
+                size="small"
+                label={`Note on message ${messageIndex + 1}`}
+                multiline
+                autoFocus
+                value={draft}
+                onChange={(event) => setDraft(event.target.value)}
+            />
+            <Stack direction="row" spacing={1}>
+                <Button size="small" variant="contained" onClick={save}>
+                    Save note
+                </Button>
+                <Button size="small" onClick={() => setIsEditing(false)}>
+                    Cancel
+                </Button>
+            </Stack>
+            {saveError && (
+                <Alert severity="error" variant="outlined">
+                    {saveError}
+                </Alert>
+            )}
+        </Stack>
+    );
+}
@@ -1,5 +1,5 @@
 import React from 'react';
-import {cleanup, fireEvent, render, screen} from '@testing-library/react';
+import {cleanup, fireEvent, render, screen, within} from '@testing-library/react';
 import {afterEach, describe, expect, it, vi} from 'vitest';
 
 import {ConversationList} from './ConversationList';
@@ -23,6 +23,7 @@ function conversationThread(overrides: Partial<ConversationThread>): Conversatio
         conversationId: overrides.conversationId ?? '',
         conversationName,
         conversationRawName: overrides.conversationRawName ?? conversationName.trim(),
+        conversationTitle: overrides.conversationTitle,
         conversationCreatedAt,
         conversationCreatedAtUnixMilliseconds: overrides.conversationCreatedAtUnixMilliseconds ?? null,
         messages: overrides.messages ?? []
@@ -40,6 +41,7 @@ const mockConversations: ConversationThread[] = [
                 conversationId: 'conv-1',
                 conversationName: 'First Conversation',
                 conversationCreatedAt: '2025-09-19T04:41:47.942021Z',
+                messageIndex: 0,
                 speaker: 'human',
                 message: 'Hello',
                 messageTimestamp: '2025-09-19T04:41:47.942021Z'
@@ -56,6 +58,7 @@ const mockConversations: ConversationThread[] = [
                 conversationId: 'conv-2',
                 conversationName: 'Second Conversation',
                 conversationCreatedAt: '2025-09-19T04:42:47.942021Z',
+                messageIndex: 0,
                 speaker: 'assistant',
                 message: 'Hi there',
                 messageTimestamp: '2025-09-19T04:42:47.942021Z'
@@ -189,6 +192,7 @@ describe('ConversationList', () => {
                         conversationId: 'conv-chatgpt',
                         conversationName: 'ChatGPT Conversation',
                         conversationCreatedAt: '2025-09-19T04:41:47.942021Z',
+                        messageIndex: 0,
                         speaker: 'user',
                         message: 'ChatGPT user message',
                         messageTimestamp: '2025-09-19T04:41:47.942021Z'
@@ -207,6 +211,90 @@ describe('ConversationList', () => {
         expect(userChip?.className).toContain('MuiChip-colorWarning');
     });
 
+    it('shows the model, finish reason, searches and sources of an answer', () => {
+        const browsingConversation: ConversationThread[] = [
+            conversationThread({
+                conversationId: 'conv-browsing',
+                conversationName: 'Browsing',
+                messages: [
+                    {
+                        conversationId: 'conv-browsing',
+                        conversationName: 'Browsing',
+                        conversationCreatedAt: '',
+                        speaker: 'assistant',
+                        message: 'Go 1.23 is the latest release.',
+                        messageTimestamp: '',
+                        model: 'gpt-4o',
+                        finishReason: 'stop',
+                        searchQueries: ['latest go release', 'go 1.23'],
+                        citations: [
+                            {title: 'Go release history', url: 'https://go.dev/doc/devel/release'},
+                            {url: 'https://go.dev/blog'}
+                        ]
+                    }
+                ]
+            })
+        ];
+
+        render(<ConversationList conversations={browsingConversation} conversationSetVersion={0} />);
+        fireEvent.click(screen.getByRole('button', {name: /Browsing/i}));
+
+        expect(screen.getByText('gpt-4o').closest('.MuiChip-root')).toBeTruthy();
+        expect(screen.getByText('finish: stop')).toBeTruthy();
+        expect(screen.getByText('Searched: latest go release · go 1.23')).toBeTruthy();
+
+        const sources = within(screen.getByRole('list', {name: 'Sources'})).getAllByRole('listitem');
+        expect(sources.map((source) => source.textContent)).toEqual([
+            'Go release history — https://go.dev/doc/devel/release',
+            'https://go.dev/blog'
+        ]);
+    });
+
+    it('shows estimated token counts per conversation and message', () => {
+        const countedConversation: ConversationThread[] = [
+            conversationThread({
+                conversationId: 'conv-tokens',
+                conversationName: 'Tokens',
+                messages: [
+                    {conversationId: 'conv-tokens', conversationName: 'Tokens', conversationCreatedAt: '', messageIndex: 0, speaker: 'user', message: 'Hi', messageTimestamp: '', tokenCount: 1},
+                    {conversationId: 'conv-tokens', conversationName: 'Tokens', conversationCreatedAt: '', messageIndex: 1, speaker: 'assistant', message: 'Hello there', messageTimestamp: '', tokenCount: 2}
+                ]
+            })
+        ];
+
+        render(<ConversationList conversations={countedConversation} conversationSetVersion={0} />);
+
+        expect(screen.getByText('~3 tokens')).toBeTruthy();
+
+        fireEvent.click(screen.getByRole('button', {name: /Tokens/i}));
+
+        expect(screen.getByText('~1 token')).toBeTruthy();
+        expect(screen.getByText('~2 tokens')).toBeTruthy();
+    });
+
+    it('labels tool calls, tool results and reasoning', () => {
+        const toolConversation: ConversationThread[] = [
+            conversationThread({
+                conversationId: 'conv-tools',
+                conversationName: 'Tools',
+                messages: [
+                    {conversationId: 'conv-tools', conversationName: 'Tools', conversationCreatedAt: '', messageIndex: 0, speaker: 'assistant', message: 'thinking', messageTimestamp: '', kind: 'reasoning'},
+                    {conversationId: 'conv-tools', conversationName: 'Tools', conversationCreatedAt: '', messageIndex: 1, speaker: 'assistant', message: '{"q": "oslo"}', messageTimestamp: '', kind: 'tool_call', recipient: 'web.run'},
+                    {conversationId: 'conv-tools', conversationName: 'Tools', conversationCreatedAt: '', messageIndex: 2, speaker: 'tool', message: '4°C', messageTimestamp: '', kind: 'tool_result', toolName: 'web.run'},
+                    {conversationId: 'conv-tools', conversationName: 'Tools', conversationCreatedAt: '', messageIndex: 3, speaker: 'assistant', message: 'It is 4°C.', messageTimestamp: '', kind: 'answer'}
+                ]
+            })
+        ];
+
+        render(<ConversationList conversations={toolConversation} conversationSetVersion={0} />);
+        fireEvent.click(screen.getByRole('button', {name: /Tools/i}));
+
+        expect(screen.getByText('reasoning')).toBeTruthy();
+        expect(screen.getByText('tool call → web.run')).toBeTruthy();
+        expect(screen.getByText('tool result from web.run')).toBeTruthy();
+        expect(screen.queryByText('answer')).toBeNull();
+    });
+
     it('does not reformat timestamps for an already-expanded conversation when toggling another conversation', () => {
         render(<ConversationList conversations={mockConversations} conversationSetVersion={0} />);
 
@@ -224,6 +312,88 @@ describe('ConversationList', () => {
         expect(callArguments).not.toContain(firstTimestamp);
     });
 
+    it('shows the original name next to a generated title', () => {
+        render(
+            <ConversationList
+                conversations={[
+                    conversationThread({conversationId: 'conv-a', conversationName: 'Deploy to Kubernetes', conversationRawName: 'New chat', conversationTitle: 'Deploy to Kubernetes'}),
+                    conversationThread({conversationId: 'conv-b', conversationName: 'Plan a trip', conversationRawName: '', conversationTitle: 'Plan a trip'})
+                ]}
+                conversationSetVersion={0}
+            />
+        );
+
+        const firstHeader = screen.getByRole('button', {name: /Deploy to Kubernetes/i});
+        expect(within(firstHeader).getByText('auto title · originally "New chat"')).toBeTruthy();
+        const secondHeader = screen.getByRole('button', {name: /Plan a trip/i});
+        expect(within(secondHeader).getByText('auto title')).toBeTruthy();
+    });
+
+    it('expands restored panels for a new conversation set and reports toggles', () => {
+        const onPanelToggle = vi.fn();
+        const {rerender} = render(
+            <ConversationList
+                conversations={mockConversations}
+                conversationSetVersion={0}
+                restoredPanelKeys={['id:conv-2']}
+                onPanelToggle={onPanelToggle}
+            />
+        );
+
+        expect(screen.getByRole('button', {name: /Second Conversation/i}).getAttribute('aria-expanded')).toBe('true');
+        expect(screen.getByRole('button', {name: /First Conversation/i}).getAttribute('aria-expanded')).toBe('false');
+
+        fireEvent.click(screen.getByRole('button', {name: /First Conversation/i}));
+        expect(onPanelToggle).toHaveBeenCalledWith('id:conv-1', true);
+
+        rerender(
+            <ConversationList
+                conversations={mockConversations}
+                conversationSetVersion={1}
+                restoredPanelKeys={[]}
+                onPanelToggle={onPanelToggle}
+            />
+        );
+        expect(screen.getByRole('button', {name: /First Conversation/i}).getAttribute('aria-expanded')).toBe('false');
+        expect(screen.getByRole('button', {name: /Second Conversation/i}).getAttribute('aria-expanded')).toBe('false');
+    });
+
+    it('shows stars and tags and edits annotations when enabled', () => {
+        const threads = [
+            conversationThread({
+                conversationId: 'conv-star',
+                conversationName: 'Starred chat',
+                messages: [
+                    {conversationId: 'conv-star', conversationName: 'Starred chat', conversationCreatedAt: '', provider: 'claude', messageIndex: 0, speaker: 'human', message: 'hello', messageTimestamp: ''},
+                    {conversationId: 'conv-star', conversationName: 'Starred chat', conversationCreatedAt: '', provider: 'claude', messageIndex: 1, speaker: 'assistant', message: 'hi', messageTimestamp: ''}
+                ]
+            })
+        ];
+        const annotations = {
+            conversations: [
+                {provider: 'claude', conversationId: 'conv-star', starred: true, tags: ['travel'], messageNotes: [{messageIndex: 1, note: 'Friendly'}]}
+            ]
+        };
+
+        render(
+            <ConversationList
+                conversations={threads}
+                conversationSetVersion={0}
+                annotations={annotations}
+                onAnnotationsChange={vi.fn()}
+            />
+        );
+
+        const header = screen.getByRole('button', {name: /Starred chat/i});
+        expect(within(header).getByRole('img', {name: 'Starred'})).toBeTruthy();
+        expect(within(header).getByText('travel')).toBeTruthy();
+
+        fireEvent.click(header);
+        expect(screen.getByRole('button', {name: '★ Starred'})).toBeTruthy();
+        expect(screen.getByText('Note: Friendly')).toBeTruthy();
+        expect(screen.getAllByRole('button', {name: 'Add note'}).length).toBe(1);
+    });
+
     it('collapses a short conversation in under 100ms', () => {
         const shortConversation: ConversationThread[] = [
             conversationThread({
@@ -236,6 +406,7 @@ describe('ConversationList', () => {
                     conversationId: 'conv-latency',
                     conversationName: 'Latency',
                     conversationCreatedAt: '2026-01-01T00:00:00Z',
+                    messageIndex: index,
                     speaker: index % 2 === 0 ? 'human' : 'assistant',
                     message: `message-${index}`,
                     messageTimestamp: `2026-01-01T00:00:0${index}Z`
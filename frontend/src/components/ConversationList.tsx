@@ -9,12 +9,33 @@ import {
     Typography
 } from '@mui/material';
 
-import type {ConversationThread} from '../models/conversations';
+import {
+    countTokens,
+    formatTokenCount,
+    isToolChatter,
+    type ConversationEntry,
+    type ConversationThread
+} from '../models/conversations';
 import {formatConversationTimestamp, formatMessageTimestamp} from '../utils/timestamps';
+import {
+    ConversationAnnotationEditor,
+    MessageNoteEditor,
+    findConversationAnnotation,
+    type Annotations,
+    type ConversationAnnotation
+} from './ConversationAnnotations';
 
 type ConversationListProps = {
     conversations: ConversationThread[];
     conversationSetVersion: number;
+    // annotations and onAnnotationsChange enable stars, tags and notes; without a change
+    // handler the list is read-only.
+    annotations?: Annotations;
+    onAnnotationsChange?: (annotations: Annotations) => void;
+    // restoredPanelKeys are expanded whenever a new conversation set is shown, such as
+    // the conversations left expanded in the last session. onPanelToggle reports changes.
+    restoredPanelKeys?: string[];
+    onPanelToggle?: (panelKey: string, isExpanded: boolean) => void;
 };
 
 type ConversationPanelProps = {
@@ -23,6 +44,8 @@ type ConversationPanelProps = {
     panelKey: string;
     isExpanded: boolean;
     onToggle: (panelKey: string, isExpanded: boolean) => void;
+    annotation?: ConversationAnnotation;
+    onAnnotationsChange?: (annotations: Annotations) => void;
 };
 
 function getSpeakerChipColor(speaker: string): 'default' | 'success' | 'warning' {
@@ -36,6 +59,50 @@ function getSpeakerChipColor(speaker: string): 'default' | 'success' | 'warning'
     return 'default';
 }
 
+function describeMessageKind(entry: ConversationEntry): string {
+    switch (entry.kind) {
+    case 'tool_call':
+        return entry.recipient ? `tool call → ${entry.recipient}` : 'tool call';
+    case 'tool_result':
+        return entry.toolName ? `tool result from ${entry.toolName}` : 'tool result';
+    case 'reasoning':
+        return 'reasoning';
+    default:
+        return '';
+    }
+}
+
+function MessageMetadata({entry}: {entry: ConversationEntry}) {
+    const citations = entry.citations ?? [];
+    const searchQueries = entry.searchQueries ?? [];
+    if (citations.length === 0 && searchQueries.length === 0) {
+        return null;
+    }
+
+    return (
+        <Stack spacing={0.5} sx={{mt: 1}}>
+            {searchQueries.length > 0 && (
+                <Typography variant="caption" color="text.secondary">
+                    Searched: {searchQueries.join(' · ')}
+                </Typography>
+            )}
+            {citations.length > 0 && (
+                <Stack component="ul" aria-label="Sources" spacing={0.25} sx={{m: 0, pl: 2}}>
+                    {citations.map((citation) => (
+                        <Typography component="li" variant="caption" color="text.secondary" key={citation.url}>
+                            {citation.title ? `${citation.title} — ${citation.url}` : citation.url}
+                        </Typography>
+                    ))}
+                </Stack>
+            )}
+        </Stack>
+    );
+}
+
+function expandedPanels(panelKeys: string[] | undefined): Record<string, boolean> {
+    return Object.fromEntries((panelKeys ?? []).map((panelKey) => [panelKey, true]));
+}
+
 function buildPanelKey(conversationID: string, conversationRawName: string): string {
     if (conversationID !== '') {
         return `id:${conversationID}`;
@@ -48,7 +115,9 @@ const ConversationPanel = React.memo(function ConversationPanel({
     conversationIndex,
     panelKey,
     isExpanded,
-    onToggle
+    onToggle,
+    annotation,
+    onAnnotationsChange
 }: ConversationPanelProps) {
     const handleToggle = useCallback(
         (_: React.SyntheticEvent, nextExpanded: boolean) => {
@@ -56,6 +125,9 @@ const ConversationPanel = React.memo(function ConversationPanel({
         },
         [onToggle, panelKey]
     );
+    const conversationTokenCount = countTokens(conversation.messages);
+    const provider = conversation.messages[0]?.provider ?? '';
+    const messageNotes = new Map((annotation?.messageNotes ?? []).map((messageNote) => [messageNote.messageIndex, messageNote.note]));
 
     return (
         <Accordion
@@ -81,6 +153,11 @@ const ConversationPanel = React.memo(function ConversationPanel({
                     useFlexGap
                     sx={{width: '100%', alignItems: {sm: 'center'}}}
                 >
+                    {annotation?.starred && (
+                        <Typography variant="subtitle2" role="img" aria-label="Starred" sx={{color: '#b8860b'}}>
+                            ★
+                        </Typography>
+                    )}
                     <Typography
                         data-testid="conversation-title"
                         variant="subtitle2"
@@ -88,9 +165,24 @@ const ConversationPanel = React.memo(function ConversationPanel({
                     >
                         {conversation.conversationName}
                     </Typography>
+                    {conversation.conversationTitle && (
+                        <Typography variant="caption" color="text.secondary" sx={{fontStyle: 'italic'}}>
+                            {conversation.conversationRawName
+                                ? `auto title · originally "${conversation.conversationRawName}"`
+                                : 'auto title'}
+                        </Typography>
+                    )}
+                    {(annotation?.tags ?? []).map((tag) => (
+                        <Chip key={tag} label={tag} size="small" variant="outlined" />
+                    ))}
                     <Typography variant="caption" color="text.secondary">
                         {conversation.messages.length} {conversation.messages.length === 1 ? 'message' : 'messages'}
                     </Typography>
+                    {conversationTokenCount > 0 && (
+                        <Typography variant="caption" color="text.secondary">
+                            {formatTokenCount(conversationTokenCount)}
+                        </Typography>
+                    )}
                     {conversation.conversationId && (
                         <Typography variant="caption" color="text.secondary">
                             {conversation.conversationId}
@@ -102,13 +194,21 @@ const ConversationPanel = React.memo(function ConversationPanel({
                 </Stack>
             </AccordionSummary>
             <AccordionDetails>
+                {onAnnotationsChange && conversation.conversationId && (
+                    <ConversationAnnotationEditor
+                        provider={provider}
+                        conversationId={conversation.conversationId}
+                        annotation={annotation}
+                        onChange={onAnnotationsChange}
+                    />
+                )}
                 <Stack spacing={1.5} role="list" aria-label={`${conversation.conversationName} messages`}>
                     {conversation.messages.map((entry, messageIndex) => (
                         <Paper
                             variant="outlined"
                             role="listitem"
                             key={`${entry.conversationId}-${entry.speaker}-${messageIndex}`}
-                            sx={{p: 1.5, backgroundColor: '#ffffff'}}
+                            sx={{p: 1.5, backgroundColor: isToolChatter(entry) ? '#f7f7f4' : '#ffffff'}}
                         >
                             <Stack direction={{xs: 'column', sm: 'row'}} spacing={1} useFlexGap sx={{mb: 1}}>
                                 <Chip
@@ -121,10 +221,42 @@ const ConversationPanel = React.memo(function ConversationPanel({
                                 <Typography variant="caption" color="text.secondary" sx={{alignSelf: {xs: 'flex-start', sm: 'center'}}}>
                                     {formatMessageTimestamp(entry.messageTimestamp)}
                                 </Typography>
+                                {describeMessageKind(entry) && (
+                                    <Typography variant="caption" color="text.secondary" sx={{alignSelf: {xs: 'flex-start', sm: 'center'}, fontStyle: 'italic'}}>
+                                        {describeMessageKind(entry)}
+                                    </Typography>
+                                )}
+                                {entry.model && (
+                                    <Chip
+                                        label={entry.model}
+                                        size="small"
+                                        sx={{alignSelf: {xs: 'flex-start', sm: 'center'}}}
+                                    />
+                                )}
+                                {entry.finishReason && (
+                                    <Typography variant="caption" color="text.secondary" sx={{alignSelf: {xs: 'flex-start', sm: 'center'}}}>
+                                        finish: {entry.finishReason}
+                                    </Typography>
+                                )}
+                                {entry.tokenCount !== undefined && entry.tokenCount > 0 && (
+                                    <Typography variant="caption" color="text.secondary" sx={{alignSelf: {xs: 'flex-start', sm: 'center'}}}>
+                                        {formatTokenCount(entry.tokenCount)}
+                                    </Typography>
+                                )}
                             </Stack>
                             <Typography variant="body2" sx={{whiteSpace: 'pre-wrap'}}>
                                 {entry.message}
                             </Typography>
+                            <MessageMetadata entry={entry} />
+                            {onAnnotationsChange && conversation.conversationId && (
+                                <MessageNoteEditor
+                                    provider={provider}
+                                    conversationId={conversation.conversationId}
+                                    messageIndex={entry.messageIndex}
+                                    note={messageNotes.get(entry.messageIndex) ?? ''}
+                                    onChange={onAnnotationsChange}
+                                />
+                            )}
                         </Paper>
                     ))}
                 </Stack>
@@ -133,11 +265,22 @@ const ConversationPanel = React.memo(function ConversationPanel({
     );
 });
 
-export function ConversationList({conversations, conversationSetVersion}: ConversationListProps) {
-    const [expandedConversationPanels, setExpandedConversationPanels] = useState<Record<string, boolean>>({});
+export function ConversationList({
+    conversations,
+    conversationSetVersion,
+    annotations,
+    onAnnotationsChange,
+    restoredPanelKeys,
+    onPanelToggle
+}: ConversationListProps) {
+    const [expandedConversationPanels, setExpandedConversationPanels] = useState<Record<string, boolean>>(
+        () => expandedPanels(restoredPanelKeys)
+    );
 
+    // Only a new conversation set restores panels; later changes to restoredPanelKeys
+    // come from this list's own toggles.
     useEffect(() => {
-        setExpandedConversationPanels({});
+        setExpandedConversationPanels(expandedPanels(restoredPanelKeys));
     }, [conversationSetVersion]);
 
     const handleConversationToggle = useCallback((panelKey: string, isExpanded: boolean): void => {
@@ -145,7 +288,8 @@ export function ConversationList({conversations, conversationSetVersion}: Conver
             ...previousPanels,
             [panelKey]: isExpanded
         }));
-    }, []);
+        onPanelToggle?.(panelKey, isExpanded);
+    }, [onPanelToggle]);
 
     return (
         <Stack spacing={1.5}>
@@ -164,6 +308,14 @@ export function ConversationList({conversations, conversationSetVersion}: Conver
                         panelKey={panelKey}
                         isExpanded={isExpanded}
                         onToggle={handleConversationToggle}
+                        annotation={
+                            annotations && findConversationAnnotation(
+                                annotations,
+                                conversation.messages[0]?.provider ?? '',
+                                conversation.conversationId
+                            )
+                        }
+                        onAnnotationsChange={onAnnotationsChange}
                     />
                 );
             })}
@@ -0,0 +1,53 @@
+import React from 'react';
+import {cleanup, render, screen, within} from '@testing-library/react';
+import {afterEach, describe, expect, it} from 'vitest';
+
+import {CustomInstructionsPanel} from './CustomInstructionsPanel';
+import {formatConversationTimestamp} from '../utils/timestamps';
+
+describe('CustomInstructionsPanel', () => {
+    afterEach(() => {
+        cleanup();
+    });
+
+    it('renders nothing when the export has no custom instructions', () => {
+        const {container} = render(<CustomInstructionsPanel versions={[]} />);
+
+        expect(container.firstChild).toBeNull();
+    });
+
+    it('lists each version with its date range and texts', () => {
+        render(
+            <CustomInstructionsPanel
+                versions={[
+                    {
+                        userProfile: 'Likes jazz',
+                        userInstructions: 'Be concise.',
+                        firstSeen: '2024-01-01T00:00:00Z',
+                        lastSeen: '2024-02-01T00:00:00Z',
+                        conversationIds: ['conv-1', 'conv-2']
+                    },
+                    {
+                        userInstructions: 'Answer in French.',
+                        firstSeen: '',
+                        lastSeen: '',
+                        conversationIds: ['conv-3']
+                    }
+                ]}
+            />
+        );
+
+        expect(screen.getByText('2 versions of custom instructions found in this export.')).toBeTruthy();
+
+        const items = within(screen.getByRole('list', {name: 'Custom instruction versions'})).getAllByRole('listitem');
+        expect(items.length).toBe(2);
+        expect(within(items[0]).getByText(
+            `${formatConversationTimestamp('2024-01-01T00:00:00Z')} – ${formatConversationTimestamp('2024-02-01T00:00:00Z')} · 2 conversations`
+        )).toBeTruthy();
+        expect(within(items[0]).getByText('Likes jazz')).toBeTruthy();
+        expect(within(items[0]).getByText('Be concise.')).toBeTruthy();
+        expect(within(items[1]).getByText('1 conversation')).toBeTruthy();
+        expect(within(items[1]).queryByText('About you')).toBeNull();
+        expect(within(items[1]).getByText('Answer in French.')).toBeTruthy();
+    });
+});
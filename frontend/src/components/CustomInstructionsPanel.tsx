@@ -0,0 +1,74 @@
+import React from 'react';
+import {Paper, Stack, Typography} from '@mui/material';
+
+import type {models} from '../../wailsjs/go/models';
+import {formatConversationTimestamp} from '../utils/timestamps';
+
+type CustomInstructionsVersion = models.CustomInstructionsVersion;
+
+type CustomInstructionsPanelProps = {
+    versions: CustomInstructionsVersion[];
+};
+
+function formatVersionSummary(versionCount: number): string {
+    if (versionCount === 1) {
+        return '1 version of custom instructions found in this export.';
+    }
+    return `${versionCount} versions of custom instructions found in this export.`;
+}
+
+function formatVersionRange(version: CustomInstructionsVersion): string {
+    const conversationCount = version.conversationIds.length;
+    const conversationLabel = conversationCount === 1 ? '1 conversation' : `${conversationCount} conversations`;
+    if (version.firstSeen.trim() === '') {
+        return conversationLabel;
+    }
+    if (version.firstSeen === version.lastSeen) {
+        return `${formatConversationTimestamp(version.firstSeen)} · ${conversationLabel}`;
+    }
+    return `${formatConversationTimestamp(version.firstSeen)} – ${formatConversationTimestamp(version.lastSeen)} · ${conversationLabel}`;
+}
+
+function InstructionsText({label, text}: {label: string; text?: string}) {
+    if ((text ?? '').trim() === '') {
+        return null;
+    }
+
+    return (
+        <Stack spacing={0.25}>
+            <Typography variant="caption" sx={{fontWeight: 700}}>
+                {label}
+            </Typography>
+            <Typography variant="caption" color="text.secondary" sx={{whiteSpace: 'pre-wrap', wordBreak: 'break-word'}}>
+                {text}
+            </Typography>
+        </Stack>
+    );
+}
+
+export function CustomInstructionsPanel({versions}: CustomInstructionsPanelProps) {
+    if (versions.length === 0) {
+        return null;
+    }
+
+    return (
+        <Paper variant="outlined" role="region" aria-label="Custom instructions" sx={{p: 2}}>
+            <Stack spacing={1.5}>
+                <Typography variant="body2" color="text.secondary">
+                    {formatVersionSummary(versions.length)}
+                </Typography>
+                <Stack spacing={1.5} role="list" aria-label="Custom instruction versions">
+                    {versions.map((version, index) => (
+                        <Stack role="listitem" key={`${index}-${version.firstSeen}`} spacing={0.5}>
+                            <Typography variant="caption" sx={{fontWeight: 700}}>
+                                {formatVersionRange(version)}
+                            </Typography>
+                            <InstructionsText label="About you" text={version.userProfile} />
+                            <InstructionsText label="How to respond" text={version.userInstructions} />
+                        </Stack>
+                    ))}
+                </Stack>
+            </Stack>
+        </Paper>
+    );
+}
@@ -0,0 +1,132 @@
+import React from 'react';
+import {cleanup, fireEvent, render, screen, waitFor, within} from '@testing-library/react';
+import {afterEach, beforeEach, describe, expect, it, vi} from 'vitest';
+
+import {DeletionPlanPanel} from './DeletionPlanPanel';
+import {
+    AddToDeletionPlan,
+    ExportDeletionPlan,
+    GetDeletionPlan,
+    RemoveFromDeletionPlan,
+    SetDeletionDone
+} from '../../wailsjs/go/main/App';
+import type {models} from '../../wailsjs/go/models';
+import {BrowserOpenURL} from '../../wailsjs/runtime/runtime';
+
+vi.mock('../../wailsjs/go/main/App', () => ({
+    AddToDeletionPlan: vi.fn(),
+    ExportDeletionPlan: vi.fn(),
+    GetDeletionPlan: vi.fn(),
+    RemoveFromDeletionPlan: vi.fn(),
+    SetDeletionDone: vi.fn()
+}));
+
+vi.mock('../../wailsjs/runtime/runtime', () => ({
+    BrowserOpenURL: vi.fn()
+}));
+
+const mockedAddToDeletionPlan = vi.mocked(AddToDeletionPlan);
+const mockedExportDeletionPlan = vi.mocked(ExportDeletionPlan);
+const mockedGetDeletionPlan = vi.mocked(GetDeletionPlan);
+const mockedRemoveFromDeletionPlan = vi.mocked(RemoveFromDeletionPlan);
+const mockedSetDeletionDone = vi.mocked(SetDeletionDone);
+const mockedBrowserOpenURL = vi.mocked(BrowserOpenURL);
+
+const plannedItem = {
+    provider: 'chatgpt',
+    conversationId: 'conv-1',
+    conversationName: 'Tax return',
+    createdAt: '2023-01-01T00:00:00Z',
+    url: 'https://chatgpt.com/c/conv-1',
+    reasons: ['older than 365 days'],
+    done: false
+};
+
+// Bound methods resolve to generated model classes; the fixtures are plain objects.
+function asPlan(items: models.DeletionItem[]): models.DeletionPlan {
+    return {items} as models.DeletionPlan;
+}
+
+describe('DeletionPlanPanel', () => {
+    beforeEach(() => {
+        mockedAddToDeletionPlan.mockReset();
+        mockedExportDeletionPlan.mockReset();
+        mockedGetDeletionPlan.mockReset();
+        mockedRemoveFromDeletionPlan.mockReset();
+        mockedSetDeletionDone.mockReset();
+        mockedBrowserOpenURL.mockReset();
+    });
+
+    afterEach(() => {
+        cleanup();
+    });
+
+    it('renders nothing without conversations or a saved plan', async () => {
+        mockedGetDeletionPlan.mockResolvedValue(asPlan([]));
+
+        const {container} = render(<DeletionPlanPanel conversationSetVersion={0} hasConversations={false} />);
+
+        await waitFor(() => {
+            expect(mockedGetDeletionPlan).toHaveBeenCalled();
+        });
+        expect(container.firstChild).toBeNull();
+    });
+
+    it('shows a saved plan before any export is loaded', async () => {
+        mockedGetDeletionPlan.mockResolvedValue(asPlan([plannedItem]));
+
+        render(<DeletionPlanPanel conversationSetVersion={0} hasConversations={false} />);
+
+        await waitFor(() => {
+            expect(screen.getByText('0 of 1 conversation deleted.')).toBeTruthy();
+        });
+        expect(screen.queryByRole('button', {name: 'Add sensitive'})).toBeNull();
+
+        fireEvent.click(screen.getByRole('button', {name: 'Open'}));
+        expect(mockedBrowserOpenURL).toHaveBeenCalledWith('https://chatgpt.com/c/conv-1');
+    });
+
+    it('adds conversations and tracks their done state', async () => {
+        mockedGetDeletionPlan.mockResolvedValue(asPlan([]));
+        mockedAddToDeletionPlan.mockResolvedValue(asPlan([plannedItem]));
+        mockedSetDeletionDone.mockResolvedValue(asPlan([{...plannedItem, done: true}]));
+
+        render(<DeletionPlanPanel conversationSetVersion={0} hasConversations />);
+
+        await waitFor(() => {
+            expect(screen.getByText('No conversations planned for deletion.')).toBeTruthy();
+        });
+        fireEvent.click(screen.getByRole('button', {name: 'Add older than a year'}));
+
+        await waitFor(() => {
+            expect(screen.getByText('0 of 1 conversation deleted.')).toBeTruthy();
+        });
+        expect(mockedAddToDeletionPlan).toHaveBeenCalledWith({olderThanDays: 365});
+        const items = within(screen.getByRole('list', {name: 'Planned deletions'})).getAllByRole('listitem');
+        expect(within(items[0]).getByText('chatgpt · older than 365 days')).toBeTruthy();
+
+        fireEvent.click(screen.getByLabelText('Mark Tax return as deleted'));
+
+        await waitFor(() => {
+            expect(screen.getByText('1 of 1 conversation deleted.')).toBeTruthy();
+        });
+        expect(mockedSetDeletionDone).toHaveBeenCalledWith('chatgpt', 'conv-1', true);
+    });
+
+    it('reports where the plan was exported', async () => {
+        mockedGetDeletionPlan.mockResolvedValue(asPlan([plannedItem]));
+        mockedExportDeletionPlan.mockResolvedValue('/tmp/deletion-plan.csv');
+
+        render(<DeletionPlanPanel conversationSetVersion={0} hasConversations />);
+
+        await waitFor(() => {
+            expect(screen.getByRole('button', {name: 'Export CSV'})).toBeTruthy();
+        });
+        fireEvent.click(screen.getByRole('button', {name: 'Export CSV'}));
+
+        await waitFor(() => {
+            expect(screen.getByText('Deletion plan saved to /tmp/deletion-plan.csv.')).toBeTruthy();
+        });
+        expect(mockedExportDeletionPlan).toHaveBeenCalledWith('csv');
+    });
+});
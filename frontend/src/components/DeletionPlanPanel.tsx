@@ -0,0 +1,200 @@
+import React, {useEffect, useState} from 'react';
+import {Alert, Button, Checkbox, Paper, Stack, TextField, Typography} from '@mui/material';
+
+import {
+    AddToDeletionPlan,
+    ExportDeletionPlan,
+    GetDeletionPlan,
+    RemoveFromDeletionPlan,
+    SetDeletionDone
+} from '../../wailsjs/go/main/App';
+import type {models} from '../../wailsjs/go/models';
+import {BrowserOpenURL} from '../../wailsjs/runtime/runtime';
+
+type DeletionItem = models.DeletionItem;
+type DeletionCriteria = models.DeletionCriteria;
+
+type DeletionPlanPanelProps = {
+    conversationSetVersion: number;
+    hasConversations: boolean;
+};
+
+const oneYearInDays = 365;
+
+function formatPlanSummary(items: DeletionItem[]): string {
+    const doneCount = items.filter((item) => item.done).length;
+    const noun = items.length === 1 ? 'conversation' : 'conversations';
+    return `${doneCount} of ${items.length} ${noun} deleted.`;
+}
+
+function errorMessage(failure: unknown, fallback: string): string {
+    return failure instanceof Error ? failure.message : fallback;
+}
+
+export function DeletionPlanPanel({conversationSetVersion, hasConversations}: DeletionPlanPanelProps) {
+    const [items, setItems] = useState<DeletionItem[]>([]);
+    const [query, setQuery] = useState('');
+    const [status, setStatus] = useState('');
+    const [planError, setPlanError] = useState('');
+
+    useEffect(() => {
+        let isCurrent = true;
+        GetDeletionPlan()
+            .then((plan) => {
+                if (isCurrent) {
+                    setItems(plan?.items ?? []);
+                }
+            })
+            .catch((loadFailure: unknown) => {
+                if (isCurrent) {
+                    setPlanError(errorMessage(loadFailure, 'Failed to read the deletion plan.'));
+                }
+            });
+
+        return () => {
+            isCurrent = false;
+        };
+    }, [conversationSetVersion]);
+
+    const applyPlanChange = async (change: () => Promise<models.DeletionPlan>, fallback: string) => {
+        setStatus('');
+        setPlanError('');
+        try {
+            const plan = await change();
+            setItems(plan?.items ?? []);
+        } catch (changeFailure: unknown) {
+            setPlanError(errorMessage(changeFailure, fallback));
+        }
+    };
+
+    const addConversations = (criteria: DeletionCriteria) => applyPlanChange(
+        () => AddToDeletionPlan(criteria),
+        'Failed to add conversations to the deletion plan.'
+    );
+
+    const exportPlan = async (format: string) => {
+        setStatus('');
+        setPlanError('');
+        try {
+            const exportedPath = await ExportDeletionPlan(format);
+            if (exportedPath) {
+                setStatus(`Deletion plan saved to ${exportedPath}.`);
+            }
+        } catch (exportFailure: unknown) {
+            setPlanError(errorMessage(exportFailure, 'Failed to export the deletion plan.'));
+        }
+    };
+
+    if (!hasConversations && items.length === 0) {
+        return null;
+    }
+
+    return (
+        <Paper variant="outlined" role="region" aria-label="Deletion plan" sx={{p: 2}}>
+            <Stack spacing={1.5}>
+                {hasConversations && (
+                    <Stack direction={{xs: 'column', sm: 'row'}} spacing={1} useFlexGap alignItems={{sm: 'center'}} flexWrap="wrap">
+                        <TextField
+                            size="small"
+                            label="Filter conversations"
+                            value={query}
+                            onChange={(event) => setQuery(event.target.value)}
+                            sx={{flex: 1, minWidth: 200}}
+                        />
+                        <Button
+                            size="small"
+                            variant="outlined"
+                            disabled={query.trim() === ''}
+                            onClick={() => addConversations({query})}
+                        >
+                            Add matching
+                        </Button>
+                        <Button size="small" variant="outlined" onClick={() => addConversations({olderThanDays: oneYearInDays})}>
+                            Add older than a year
+                        </Button>
+                        <Button size="small" variant="outlined" onClick={() => addConversations({minSeverity: 'high'})}>
+                            Add sensitive
+                        </Button>
+                    </Stack>
+                )}
+                <Stack direction={{xs: 'column', sm: 'row'}} spacing={1} useFlexGap alignItems={{sm: 'center'}}>
+                    <Typography variant="body2" color="text.secondary" sx={{flex: 1}}>
+                        {items.length === 0 ? 'No conversations planned for deletion.' : formatPlanSummary(items)}
+                    </Typography>
+                    {items.length > 0 && (
+                        <>
+                            <Button size="small" onClick={() => exportPlan('markdown')}>
+                                Export Markdown
+                            </Button>
+                            <Button size="small" onClick={() => exportPlan('csv')}>
+                                Export CSV
+                            </Button>
+                        </>
+                    )}
+                </Stack>
+                {status && (
+                    <Alert severity="success" variant="outlined">
+                        {status}
+                    </Alert>
+                )}
+                {planError && (
+                    <Alert severity="error" variant="outlined">
+                        {planError}
+                    </Alert>
+                )}
+                {items.length > 0 && (
+                    <Stack spacing={0.5} role="list" aria-label="Planned deletions" sx={{maxHeight: 360, overflowY: 'auto'}}>
+                        {items.map((item) => {
+                            const name = item.conversationName.trim() || item.conversationId;
+                            return (
+                                <Stack
+                                    role="listitem"
+                                    key={`${item.provider ?? ''}-${item.conversationId}`}
+                                    direction="row"
+                                    spacing={1}
+                                    alignItems="center"
+                                >
+                                    <Checkbox
+                                        size="small"
+                                        checked={item.done}
+                                        inputProps={{'aria-label': `Mark ${name} as deleted`}}
+                                        onChange={(event) => applyPlanChange(
+                                            () => SetDeletionDone(item.provider ?? '', item.conversationId, event.target.checked),
+                                            'Failed to update the deletion plan.'
+                                        )}
+                                    />
+                                    <Stack spacing={0.25} sx={{flex: 1, minWidth: 0}}>
+                                        <Typography
+                                            variant="body2"
+                                            sx={{textDecoration: item.done ? 'line-through' : 'none'}}
+                                        >
+                                            {name}
+                                        </Typography>
+                                        <Typography variant="caption" color="text.secondary">
+                                            {[item.provider, ...item.reasons].filter(Boolean).join(' · ')}
+                                        </Typography>
+                                    </Stack>
+                                    {item.url && (
+                                        <Button size="small" onClick={() => BrowserOpenURL(item.url ?? '')}>
+                                            Open
+                                        </Button>
+                                    )}
+                                    <Button
+                                        size="small"
+                                        color="inherit"
+                                        onClick={() => applyPlanChange(
+                                            () => RemoveFromDeletionPlan(item.provider ?? '', item.conversationId),
+                                            'Failed to update the deletion plan.'
+                                        )}
+                                    >
+                                        Remove
+                                    </Button>
+                                </Stack>
+                            );
+                        })}
+                    </Stack>
+                )}
+            </Stack>
+        </Paper>
+    );
+}
@@ -0,0 +1,42 @@
+import React from 'react';
+import {cleanup, render, screen, within} from '@testing-library/react';
+import {afterEach, describe, expect, it} from 'vitest';
+
+import {DiagnosticsPanel} from './DiagnosticsPanel';
+
+describe('DiagnosticsPanel', () => {
+    afterEach(() => {
+        cleanup();
+    });
+
+    it('renders nothing when there are no warnings', () => {
+        const {container} = render(<DiagnosticsPanel warnings={[]} />);
+
+        expect(container.firstChild).toBeNull();
+    });
+
+    it('summarizes and lists each skipped conversation', () => {
+        render(
+            <DiagnosticsPanel
+                warnings={[
+                    {index: 3, conversationId: 'conv-bad', message: 'decode claude conversation: unexpected type'},
+                    {index: 7, conversationId: '', message: 'decode conversation: not an object'}
+                ]}
+            />
+        );
+
+        expect(screen.getByText('2 conversations could not be parsed and were skipped.')).toBeTruthy();
+
+        const items = within(screen.getByRole('list', {name: 'Parse warnings'})).getAllByRole('listitem');
+        expect(items.length).toBe(2);
+        expect(within(items[0]).getByText('Index 3 · conv-bad')).toBeTruthy();
+        expect(within(items[0]).getByText('decode claude conversation: unexpected type')).toBeTruthy();
+        expect(within(items[1]).getByText('Index 7')).toBeTruthy();
+    });
+
+    it('uses singular wording for a single warning', () => {
+        render(<DiagnosticsPanel warnings={[{index: 0, conversationId: 'only', message: 'broken'}]} />);
+
+        expect(screen.getByText('1 conversation could not be parsed and was skipped.')).toBeTruthy();
+    });
+});
@@ -0,0 +1,53 @@
+import React from 'react';
+import {Alert, Paper, Stack, Typography} from '@mui/material';
+
+import type {models} from '../../wailsjs/go/models';
+
+type ParseWarning = models.ParseWarning;
+
+type DiagnosticsPanelProps = {
+    warnings: ParseWarning[];
+};
+
+function formatWarningSummary(warningCount: number): string {
+    if (warningCount === 1) {
+        return '1 conversation could not be parsed and was skipped.';
+    }
+    return `${warningCount} conversations could not be parsed and were skipped.`;
+}
+
+function formatWarningLocation(warning: ParseWarning): string {
+    const conversationID = warning.conversationId.trim();
+    if (conversationID === '') {
+        return `Index ${warning.index}`;
+    }
+    return `Index ${warning.index} · ${conversationID}`;
+}
+
+export function DiagnosticsPanel({warnings}: DiagnosticsPanelProps) {
+    if (warnings.length === 0) {
+        return null;
+    }
+
+    return (
+        <Paper variant="outlined" role="region" aria-label="Load diagnostics" sx={{p: 2}}>
+            <Stack spacing={1.5}>
+                <Alert severity="warning" variant="outlined">
+                    {formatWarningSummary(warnings.length)}
+                </Alert>
+                <Stack spacing={1} role="list" aria-label="Parse warnings">
+                    {warnings.map((warning) => (
+                        <Stack role="listitem" key={`${warning.index}-${warning.conversationId}`} spacing={0.25}>
+                            <Typography variant="caption" sx={{fontWeight: 700}}>
+                                {formatWarningLocation(warning)}
+                            </Typography>
+                            <Typography variant="caption" color="text.secondary" sx={{wordBreak: 'break-word'}}>
+                                {warning.message}
+                            </Typography>
+                        </Stack>
+                    ))}
+                </Stack>
+            </Stack>
+        </Paper>
+    );
+}
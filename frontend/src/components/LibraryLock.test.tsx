@@ -0,0 +1,79 @@
+import React from 'react';
+import {cleanup, fireEvent, render, screen, waitFor} from '@testing-library/react';
+import {afterEach, beforeEach, describe, expect, it, vi} from 'vitest';
+
+import {LibraryLock} from './LibraryLock';
+import {EnableEncryption, GetStoreStatus, Unlock} from '../../wailsjs/go/main/App';
+
+vi.mock('../../wailsjs/go/main/App', () => ({
+    EnableEncryption: vi.fn(),
+    GetStoreStatus: vi.fn(),
+    Unlock: vi.fn()
+}));
+
+const mockedEnableEncryption = vi.mocked(EnableEncryption);
+const mockedGetStoreStatus = vi.mocked(GetStoreStatus);
+const mockedUnlock = vi.mocked(Unlock);
+
+describe('LibraryLock', () => {
+    beforeEach(() => {
+        mockedEnableEncryption.mockReset();
+        mockedGetStoreStatus.mockReset();
+        mockedUnlock.mockReset();
+    });
+
+    afterEach(() => {
+        cleanup();
+    });
+
+    it('asks for the passphrase of a locked library', async () => {
+        const onUnlocked = vi.fn();
+        mockedGetStoreStatus.mockResolvedValue({encrypted: true, locked: true});
+        mockedUnlock.mockRejectedValueOnce(new Error('incorrect passphrase')).mockResolvedValueOnce(undefined);
+
+        render(<LibraryLock onUnlocked={onUnlocked} />);
+
+        await waitFor(() => {
+            expect(screen.getByText('Unlock local library')).toBeTruthy();
+        });
+        fireEvent.change(screen.getByLabelText('Passphrase'), {target: {value: 'wrong'}});
+        fireEvent.click(screen.getByRole('button', {name: 'Unlock'}));
+
+        await waitFor(() => {
+            expect(screen.getByText('incorrect passphrase')).toBeTruthy();
+        });
+        expect(onUnlocked).not.toHaveBeenCalled();
+
+        fireEvent.change(screen.getByLabelText('Passphrase'), {target: {value: 'correct horse'}});
+        fireEvent.click(screen.getByRole('button', {name: 'Unlock'}));
+
+        await waitFor(() => {
+            expect(screen.getByText('Local data encrypted')).toBeTruthy();
+        });
+        expect(mockedUnlock).toHaveBeenLastCalledWith('correct horse');
+        expect(onUnlocked).toHaveBeenCalledTimes(1);
+    });
+
+    it('encrypts a plain library once the passphrase is confirmed', async () => {
+        mockedGetStoreStatus.mockResolvedValue({encrypted: false, locked: false});
+        mockedEnableEncryption.mockResolvedValue(undefined);
+
+        render(<LibraryLock onUnlocked={vi.fn()} />);
+
+        await waitFor(() => {
+            expect(screen.getByRole('button', {name: 'Encrypt local data'})).toBeTruthy();
+        });
+        fireEvent.click(screen.getByRole('button', {name: 'Encrypt local data'}));
+        fireEvent.change(screen.getByLabelText('New passphrase'), {target: {value: 'correct horse'}});
+        fireEvent.change(screen.getByLabelText('Confirm passphrase'), {target: {value: 'correct hors'}});
+        expect((screen.getByRole('button', {name: 'Encrypt'}) as HTMLButtonElement).disabled).toBe(true);
+
+        fireEvent.change(screen.getByLabelText('Confirm passphrase'), {target: {value: 'correct horse'}});
+        fireEvent.click(screen.getByRole('button', {name: 'Encrypt'}));
+
+        await waitFor(() => {
+            expect(screen.getByText('Local data encrypted')).toBeTruthy();
+        });
+        expect(mockedEnableEncryption).toHaveBeenCalledWith('correct horse');
+    });
+});
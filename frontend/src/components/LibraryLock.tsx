@@ -0,0 +1,176 @@
+import React, {useEffect, useState} from 'react';
+import {
+    Alert,
+    Button,
+    Dialog,
+    DialogActions,
+    DialogContent,
+    DialogContentText,
+    DialogTitle,
+    Stack,
+    TextField,
+    Typography
+} from '@mui/material';
+
+import {EnableEncryption, GetStoreStatus, Unlock} from '../../wailsjs/go/main/App';
+
+type StoreStatus = {
+    encrypted: boolean;
+    locked: boolean;
+};
+
+type LibraryLockProps = {
+    // onUnlocked runs once saved data becomes readable, after an unlock.
+    onUnlocked: () => void;
+};
+
+function errorMessage(failure: unknown, fallback: string): string {
+    return failure instanceof Error ? failure.message : fallback;
+}
+
+// LibraryLock asks for the passphrase of an encrypted local library on startup and lets
+// a plain library be encrypted.
+export function LibraryLock({onUnlocked}: LibraryLockProps) {
+    const [status, setStatus] = useState<StoreStatus | null>(null);
+    const [passphrase, setPassphrase] = useState('');
+    const [confirmation, setConfirmation] = useState('');
+    const [isEncryptDialogOpen, setIsEncryptDialogOpen] = useState(false);
+    const [isWorking, setIsWorking] = useState(false);
+    const [lockError, setLockError] = useState('');
+
+    useEffect(() => {
+        GetStoreStatus()
+            .then((storeStatus) => setStatus(storeStatus))
+            .catch((statusFailure: unknown) => setLockError(errorMessage(statusFailure, 'Failed to open the local library.')));
+    }, []);
+
+    const unlock = async () => {
+        setIsWorking(true);
+        setLockError('');
+        try {
+            await Unlock(passphrase);
+            setPassphrase('');
+            setStatus({encrypted: true, locked: false});
+            onUnlocked();
+        } catch (unlockFailure: unknown) {
+            setLockError(errorMessage(unlockFailure, 'Failed to unlock the local library.'));
+        } finally {
+            setIsWorking(false);
+        }
+    };
+
+    const encrypt = async () => {
+        setIsWorking(true);
+        setLockError('');
+        try {
+            await EnableEncryption(passphrase);
+            setPassphrase('');
+            setConfirmation('');
+            setIsEncryptDialogOpen(false);
+            setStatus({encrypted: true, locked: false});
+        } catch (encryptFailure: unknown) {
+            setLockError(errorMessage(encryptFailure, 'Failed to encrypt the local library.'));
+        } finally {
+            setIsWorking(false);
+        }
+    };
+
+    const closeEncryptDialog = () => {
+        setIsEncryptDialogOpen(false);
+        setPassphrase('');
+        setConfirmation('');
+        setLockError('');
+    };
+
+    if (status === null) {
+        return lockError ? <Typography variant="body2" color="error">{lockError}</Typography> : null;
+    }
+
+    if (status.locked) {
+        return (
+            <Dialog open aria-labelledby="unlock-library-title">
+                <DialogTitle id="unlock-library-title">Unlock local library</DialogTitle>
+                <DialogContent>
+                    <Stack spacing={2}>
+                        <DialogContentText>
+                            Saved data such as the deletion plan is encrypted. Enter the passphrase to read it.
+                        </DialogContentText>
+                        <TextField
+                            autoFocus
+                            type="password"
+                            label="Passphrase"
+                            value={passphrase}
+                            onChange={(event) => setPassphrase(event.target.value)}
+                            onKeyDown={(event) => {
+                                if (event.key === 'Enter' && passphrase !== '') {
+                                    void unlock();
+                                }
+                            }}
+                        />
+                        {lockError && (
+                            <Alert severity="error" variant="outlined">
+                                {lockError}
+                            </Alert>
+                        )}
+                    </Stack>
+                </DialogContent>
+                <DialogActions>
+                    <Button variant="contained" onClick={unlock} disabled={isWorking || passphrase === ''}>
+                        Unlock
+                    </Button>
+                </DialogActions>
+            </Dialog>
+        );
+    }
+
+    if (status.encrypted) {
+        return (
+            <Typography variant="body2" color="text.secondary">
+                Local data encrypted
+            </Typography>
+        );
+    }
+
+    const passphrasesMatch = passphrase !== '' && passphrase === confirmation;
+    return (
+        <>
+            <Button size="small" onClick={() => setIsEncryptDialogOpen(true)}>
+                Encrypt local data
+            </Button>
+            <Dialog open={isEncryptDialogOpen} onClose={closeEncryptDialog} aria-labelledby="encrypt-library-title">
+                <DialogTitle id="encrypt-library-title">Encrypt local data</DialogTitle>
+                <DialogContent>
+                    <Stack spacing={2}>
+                        <DialogContentText>
+                            Everything the app saves will be encrypted with this passphrase. It cannot be recovered if it is lost.
+                        </DialogContentText>
+                        <TextField
+                            autoFocus
+                            type="password"
+                            label="New passphrase"
+                            value={passphrase}
+                            onChange={(event) => setPassphrase(event.target.value)}
+                        />
+                        <TextField
+                            type="password"
+                            label="Confirm passphrase"
+                            value={confirmation}
+                            onChange={(event) => setConfirmation(event.target.value)}
+                        />
+                        {lockError && (
+                            <Alert severity="error" variant="outlined">
+                                {lockError}
+                            </Alert>
+                        )}
+                    </Stack>
+                </DialogContent>
+                <DialogActions>
+                    <Button onClick={closeEncryptDialog}>Cancel</Button>
+                    <Button variant="contained" onClick={encrypt} disabled={isWorking || !passphrasesMatch}>
+                        Encrypt
+                    </Button>
+                </DialogActions>
+            </Dialog>
+        </>
+    );
+}
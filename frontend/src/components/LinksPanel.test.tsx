@@ -0,0 +1,53 @@
+import React from 'react';
+import {cleanup, render, screen, within} from '@testing-library/react';
+import {afterEach, describe, expect, it} from 'vitest';
+
+import {LinksPanel} from './LinksPanel';
+
+describe('LinksPanel', () => {
+    afterEach(() => {
+        cleanup();
+    });
+
+    it('renders nothing when no links were found', () => {
+        const {container} = render(<LinksPanel links={[]} />);
+
+        expect(container.firstChild).toBeNull();
+    });
+
+    it('lists links with their usage', () => {
+        render(
+            <LinksPanel
+                links={[
+                    {
+                        url: 'https://go.dev/blog',
+                        title: 'The Go Blog',
+                        domain: 'go.dev',
+                        mentions: 3,
+                        citations: 1,
+                        conversations: [
+                            {conversationId: 'conv-1', conversationName: 'Go news'},
+                            {conversationId: 'conv-2', conversationName: 'Packages'}
+                        ]
+                    },
+                    {
+                        url: 'https://pkg.go.dev',
+                        domain: 'pkg.go.dev',
+                        mentions: 1,
+                        citations: 0,
+                        conversations: [{conversationId: 'conv-2', conversationName: 'Packages'}]
+                    }
+                ]}
+            />
+        );
+
+        expect(screen.getByText('2 links referenced in this export.')).toBeTruthy();
+
+        const items = within(screen.getByRole('list', {name: 'Links'})).getAllByRole('listitem');
+        expect(items.length).toBe(2);
+        expect(within(items[0]).getByText('The Go Blog')).toBeTruthy();
+        expect(within(items[0]).getByText('https://go.dev/blog')).toBeTruthy();
+        expect(within(items[0]).getByText('3 mentions · cited 1× · 2 conversations')).toBeTruthy();
+        expect(within(items[1]).getByText('1 mention · 1 conversation')).toBeTruthy();
+    });
+});
@@ -0,0 +1,64 @@
+import React from 'react';
+import {Paper, Stack, Typography} from '@mui/material';
+
+import type {models} from '../../wailsjs/go/models';
+
+// Generated Link gains convertValues for its nested references; the data is plain JSON.
+export type Link = Omit<models.Link, 'convertValues'>;
+
+type LinksPanelProps = {
+    links: Link[];
+};
+
+// The catalog can hold thousands of links; the most referenced ones come first.
+const visibleLinkLimit = 100;
+
+function formatLinkSummary(linkCount: number): string {
+    if (linkCount === 1) {
+        return '1 link referenced in this export.';
+    }
+    return `${linkCount} links referenced in this export.`;
+}
+
+function formatLinkUsage(link: Link): string {
+    const parts = [link.mentions === 1 ? '1 mention' : `${link.mentions} mentions`];
+    if (link.citations > 0) {
+        parts.push(`cited ${link.citations}×`);
+    }
+    const conversationCount = link.conversations.length;
+    parts.push(conversationCount === 1 ? '1 conversation' : `${conversationCount} conversations`);
+    return parts.join(' · ');
+}
+
+export function LinksPanel({links}: LinksPanelProps) {
+    if (links.length === 0) {
+        return null;
+    }
+
+    return (
+        <Paper variant="outlined" role="region" aria-label="Reading list" sx={{p: 2}}>
+            <Stack spacing={1.5}>
+                <Typography variant="body2" color="text.secondary">
+                    {formatLinkSummary(links.length)}
+                </Typography>
+                <Stack spacing={1} role="list" aria-label="Links" sx={{maxHeight: 320, overflowY: 'auto'}}>
+                    {links.slice(0, visibleLinkLimit).map((link) => (
+                        <Stack role="listitem" key={link.url} spacing={0.25}>
+                            {link.title && (
+                                <Typography variant="caption" sx={{fontWeight: 700}}>
+                                    {link.title}
+                                </Typography>
+                            )}
+                            <Typography variant="caption" sx={{wordBreak: 'break-all'}}>
+                                {link.url}
+                            </Typography>
+                            <Typography variant="caption" color="text.secondary">
+                                {formatLinkUsage(link)}
+                            </Typography>
+                        </Stack>
+                    ))}
+                </Stack>
+            </Stack>
+        </Paper>
+    );
+}
@@ -0,0 +1,67 @@
+import React from 'react';
+import {cleanup, fireEvent, render, screen, waitFor} from '@testing-library/react';
+import {afterEach, beforeEach, describe, expect, it, vi} from 'vitest';
+
+import {RecentFilesMenu} from './RecentFilesMenu';
+import {ClearRecentFiles, GetRecentFiles} from '../../wailsjs/go/main/App';
+
+vi.mock('../../wailsjs/go/main/App', () => ({
+    ClearRecentFiles: vi.fn(),
+    GetRecentFiles: vi.fn()
+}));
+
+const mockedClearRecentFiles = vi.mocked(ClearRecentFiles);
+const mockedGetRecentFiles = vi.mocked(GetRecentFiles);
+
+const recentFiles = [
+    {path: '/exports/claude-export.zip', name: 'claude-export.zip', exists: true},
+    {path: '/exports/moved.json', name: 'moved.json', exists: false}
+];
+
+describe('RecentFilesMenu', () => {
+    beforeEach(() => {
+        mockedClearRecentFiles.mockReset();
+        mockedGetRecentFiles.mockReset();
+    });
+
+    afterEach(() => {
+        cleanup();
+    });
+
+    it('opens a recent file and disables missing ones', async () => {
+        const onOpen = vi.fn();
+        mockedGetRecentFiles.mockResolvedValue(recentFiles);
+
+        render(<RecentFilesMenu disabled={false} onOpen={onOpen} />);
+
+        fireEvent.click(screen.getByRole('button', {name: 'Recent files'}));
+
+        const missingFile = await screen.findByRole('menuitem', {name: /moved\.json/});
+        expect(missingFile.getAttribute('aria-disabled')).toBe('true');
+        expect(screen.getByText('File not found')).toBeTruthy();
+
+        fireEvent.click(screen.getByRole('menuitem', {name: /claude-export\.zip/}));
+
+        expect(onOpen).toHaveBeenCalledWith('/exports/claude-export.zip');
+    });
+
+    it('clears the recent files', async () => {
+        mockedGetRecentFiles.mockResolvedValue(recentFiles);
+        mockedClearRecentFiles.mockResolvedValue(undefined);
+
+        render(<RecentFilesMenu disabled={false} onOpen={vi.fn()} />);
+
+        fireEvent.click(screen.getByRole('button', {name: 'Recent files'}));
+        fireEvent.click(await screen.findByRole('menuitem', {name: 'Clear recent files'}));
+
+        expect(mockedClearRecentFiles).toHaveBeenCalledTimes(1);
+
+        // The button is hidden from the accessibility tree until the menu finishes closing.
+        mockedGetRecentFiles.mockResolvedValue([]);
+        await waitFor(() => {
+            fireEvent.click(screen.getByRole('button', {name: 'Recent files'}));
+        });
+
+        expect(await screen.findByRole('menuitem', {name: 'No recent files'})).toBeTruthy();
+    });
+});
@@ -0,0 +1,70 @@
+import React, {useState} from 'react';
+import {Button, Divider, ListItemText, Menu, MenuItem} from '@mui/material';
+
+import {ClearRecentFiles, GetRecentFiles} from '../../wailsjs/go/main/App';
+import type {models} from '../../wailsjs/go/models';
+
+type RecentFile = models.RecentFile;
+
+type RecentFilesMenuProps = {
+    disabled: boolean;
+    // onOpen loads the chosen export.
+    onOpen: (path: string) => void;
+};
+
+// RecentFilesMenu lists the recently opened exports. The list is fetched when the menu
+// opens, so files moved or deleted since are shown disabled.
+export function RecentFilesMenu({disabled, onOpen}: RecentFilesMenuProps) {
+    const [anchorElement, setAnchorElement] = useState<HTMLElement | null>(null);
+    const [files, setFiles] = useState<RecentFile[]>([]);
+
+    const openMenu = (anchor: HTMLElement) => {
+        setAnchorElement(anchor);
+        GetRecentFiles()
+            .then((recentFiles) => setFiles(recentFiles ?? []))
+            .catch(() => setFiles([]));
+    };
+
+    const closeMenu = () => {
+        setAnchorElement(null);
+    };
+
+    const chooseFile = (path: string) => {
+        closeMenu();
+        onOpen(path);
+    };
+
+    const clearFiles = () => {
+        closeMenu();
+        setFiles([]);
+        ClearRecentFiles().catch(() => undefined);
+    };
+
+    return (
+        <>
+            <Button
+                size="small"
+                aria-haspopup="menu"
+                aria-expanded={anchorElement !== null}
+                disabled={disabled}
+                onClick={(event) => openMenu(event.currentTarget)}
+            >
+                Recent files
+            </Button>
+            <Menu anchorEl={anchorElement} open={anchorElement !== null} onClose={closeMenu}>
+                {files.length === 0 && (
+                    <MenuItem disabled>No recent files</MenuItem>
+                )}
+                {files.map((file) => (
+                    <MenuItem key={file.path} disabled={!file.exists} title={file.path} onClick={() => chooseFile(file.path)}>
+                        <ListItemText primary={file.name} secondary={file.exists ? file.path : 'File not found'} />
+                    </MenuItem>
+                ))}
+                {files.length > 0 && <Divider />}
+                {files.length > 0 && (
+                    <MenuItem onClick={clearFiles}>Clear recent files</MenuItem>
+                )}
+            </Menu>
+        </>
+    );
+}
@@ -0,0 +1,100 @@
+import React from 'react';
+import {cleanup, fireEvent, render, screen, waitFor, within} from '@testing-library/react';
+import {afterEach, beforeEach, describe, expect, it, vi} from 'vitest';
+
+import {SensitiveDataPanel} from './SensitiveDataPanel';
+import {ExportRedacted, ScanSensitiveData} from '../../wailsjs/go/main/App';
+
+vi.mock('../../wailsjs/go/main/App', () => ({
+    ExportRedacted: vi.fn(),
+    ScanSensitiveData: vi.fn()
+}));
+
+const mockedExportRedacted = vi.mocked(ExportRedacted);
+const mockedScanSensitiveData = vi.mocked(ScanSensitiveData);
+
+describe('SensitiveDataPanel', () => {
+    beforeEach(() => {
+        mockedExportRedacted.mockReset();
+        mockedScanSensitiveData.mockReset();
+    });
+
+    afterEach(() => {
+        cleanup();
+    });
+
+    it('renders nothing before conversations are loaded', () => {
+        const {container} = render(<SensitiveDataPanel conversationSetVersion={0} hasConversations={false} />);
+
+        expect(container.firstChild).toBeNull();
+    });
+
+    it('lists findings with severity and location after a scan', async () => {
+        mockedScanSensitiveData.mockResolvedValue([
+            {
+                type: 'aws_credential',
+                severity: 'critical',
+                conversationId: 'conv-1',
+                conversationName: 'Deploy',
+                messageIndex: 1,
+                speaker: 'human',
+                messageTimestamp: '',
+                attachment: '.env',
+                start: 18,
+                end: 38,
+                line: 2,
+                column: 3,
+                preview: 'AKIA…LE'
+            },
+            {
+                type: 'email',
+                severity: 'low',
+                conversationId: 'conv-2',
+                conversationName: '',
+                messageIndex: 0,
+                speaker: 'human',
+                messageTimestamp: '',
+                start: 0,
+                end: 18,
+                line: 1,
+                column: 1,
+                preview: 'jane…om'
+            }
+        ]);
+
+        render(<SensitiveDataPanel conversationSetVersion={0} hasConversations />);
+        fireEvent.click(screen.getByRole('button', {name: 'Scan for sensitive data'}));
+
+        await waitFor(() => {
+            expect(screen.getByText('2 findings: 1 critical, 1 low.')).toBeTruthy();
+        });
+        const items = within(screen.getByRole('list', {name: 'Findings'})).getAllByRole('listitem');
+        expect(within(items[0]).getByText('AWS credential')).toBeTruthy();
+        expect(within(items[0]).getByText('AKIA…LE')).toBeTruthy();
+        expect(within(items[0]).getByText('Deploy · message 2 · .env · line 2, column 3')).toBeTruthy();
+        expect(within(items[1]).getByText('conv-2 · message 1 · line 1, column 1')).toBeTruthy();
+    });
+
+    it('reports a clean scan', async () => {
+        mockedScanSensitiveData.mockResolvedValue([]);
+
+        render(<SensitiveDataPanel conversationSetVersion={0} hasConversations />);
+        fireEvent.click(screen.getByRole('button', {name: 'Scan for sensitive data'}));
+
+        await waitFor(() => {
+            expect(screen.getByText('No sensitive data found.')).toBeTruthy();
+        });
+        expect(screen.queryByRole('list', {name: 'Findings'})).toBeNull();
+    });
+
+    it('reports how many values the redacted copy replaced', async () => {
+        mockedExportRedacted.mockResolvedValue(3);
+
+        render(<SensitiveDataPanel conversationSetVersion={0} hasConversations />);
+        fireEvent.click(screen.getByRole('button', {name: 'Export redacted copy'}));
+
+        await waitFor(() => {
+            expect(screen.getByText('Saved a redacted copy with 3 values replaced.')).toBeTruthy();
+        });
+    });
+});
@@ -0,0 +1,163 @@
+import React, {useEffect, useState} from 'react';
+import {Alert, Box, Button, Chip, Paper, Stack, Typography} from '@mui/material';
+
+import {ExportRedacted, ScanSensitiveData} from '../../wailsjs/go/main/App';
+import type {models} from '../../wailsjs/go/models';
+
+type SensitiveFinding = models.SensitiveFinding;
+
+type SensitiveDataPanelProps = {
+    conversationSetVersion: number;
+    hasConversations: boolean;
+};
+
+const visibleFindingLimit = 100;
+
+const severityOrder = ['critical', 'high', 'medium', 'low'];
+
+const severityColors: Record<string, 'error' | 'warning' | 'info' | 'default'> = {
+    critical: 'error',
+    high: 'warning',
+    medium: 'info',
+    low: 'default'
+};
+
+const findingTypeLabels: Record<string, string> = {
+    private_key: 'Private key',
+    aws_credential: 'AWS credential',
+    gcp_credential: 'GCP credential',
+    api_key: 'API key',
+    jwt: 'JWT',
+    credit_card: 'Credit card',
+    national_id: 'National ID',
+    iban: 'IBAN',
+    phone_number: 'Phone number',
+    email: 'Email'
+};
+
+function formatFindingSummary(findings: SensitiveFinding[]): string {
+    if (findings.length === 0) {
+        return 'No sensitive data found.';
+    }
+
+    const counts = severityOrder
+        .map((severity) => [severity, findings.filter((finding) => finding.severity === severity).length] as const)
+        .filter(([, count]) => count > 0)
+        .map(([severity, count]) => `${count} ${severity}`);
+    const noun = findings.length === 1 ? 'finding' : 'findings';
+    return `${findings.length} ${noun}: ${counts.join(', ')}.`;
+}
+
+function formatFindingLocation(finding: SensitiveFinding): string {
+    const parts = [
+        finding.conversationName.trim() || finding.conversationId,
+        `message ${finding.messageIndex + 1}`
+    ];
+    if (finding.attachment) {
+        parts.push(finding.attachment);
+    }
+    parts.push(`line ${finding.line}, column ${finding.column}`);
+    return parts.join(' · ');
+}
+
+export function SensitiveDataPanel({conversationSetVersion, hasConversations}: SensitiveDataPanelProps) {
+    const [findings, setFindings] = useState<SensitiveFinding[] | null>(null);
+    const [isScanning, setIsScanning] = useState(false);
+    const [scanError, setScanError] = useState('');
+    const [exportStatus, setExportStatus] = useState('');
+
+    useEffect(() => {
+        setFindings(null);
+        setScanError('');
+        setExportStatus('');
+    }, [conversationSetVersion]);
+
+    const scan = async () => {
+        setIsScanning(true);
+        setScanError('');
+        try {
+            const scannedFindings = await ScanSensitiveData();
+            setFindings(scannedFindings ?? []);
+        } catch (scanFailure: unknown) {
+            setScanError(scanFailure instanceof Error ? scanFailure.message : 'Failed to scan for sensitive data.');
+        } finally {
+            setIsScanning(false);
+        }
+    };
+
+    const exportRedacted = async () => {
+        setScanError('');
+        setExportStatus('');
+        try {
+            const redactedCount = await ExportRedacted();
+            if (redactedCount > 0) {
+                setExportStatus(`Saved a redacted copy with ${redactedCount} ${redactedCount === 1 ? 'value' : 'values'} replaced.`);
+            }
+        } catch (exportFailure: unknown) {
+            setScanError(exportFailure instanceof Error ? exportFailure.message : 'Failed to save a redacted copy.');
+        }
+    };
+
+    if (!hasConversations) {
+        return null;
+    }
+
+    return (
+        <Paper variant="outlined" role="region" aria-label="Sensitive data" sx={{p: 2}}>
+            <Stack spacing={1.5}>
+                <Stack direction={{xs: 'column', sm: 'row'}} spacing={1} useFlexGap alignItems={{sm: 'center'}}>
+                    <Button size="small" variant="outlined" onClick={scan} disabled={isScanning}>
+                        {isScanning ? 'Scanning...' : 'Scan for sensitive data'}
+                    </Button>
+                    <Button size="small" variant="outlined" onClick={exportRedacted}>
+                        Export redacted copy
+                    </Button>
+                    {findings !== null && (
+                        <Typography variant="body2" color="text.secondary">
+                            {formatFindingSummary(findings)}
+                        </Typography>
+                    )}
+                </Stack>
+                {exportStatus && (
+                    <Alert severity="success" variant="outlined">
+                        {exportStatus}
+                    </Alert>
+                )}
+                {scanError && (
+                    <Alert severity="error" variant="outlined">
+                        {scanError}
+                    </Alert>
+                )}
+                {findings !== null && findings.length > 0 && (
+                    <Stack spacing={1} role="list" aria-label="Findings" sx={{maxHeight: 360, overflowY: 'auto'}}>
+                        {findings.slice(0, visibleFindingLimit).map((finding, index) => (
+                            <Stack
+                                role="listitem"
+                                key={`${finding.conversationId}-${finding.messageIndex}-${finding.attachment ?? ''}-${finding.start}-${index}`}
+                                spacing={0.5}
+                            >
+                                <Stack direction="row" spacing={1} alignItems="center">
+                                    <Chip
+                                        label={finding.severity}
+                                        size="small"
+                                        color={severityColors[finding.severity] ?? 'default'}
+                                        variant="outlined"
+                                    />
+                                    <Typography variant="caption" sx={{fontWeight: 700}}>
+                                        {findingTypeLabels[finding.type] ?? finding.type}
+                                    </Typography>
+                                    <Box component="code" sx={{fontSize: 12}}>
+                                        {finding.preview}
+                                    </Box>
+                                </Stack>
+                                <Typography variant="caption" color="text.secondary">
+                                    {formatFindingLocation(finding)}
+                                </Typography>
+                            </Stack>
+                        ))}
+                    </Stack>
+                )}
+            </Stack>
+        </Paper>
+    );
+}
@@ -0,0 +1,162 @@
+import React from 'react';
+import {cleanup, fireEvent, render, screen, waitFor, within} from '@testing-library/react';
+import {afterEach, beforeEach, describe, expect, it, vi} from 'vitest';
+
+import {SmartCollectionsPanel} from './SmartCollectionsPanel';
+import {DeleteSavedSearch, FilterConversations, GetSmartCollections, SaveSearch} from '../../wailsjs/go/main/App';
+import type {models} from '../../wailsjs/go/models';
+
+vi.mock('../../wailsjs/go/main/App', () => ({
+    DeleteSavedSearch: vi.fn(),
+    FilterConversations: vi.fn(),
+    GetSmartCollections: vi.fn(),
+    SaveSearch: vi.fn()
+}));
+
+const mockedDeleteSavedSearch = vi.mocked(DeleteSavedSearch);
+const mockedFilterConversations = vi.mocked(FilterConversations);
+const mockedGetSmartCollections = vi.mocked(GetSmartCollections);
+const mockedSaveSearch = vi.mocked(SaveSearch);
+
+const annotations = {conversations: []};
+
+const healthCollection = {
+    id: 'health-questions',
+    name: 'Health questions',
+    query: 'tag:health',
+    count: 2,
+    conversations: [
+        {provider: 'claude', conversationId: 'conv-1'},
+        {provider: 'chatgpt', conversationId: 'conv-2'}
+    ]
+};
+
+// Bound methods resolve to generated model classes; the fixtures are plain objects.
+function asCollections(collections: object[]): models.SmartCollection[] {
+    return collections as models.SmartCollection[];
+}
+
+describe('SmartCollectionsPanel', () => {
+    beforeEach(() => {
+        mockedDeleteSavedSearch.mockReset();
+        mockedFilterConversations.mockReset();
+        mockedGetSmartCollections.mockReset();
+        mockedSaveSearch.mockReset();
+    });
+
+    afterEach(() => {
+        cleanup();
+    });
+
+    it('renders nothing without conversations or saved searches', async () => {
+        mockedGetSmartCollections.mockResolvedValue([]);
+
+        const {container} = render(
+            <SmartCollectionsPanel conversationSetVersion={0} hasConversations={false} annotations={annotations} filter={null} onFilterChange={vi.fn()} />
+        );
+
+        await waitFor(() => {
+            expect(mockedGetSmartCollections).toHaveBeenCalledTimes(1);
+        });
+        expect(container.firstChild).toBeNull();
+    });
+
+    it('lists saved searches with counts and selects one as the filter', async () => {
+        const onFilterChange = vi.fn();
+        mockedGetSmartCollections.mockResolvedValue(asCollections([healthCollection]));
+
+        render(
+            <SmartCollectionsPanel conversationSetVersion={0} hasConversations annotations={annotations} filter={null} onFilterChange={onFilterChange} />
+        );
+
+        const list = await screen.findByRole('list', {name: 'Saved searches'});
+        fireEvent.click(within(list).getByRole('button', {name: 'Health questions (2)'}));
+
+        expect(onFilterChange).toHaveBeenCalledWith({
+            label: 'Health questions',
+            query: 'tag:health',
+            collectionId: 'health-questions',
+            conversations: healthCollection.conversations
+        });
+    });
+
+    it('filters by a query and saves it as a collection', async () => {
+        const onFilterChange = vi.fn();
+        mockedGetSmartCollections.mockResolvedValue([]);
+        mockedFilterConversations.mockResolvedValue([{provider: 'claude', conversationId: 'conv-1'}]);
+        mockedSaveSearch.mockResolvedValue(asCollections([healthCollection]));
+
+        render(
+            <SmartCollectionsPanel conversationSetVersion={0} hasConversations annotations={annotations} filter={null} onFilterChange={onFilterChange} />
+        );
+
+        fireEvent.change(screen.getByLabelText('Query'), {target: {value: ' tag:health '}});
+        fireEvent.click(screen.getByRole('button', {name: 'Filter'}));
+
+        await waitFor(() => {
+            expect(onFilterChange).toHaveBeenCalledWith({
+                label: 'tag:health',
+                query: 'tag:health',
+                conversations: [{provider: 'claude', conversationId: 'conv-1'}]
+            });
+        });
+
+        fireEvent.change(screen.getByLabelText('Collection name'), {target: {value: 'Health questions'}});
+        fireEvent.click(screen.getByRole('button', {name: 'Save search'}));
+
+        await waitFor(() => {
+            expect(screen.getByRole('button', {name: 'Health questions (2)'})).toBeTruthy();
+        });
+        expect(mockedSaveSearch).toHaveBeenCalledWith('Health questions', 'tag:health');
+    });
+
+    it('re-evaluates the active collection and clears it once deleted', async () => {
+        const onFilterChange = vi.fn();
+        const activeFilter = {
+            label: 'Health questions',
+            query: 'tag:health',
+            collectionId: 'health-questions',
+            conversations: []
+        };
+        mockedGetSmartCollections.mockResolvedValue(asCollections([healthCollection]));
+        mockedDeleteSavedSearch.mockResolvedValue([]);
+
+        render(
+            <SmartCollectionsPanel
+                conversationSetVersion={1}
+                hasConversations
+                annotations={annotations}
+                filter={activeFilter}
+                onFilterChange={onFilterChange}
+            />
+        );
+
+        await waitFor(() => {
+            expect(onFilterChange).toHaveBeenCalledWith(expect.objectContaining({conversations: healthCollection.conversations}));
+        });
+        expect(screen.getByText('Showing Health questions (0)')).toBeTruthy();
+
+        fireEvent.click(screen.getByRole('button', {name: 'Delete Health questions'}));
+
+        await waitFor(() => {
+            expect(onFilterChange).toHaveBeenLastCalledWith(null);
+        });
+        expect(mockedDeleteSavedSearch).toHaveBeenCalledWith('health-questions');
+    });
+
+    it('shows query errors', async () => {
+        mockedGetSmartCollections.mockResolvedValue([]);
+        mockedFilterConversations.mockRejectedValue(new Error('parse query: unknown filter is:pinned'));
+
+        render(
+            <SmartCollectionsPanel conversationSetVersion={0} hasConversations annotations={annotations} filter={null} onFilterChange={vi.fn()} />
+        );
+
+        fireEvent.change(screen.getByLabelText('Query'), {target: {value: 'is:pinned'}});
+        fireEvent.click(screen.getByRole('button', {name: 'Filter'}));
+
+        await waitFor(() => {
+            expect(screen.getByText('parse query: unknown filter is:pinned')).toBeTruthy();
+        });
+    });
+});
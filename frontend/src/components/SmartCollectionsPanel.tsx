@@ -0,0 +1,211 @@
+import React, {useEffect, useRef, useState} from 'react';
+import {Alert, Button, Paper, Stack, TextField, Typography} from '@mui/material';
+
+import {DeleteSavedSearch, FilterConversations, GetSmartCollections, SaveSearch} from '../../wailsjs/go/main/App';
+import type {models} from '../../wailsjs/go/models';
+import type {Annotations} from './ConversationAnnotations';
+
+type SmartCollection = Omit<models.SmartCollection, 'convertValues'>;
+type CollectionConversation = models.CollectionConversation;
+
+// ConversationFilter limits the conversation list to the conversations a query or a
+// smart collection matched.
+export type ConversationFilter = {
+    label: string;
+    query: string;
+    collectionId?: string;
+    conversations: CollectionConversation[];
+};
+
+type SmartCollectionsPanelProps = {
+    conversationSetVersion: number;
+    hasConversations: boolean;
+    annotations: Annotations;
+    filter: ConversationFilter | null;
+    onFilterChange: (filter: ConversationFilter | null) => void;
+};
+
+function collectionFilter(collection: SmartCollection): ConversationFilter {
+    return {
+        label: collection.name,
+        query: collection.query,
+        collectionId: collection.id,
+        conversations: collection.conversations ?? []
+    };
+}
+
+function errorMessage(failure: unknown, fallback: string): string {
+    return failure instanceof Error ? failure.message : fallback;
+}
+
+// SmartCollectionsPanel filters the conversation list with a query and saves queries as
+// named collections. Collections are re-evaluated after every load and annotation change,
+// since tags and stars take part in queries.
+export function SmartCollectionsPanel({
+    conversationSetVersion,
+    hasConversations,
+    annotations,
+    filter,
+    onFilterChange
+}: SmartCollectionsPanelProps) {
+    const [collections, setCollections] = useState<SmartCollection[]>([]);
+    const [query, setQuery] = useState('');
+    const [name, setName] = useState('');
+    const [panelError, setPanelError] = useState('');
+    const filterRef = useRef(filter);
+    filterRef.current = filter;
+
+    useEffect(() => {
+        let isCurrent = true;
+        const refresh = async () => {
+            setPanelError('');
+            try {
+                const loadedCollections = (await GetSmartCollections()) ?? [];
+                if (!isCurrent) {
+                    return;
+                }
+                setCollections(loadedCollections);
+
+                const activeFilter = filterRef.current;
+                if (activeFilter?.collectionId) {
+                    const collection = loadedCollections.find((candidate) => candidate.id === activeFilter.collectionId);
+                    onFilterChange(collection ? collectionFilter(collection) : null);
+                } else if (activeFilter) {
+                    const conversations = (await FilterConversations(activeFilter.query)) ?? [];
+                    if (isCurrent) {
+                        onFilterChange({...activeFilter, conversations});
+                    }
+                }
+            } catch (refreshFailure: unknown) {
+                if (isCurrent) {
+                    setCollections([]);
+                    setPanelError(errorMessage(refreshFailure, 'Failed to load smart collections.'));
+                }
+            }
+        };
+        void refresh();
+
+        return () => {
+            isCurrent = false;
+        };
+        // onFilterChange is a state setter in App and stays the same between renders.
+    }, [conversationSetVersion, annotations]);
+
+    const applyQuery = async () => {
+        setPanelError('');
+        try {
+            const trimmedQuery = query.trim();
+            const conversations = (await FilterConversations(trimmedQuery)) ?? [];
+            onFilterChange({label: trimmedQuery, query: trimmedQuery, conversations});
+        } catch (filterFailure: unknown) {
+            setPanelError(errorMessage(filterFailure, 'Failed to filter conversations.'));
+        }
+    };
+
+    const saveSearch = async () => {
+        setPanelError('');
+        try {
+            setCollections((await SaveSearch(name, query.trim())) ?? []);
+            setName('');
+        } catch (saveFailure: unknown) {
+            setPanelError(errorMessage(saveFailure, 'Failed to save search.'));
+        }
+    };
+
+    const deleteCollection = async (collection: SmartCollection) => {
+        setPanelError('');
+        try {
+            setCollections((await DeleteSavedSearch(collection.id)) ?? []);
+            if (filter?.collectionId === collection.id) {
+                onFilterChange(null);
+            }
+        } catch (deleteFailure: unknown) {
+            setPanelError(errorMessage(deleteFailure, 'Failed to delete saved search.'));
+        }
+    };
+
+    if (!hasConversations && collections.length === 0) {
+        return null;
+    }
+
+    return (
+        <Paper variant="outlined" role="region" aria-label="Smart collections" sx={{p: 2}}>
+            <Stack spacing={1.5}>
+                <Stack
+                    component="form"
+                    direction={{xs: 'column', sm: 'row'}}
+                    spacing={1}
+                    useFlexGap
+                    alignItems={{sm: 'flex-start'}}
+                    onSubmit={(event: React.FormEvent) => {
+                        event.preventDefault();
+                        void applyQuery();
+                    }}
+                >
+                    <TextField
+                        size="small"
+                        label="Query"
+                        placeholder='review tag:work -provider:chatgpt "exact phrase"'
+                        helperText="Also provider:, is:starred, is:noted, has:code, has:attachment, model:, before:YYYY-MM-DD and after:YYYY-MM-DD"
+                        value={query}
+                        onChange={(event) => setQuery(event.target.value)}
+                        sx={{flex: 2}}
+                    />
+                    <Button type="submit" size="small" variant="outlined" disabled={query.trim() === ''}>
+                        Filter
+                    </Button>
+                    <TextField
+                        size="small"
+                        label="Collection name"
+                        value={name}
+                        onChange={(event) => setName(event.target.value)}
+                        sx={{flex: 1}}
+                    />
+                    <Button
+                        size="small"
+                        variant="outlined"
+                        disabled={query.trim() === '' || name.trim() === ''}
+                        onClick={() => void saveSearch()}
+                    >
+                        Save search
+                    </Button>
+                </Stack>
+                {panelError && (
+                    <Alert severity="error" variant="outlined">
+                        {panelError}
+                    </Alert>
+                )}
+                {filter && (
+                    <Stack direction="row" spacing={1} alignItems="center">
+                        <Typography variant="body2" color="text.secondary">
+                            Showing {filter.label} ({filter.conversations.length})
+                        </Typography>
+                        <Button size="small" onClick={() => onFilterChange(null)}>
+                            Clear filter
+                        </Button>
+                    </Stack>
+                )}
+                {collections.length > 0 && (
+                    <Stack direction="row" spacing={1} useFlexGap flexWrap="wrap" role="list" aria-label="Saved searches">
+                        {collections.map((collection) => (
+                            <Stack role="listitem" key={collection.id} direction="row" alignItems="center" title={collection.error || collection.query}>
+                                <Button
+                                    size="small"
+                                    variant={filter?.collectionId === collection.id ? 'contained' : 'outlined'}
+                                    aria-pressed={filter?.collectionId === collection.id}
+                                    disabled={Boolean(collection.error)}
+                                    onClick={() => onFilterChange(collectionFilter(collection))}
+                                >
+                                    {collection.name} ({collection.count})
+                                </Button>
+                                <Button size="small" aria-label={`Delete ${collection.name}`} onClick={() => void deleteCollection(collection)}>
+                                    ×
+                                </Button>
+                            </Stack>
+                        ))}
+                    </Stack>
+                )}
+            </Stack>
+        </Paper>
+    );
+}
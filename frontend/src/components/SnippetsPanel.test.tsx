@@ -0,0 +1,97 @@
+import React from 'react';
+import {cleanup, fireEvent, render, screen, waitFor, within} from '@testing-library/react';
+import {afterEach, beforeEach, describe, expect, it, vi} from 'vitest';
+
+import {SnippetsPanel} from './SnippetsPanel';
+import {ExportSnippets, GetSnippets} from '../../wailsjs/go/main/App';
+
+vi.mock('../../wailsjs/go/main/App', () => ({
+    ExportSnippets: vi.fn(),
+    GetSnippets: vi.fn()
+}));
+
+const mockedExportSnippets = vi.mocked(ExportSnippets);
+const mockedGetSnippets = vi.mocked(GetSnippets);
+
+const snippets = [
+    {
+        conversationId: 'conv-1',
+        conversationName: 'Plotting',
+        messageIndex: 1,
+        speaker: 'assistant',
+        messageTimestamp: '',
+        language: 'python',
+        code: 'plt.plot(x, y)'
+    },
+    {
+        conversationId: 'conv-2',
+        conversationName: '',
+        messageIndex: 0,
+        speaker: 'assistant',
+        messageTimestamp: '',
+        language: 'shell',
+        code: 'ls -la'
+    }
+];
+
+describe('SnippetsPanel', () => {
+    beforeEach(() => {
+        mockedExportSnippets.mockReset();
+        mockedGetSnippets.mockReset();
+    });
+
+    afterEach(() => {
+        cleanup();
+    });
+
+    it('renders nothing when the export has no snippets', async () => {
+        mockedGetSnippets.mockResolvedValue([]);
+
+        const {container} = render(<SnippetsPanel conversationSetVersion={0} />);
+
+        await waitFor(() => {
+            expect(mockedGetSnippets).toHaveBeenCalledWith('', '');
+        });
+        expect(container.firstChild).toBeNull();
+    });
+
+    it('lists snippets and searches through the backend', async () => {
+        mockedGetSnippets.mockImplementation(async (query: string) => (
+            query === '' ? snippets : snippets.filter((snippet) => snippet.code.includes(query))
+        ));
+
+        render(<SnippetsPanel conversationSetVersion={0} />);
+
+        await waitFor(() => {
+            expect(screen.getByText('2 code snippets found.')).toBeTruthy();
+        });
+        const items = within(screen.getByRole('list', {name: 'Snippets'})).getAllByRole('listitem');
+        expect(within(items[0]).getByText('python')).toBeTruthy();
+        expect(within(items[0]).getByText('Plotting · message 2')).toBeTruthy();
+        expect(within(items[1]).getByText('conv-2 · message 1')).toBeTruthy();
+
+        fireEvent.change(screen.getByLabelText('Search snippets'), {target: {value: 'ls'}});
+
+        await waitFor(() => {
+            expect(screen.getByText('1 code snippet match "ls".')).toBeTruthy();
+        });
+        expect(screen.getByText('ls -la')).toBeTruthy();
+        expect(screen.queryByText('plt.plot(x, y)')).toBeNull();
+    });
+
+    it('reports how many snippets were exported', async () => {
+        mockedGetSnippets.mockResolvedValue(snippets);
+        mockedExportSnippets.mockResolvedValue(2);
+
+        render(<SnippetsPanel conversationSetVersion={0} />);
+
+        await waitFor(() => {
+            expect(screen.getByRole('button', {name: 'Export snippets'})).toBeTruthy();
+        });
+        fireEvent.click(screen.getByRole('button', {name: 'Export snippets'}));
+
+        await waitFor(() => {
+            expect(screen.getByText('Exported 2 snippets.')).toBeTruthy();
+        });
+    });
+});
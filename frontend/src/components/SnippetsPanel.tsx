@@ -0,0 +1,124 @@
+import React, {useEffect, useState} from 'react';
+import {Alert, Box, Button, Chip, Paper, Stack, TextField, Typography} from '@mui/material';
+
+import {ExportSnippets, GetSnippets} from '../../wailsjs/go/main/App';
+import type {models} from '../../wailsjs/go/models';
+
+type Snippet = models.Snippet;
+
+type SnippetsPanelProps = {
+    conversationSetVersion: number;
+};
+
+// Rendering thousands of code blocks at once stalls the list; the search narrows it down.
+const visibleSnippetLimit = 50;
+
+function formatSnippetSummary(snippetCount: number, query: string): string {
+    const noun = snippetCount === 1 ? 'snippet' : 'snippets';
+    if (query.trim() === '') {
+        return `${snippetCount} code ${noun} found.`;
+    }
+    return `${snippetCount} code ${noun} match "${query.trim()}".`;
+}
+
+export function SnippetsPanel({conversationSetVersion}: SnippetsPanelProps) {
+    const [query, setQuery] = useState('');
+    const [snippets, setSnippets] = useState<Snippet[]>([]);
+    const [exportStatus, setExportStatus] = useState('');
+    const [exportError, setExportError] = useState('');
+
+    useEffect(() => {
+        let isCurrent = true;
+        GetSnippets(query, '')
+            .then((matchingSnippets) => {
+                if (isCurrent) {
+                    setSnippets(matchingSnippets ?? []);
+                }
+            })
+            .catch(() => {
+                if (isCurrent) {
+                    setSnippets([]);
+                }
+            });
+
+        return () => {
+            isCurrent = false;
+        };
+    }, [query, conversationSetVersion]);
+
+    useEffect(() => {
+        setExportStatus('');
+        setExportError('');
+    }, [conversationSetVersion]);
+
+    const exportSnippets = async () => {
+        setExportStatus('');
+        setExportError('');
+        try {
+            const writtenCount = await ExportSnippets();
+            if (writtenCount > 0) {
+                setExportStatus(`Exported ${writtenCount} ${writtenCount === 1 ? 'snippet' : 'snippets'}.`);
+            }
+        } catch (exportFailure: unknown) {
+            setExportError(exportFailure instanceof Error ? exportFailure.message : 'Failed to export snippets.');
+        }
+    };
+
+    if (snippets.length === 0 && query.trim() === '') {
+        return null;
+    }
+
+    return (
+        <Paper variant="outlined" role="region" aria-label="Code snippets" sx={{p: 2}}>
+            <Stack spacing={1.5}>
+                <Stack direction={{xs: 'column', sm: 'row'}} spacing={1} useFlexGap alignItems={{sm: 'center'}}>
+                    <TextField
+                        size="small"
+                        label="Search snippets"
+                        value={query}
+                        onChange={(event) => setQuery(event.target.value)}
+                        sx={{flex: 1}}
+                    />
+                    <Button size="small" variant="outlined" onClick={exportSnippets}>
+                        Export snippets
+                    </Button>
+                </Stack>
+                <Typography variant="body2" color="text.secondary">
+                    {formatSnippetSummary(snippets.length, query)}
+                </Typography>
+                {exportStatus && (
+                    <Alert severity="success" variant="outlined">
+                        {exportStatus}
+                    </Alert>
+                )}
+                {exportError && (
+                    <Alert severity="error" variant="outlined">
+                        {exportError}
+                    </Alert>
+                )}
+                <Stack spacing={1} role="list" aria-label="Snippets" sx={{maxHeight: 360, overflowY: 'auto'}}>
+                    {snippets.slice(0, visibleSnippetLimit).map((snippet, index) => (
+                        <Stack
+                            role="listitem"
+                            key={`${snippet.conversationId}-${snippet.messageIndex}-${index}`}
+                            spacing={0.5}
+                        >
+                            <Stack direction="row" spacing={1} alignItems="center">
+                                <Chip label={snippet.language} size="small" variant="outlined" />
+                                <Typography variant="caption" color="text.secondary">
+                                    {snippet.conversationName.trim() || snippet.conversationId} · message {snippet.messageIndex + 1}
+                                </Typography>
+                            </Stack>
+                            <Box
+                                component="pre"
+                                sx={{m: 0, p: 1, fontSize: 12, overflowX: 'auto', backgroundColor: '#f7f7f4', borderRadius: 1}}
+                            >
+                                {snippet.code}
+                            </Box>
+                        </Stack>
+                    ))}
+                </Stack>
+            </Stack>
+        </Paper>
+    );
+}
@@ -1,10 +1,16 @@
 import {describe, expect, it} from 'vitest';
 
 import {
+    countTokens,
     defaultConversationSort,
+    filterThreadsToConversations,
+    formatTokenCount,
     getConversationSortLabel,
     groupConversationEntries,
+    groupThreadsByTopic,
+    hideToolChatter,
     nextConversationSort,
+    parseConversationSort,
     sortConversations,
     type ConversationEntry,
     type ConversationSort
@@ -24,6 +30,7 @@ function entry(overrides: Partial<ConversationEntry>): ConversationEntry {
         conversationId: '',
         conversationName: '',
         conversationCreatedAt: '',
+        messageIndex: 0,
         speaker: 'human',
         message: '',
         messageTimestamp: '',
@@ -298,3 +305,123 @@ describe('conversation sorting', () => {
         expect(durationMilliseconds).toBeLessThan(100);
     });
 });
+
+describe('hideToolChatter', () => {
+    it('drops tool calls, tool results and reasoning but keeps everything else', () => {
+        const entries = [
+            entry({message: 'prompt', kind: 'prompt'}),
+            entry({message: 'thinking', kind: 'reasoning'}),
+            entry({message: 'search', kind: 'tool_call', recipient: 'web.run'}),
+            entry({message: 'results', kind: 'tool_result', toolName: 'web.run'}),
+            entry({message: 'answer', kind: 'answer'}),
+            entry({message: 'unclassified'})
+        ];
+
+        expect(hideToolChatter(entries).map((visibleEntry) => visibleEntry.message)).toEqual([
+            'prompt',
+            'answer',
+            'unclassified'
+        ]);
+    });
+});
+
+describe('token counts', () => {
+    it('sums the estimated tokens and ignores entries without a count', () => {
+        const entries = [
+            entry({message: 'prompt', tokenCount: 12}),
+            entry({message: 'answer', tokenCount: 30}),
+            entry({message: 'uncounted'})
+        ];
+
+        expect(countTokens(entries)).toBe(42);
+        expect(countTokens([])).toBe(0);
+    });
+
+    it('formats counts as an estimate', () => {
+        expect(formatTokenCount(1)).toBe('~1 token');
+        expect(formatTokenCount(42)).toBe(`~${(42).toLocaleString()} tokens`);
+    });
+});
+
+describe('generated titles', () => {
+    it('shows and sorts untitled conversations by their generated title', () => {
+        const threads = groupConversationEntries([
+            entry({conversationId: 'conv-b', conversationName: 'New chat', conversationTitle: 'Budget spreadsheet', message: 'a'}),
+            entry({conversationId: 'conv-a', conversationName: 'Weekly review', message: 'b'}),
+            entry({conversationId: 'conv-c', conversationName: '', conversationTitle: 'Apartment search', message: 'c'}),
+            entry({conversationId: 'conv-d', conversationName: '', message: 'd'})
+        ]);
+
+        expect(threads.map((thread) => thread.conversationName)).toEqual([
+            'Budget spreadsheet',
+            'Weekly review',
+            'Apartment search',
+            'Untitled conversation'
+        ]);
+        expect(threads[0].conversationRawName).toBe('New chat');
+        expect(sortConversations(threads, 'name-asc').map((thread) => thread.conversationId)).toEqual([
+            'conv-d',
+            'conv-c',
+            'conv-b',
+            'conv-a'
+        ]);
+    });
+});
+
+describe('groupThreadsByTopic', () => {
+    it('keeps the sort order within topics and collects unclaimed threads under Other', () => {
+        const threads = sortConversations(
+            groupConversationEntries([
+                entry({conversationId: 'k1', conversationName: 'Pods', message: 'a'}),
+                entry({conversationId: 't1', conversationName: 'Japan', message: 'b'}),
+                entry({conversationId: 'k2', conversationName: 'Helm', message: 'c'}),
+                entry({conversationId: 'x1', conversationName: 'Unknown', message: 'd'})
+            ]),
+            'name-asc'
+        );
+        const topics = [
+            {
+                label: 'kubernetes, pods',
+                keywords: ['kubernetes', 'pods'],
+                conversations: [
+                    {conversationId: 'k1', conversationName: 'Pods'},
+                    {conversationId: 'k2', conversationName: 'Helm'}
+                ]
+            },
+            {label: 'travel', keywords: ['travel'], conversations: [{conversationId: 't1', conversationName: 'Japan'}]},
+            {label: 'empty', keywords: ['gone'], conversations: [{conversationId: 'missing', conversationName: ''}]}
+        ];
+
+        const groups = groupThreadsByTopic(threads, topics);
+
+        expect(groups.map((group) => group.label)).toEqual(['kubernetes, pods', 'travel', 'Other']);
+        expect(groups[0].threads.map((thread) => thread.conversationName)).toEqual(['Helm', 'Pods']);
+        expect(groups[2].threads.map((thread) => thread.conversationId)).toEqual(['x1']);
+    });
+});
+
+describe('filterThreadsToConversations', () => {
+    it('keeps matching threads in order, matching on provider and id', () => {
+        const threads = groupConversationEntries([
+            entry({conversationId: 'c1', conversationName: 'First', provider: 'claude', message: 'a'}),
+            entry({conversationId: 'c2', conversationName: 'Second', provider: 'chatgpt', message: 'b'}),
+            entry({conversationId: 'c3', conversationName: 'Third', message: 'c'})
+        ]);
+
+        const filtered = filterThreadsToConversations(threads, [
+            {conversationId: 'c3'},
+            {provider: 'claude', conversationId: 'c1'},
+            {provider: 'claude', conversationId: 'c2'}
+        ]);
+
+        expect(filtered.map((thread) => thread.conversationId)).toEqual(['c1', 'c3']);
+    });
+});
+
+describe('parseConversationSort', () => {
+    it('reads saved sort modes and falls back to the default', () => {
+        expect(parseConversationSort('name-desc')).toBe('name-desc');
+        expect(parseConversationSort('random')).toBe(defaultConversationSort);
+        expect(parseConversationSort(undefined)).toBe(defaultConversationSort);
+    });
+});
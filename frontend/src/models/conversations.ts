@@ -1,6 +1,18 @@
 import type {models} from '../../wailsjs/go/models';
 
-export type ConversationEntry = models.ConversationEntry;
+// Generated classes gain a convertValues helper once they hold nested structs. Entries
+// are plain JSON objects at runtime, so only the data fields are part of the type.
+export type ConversationEntry = Omit<models.ConversationEntry, 'convertValues'>;
+
+// Topics are plain JSON objects at runtime too.
+export type Topic = Omit<models.Topic, 'convertValues'>;
+
+// TopicGroup is a topic with the conversation threads assigned to it, in list order.
+export type TopicGroup = {
+    label: string;
+    keywords: string[];
+    threads: ConversationThread[];
+};
 
 export type ConversationSort = 'name-asc' | 'name-desc' | 'created-asc' | 'created-desc';
 
@@ -8,12 +20,17 @@ export type ConversationThread = {
     conversationId: string;
     conversationName: string;
     conversationRawName: string;
+    // conversationTitle is the title generated for an untitled conversation; when set it
+    // is shown and sorted on instead of the provider's name.
+    conversationTitle?: string;
     conversationCreatedAt: string;
     conversationCreatedAtUnixMilliseconds: number | null;
     messages: ConversationEntry[];
 };
 
 const untitledConversationName = 'Untitled conversation';
+const otherTopicLabel = 'Other';
+const toolChatterKinds = new Set(['tool_call', 'tool_result', 'reasoning']);
 const sortOrder: ConversationSort[] = ['created-asc', 'created-desc', 'name-asc', 'name-desc'];
 const nameCollator = new Intl.Collator(undefined, {
     usage: 'sort',
@@ -92,6 +109,18 @@ function updateThreadCreatedAt(thread: ConversationThread, candidateTimestamp: s
     }
 }
 
+function displayConversationName(entry: ConversationEntry): string {
+    const generatedTitle = (entry.conversationTitle ?? '').trim();
+    if (generatedTitle !== '') {
+        return generatedTitle;
+    }
+    return normalizeConversationName(entry.conversationName);
+}
+
+function threadSortName(thread: ConversationThread): string {
+    return (thread.conversationTitle ?? '').trim() || thread.conversationRawName.trim();
+}
+
 function hasEmptyConversationName(thread: ConversationThread): boolean {
     return thread.conversationRawName.trim() === '';
 }
@@ -103,8 +132,8 @@ function compareTieBreakerIDs(left: ConversationThread, right: ConversationThrea
 }
 
 function compareNamesAscending(left: ConversationThread, right: ConversationThread): number {
-    const leftName = left.conversationRawName.trim();
-    const rightName = right.conversationRawName.trim();
+    const leftName = threadSortName(left);
+    const rightName = threadSortName(right);
     const emptyLeftName = leftName === '';
     const emptyRightName = rightName === '';
 
@@ -124,8 +153,8 @@ function compareNamesAscending(left: ConversationThread, right: ConversationThre
 }
 
 function compareNamesDescending(left: ConversationThread, right: ConversationThread): number {
-    const leftName = left.conversationRawName.trim();
-    const rightName = right.conversationRawName.trim();
+    const leftName = threadSortName(left);
+    const rightName = threadSortName(right);
     const emptyLeftName = leftName === '';
     const emptyRightName = rightName === '';
 
@@ -197,7 +226,8 @@ export function groupConversationEntries(entries: ConversationEntry[]): Conversa
         const key = buildConversationKey(entry);
         const existingThreadIndex = threadIndexByKey.get(key);
         const entryRawConversationName = entry.conversationName.trim();
-        const entryConversationName = normalizeConversationName(entry.conversationName);
+        const entryConversationName = displayConversationName(entry);
+        const entryConversationTitle = (entry.conversationTitle ?? '').trim();
         const entryConversationCreatedAt = extractEntryConversationCreatedAt(entry);
 
         if (existingThreadIndex === undefined) {
@@ -206,6 +236,7 @@ export function groupConversationEntries(entries: ConversationEntry[]): Conversa
                 conversationId: entry.conversationId.trim(),
                 conversationRawName: entryRawConversationName,
                 conversationName: entryConversationName,
+                ...(entryConversationTitle !== '' ? {conversationTitle: entryConversationTitle} : {}),
                 conversationCreatedAt: '',
                 conversationCreatedAtUnixMilliseconds: null,
                 messages: [entry]
@@ -227,6 +258,24 @@ export function groupConversationEntries(entries: ConversationEntry[]): Conversa
     return threads;
 }
 
+export function isToolChatter(entry: ConversationEntry): boolean {
+    return toolChatterKinds.has(entry.kind ?? '');
+}
+
+// Keeps only the human-facing exchange: prompts, answers and unclassified messages.
+export function hideToolChatter(entries: ConversationEntry[]): ConversationEntry[] {
+    return entries.filter((entry) => !isToolChatter(entry));
+}
+
+// Sums the estimated token counts of the entries; entries without a count add nothing.
+export function countTokens(entries: ConversationEntry[]): number {
+    return entries.reduce((total, entry) => total + (entry.tokenCount ?? 0), 0);
+}
+
+export function formatTokenCount(tokenCount: number): string {
+    return `~${tokenCount.toLocaleString()} ${tokenCount === 1 ? 'token' : 'tokens'}`;
+}
+
 export function sortConversations(conversations: ConversationThread[], sortBy: ConversationSort): ConversationThread[] {
     const sortedConversations = [...conversations];
 
@@ -243,6 +292,50 @@ export function sortConversations(conversations: ConversationThread[], sortBy: C
     }
 }
 
+// Splits the sorted threads into their topics, keeping the sort order within each topic.
+// Threads no topic claims, such as ones emptied by hiding tool activity, go to "Other".
+export function groupThreadsByTopic(threads: ConversationThread[], topics: Topic[]): TopicGroup[] {
+    const groups: TopicGroup[] = topics.map((topic) => ({label: topic.label, keywords: topic.keywords ?? [], threads: []}));
+    const groupIndexByConversationID = new Map<string, number>();
+    topics.forEach((topic, topicIndex) => {
+        for (const conversation of topic.conversations ?? []) {
+            groupIndexByConversationID.set(conversation.conversationId.trim(), topicIndex);
+        }
+    });
+
+    let otherGroup = groups.find((group) => group.label === otherTopicLabel && group.keywords.length === 0);
+    for (const thread of threads) {
+        const groupIndex = groupIndexByConversationID.get(thread.conversationId);
+        if (groupIndex !== undefined && thread.conversationId !== '') {
+            groups[groupIndex].threads.push(thread);
+            continue;
+        }
+        if (otherGroup === undefined) {
+            otherGroup = {label: otherTopicLabel, keywords: [], threads: []};
+            groups.push(otherGroup);
+        }
+        otherGroup.threads.push(thread);
+    }
+
+    return groups.filter((group) => group.threads.length > 0);
+}
+
+// Keeps the threads a query or smart collection matched, in their current order.
+// Conversations are matched on provider and ID, since IDs of different providers may clash.
+export function filterThreadsToConversations(
+    threads: ConversationThread[],
+    conversations: {provider?: string; conversationId: string}[]
+): ConversationThread[] {
+    const keys = new Set(conversations.map((conversation) => `${conversation.provider ?? ''}\u0000${conversation.conversationId}`));
+
+    return threads.filter((thread) => keys.has(`${thread.messages[0]?.provider ?? ''}\u0000${thread.conversationId}`));
+}
+
+// Reads a saved sort mode, falling back to the default for values this version does not know.
+export function parseConversationSort(value: string | undefined): ConversationSort {
+    return sortOrder.find((sortBy) => sortBy === value) ?? defaultConversationSort;
+}
+
 export function nextConversationSort(currentSort: ConversationSort): ConversationSort {
     const currentSortIndex = sortOrder.indexOf(currentSort);
     if (currentSortIndex === -1) {
@@ -2,6 +2,76 @@
 // This file is automatically generated. DO NOT EDIT
 import {models} from '../models';
 
+export function AddToDeletionPlan(arg1:models.DeletionCriteria):Promise<models.DeletionPlan>;
+
+export function ClearRecentFiles():Promise<void>;
+
+export function DeleteSavedSearch(arg1:string):Promise<Array<models.SmartCollection>>;
+
+export function EnableEncryption(arg1:string):Promise<void>;
+
+export function ExportDeletionPlan(arg1:string):Promise<string>;
+
+export function ExportDeletionPlanToPath(arg1:string,arg2:string):Promise<void>;
+
+export function ExportRedacted():Promise<number>;
+
+export function ExportRedactedToPath(arg1:string):Promise<number>;
+
+export function ExportSnippets():Promise<number>;
+
+export function ExportSnippetsToDirectory(arg1:string):Promise<number>;
+
+export function FilterConversations(arg1:string):Promise<Array<models.CollectionConversation>>;
+
+export function GetActivityTimeline(arg1:string):Promise<models.ActivityTimeline>;
+
+export function GetAnnotations():Promise<models.Annotations>;
+
+export function GetCustomInstructionsTimeline():Promise<Array<models.CustomInstructionsVersion>>;
+
+export function GetDeletionPlan():Promise<models.DeletionPlan>;
+
+export function GetLinks():Promise<Array<models.Link>>;
+
+export function GetParseWarnings():Promise<Array<models.ParseWarning>>;
+
+export function GetRecentFiles():Promise<Array<models.RecentFile>>;
+
+export function GetSession():Promise<models.Session>;
+
+export function GetSettings():Promise<models.Settings>;
+
+export function GetSmartCollections():Promise<Array<models.SmartCollection>>;
+
+export function GetSnippets(arg1:string,arg2:string):Promise<Array<models.Snippet>>;
+
+export function GetStatistics():Promise<models.Statistics>;
+
+export function GetStoreStatus():Promise<models.StoreStatus>;
+
+export function GetTopics(arg1:number):Promise<Array<models.Topic>>;
+
 export function LoadConversationsFromPath(arg1:string):Promise<Array<models.ConversationEntry>>;
 
 export function OpenConversationsFile():Promise<Array<models.ConversationEntry>>;
+
+export function RemoveFromDeletionPlan(arg1:string,arg2:string):Promise<models.DeletionPlan>;
+
+export function SaveSearch(arg1:string,arg2:string):Promise<Array<models.SmartCollection>>;
+
+export function SaveSettings(arg1:models.Settings):Promise<models.Settings>;
+
+export function ScanSensitiveData():Promise<Array<models.SensitiveFinding>>;
+
+export function SetConversationNote(arg1:string,arg2:string,arg3:string):Promise<models.Annotations>;
+
+export function SetConversationTags(arg1:string,arg2:string,arg3:Array<string>):Promise<models.Annotations>;
+
+export function SetDeletionDone(arg1:string,arg2:string,arg3:boolean):Promise<models.DeletionPlan>;
+
+export function SetMessageNote(arg1:string,arg2:string,arg3:number,arg4:string):Promise<models.Annotations>;
+
+export function StarConversation(arg1:string,arg2:string,arg3:boolean):Promise<models.Annotations>;
+
+export function Unlock(arg1:string):Promise<void>;
@@ -2,6 +2,106 @@
 // Cynhyrchwyd y ffeil hon yn awtomatig. PEIDIWCH Â MODIWL
 // This file is automatically generated. DO NOT EDIT
 
+export function AddToDeletionPlan(arg1) {
+  return window['go']['main']['App']['AddToDeletionPlan'](arg1);
+}
+
+export function ClearRecentFiles() {
+  return window['go']['main']['App']['ClearRecentFiles']();
+}
+
+export function DeleteSavedSearch(arg1) {
+  return window['go']['main']['App']['DeleteSavedSearch'](arg1);
+}
+
+export function EnableEncryption(arg1) {
+  return window['go']['main']['App']['EnableEncryption'](arg1);
+}
+
+export function ExportDeletionPlan(arg1) {
+  return window['go']['main']['App']['ExportDeletionPlan'](arg1);
+}
+
+export function ExportDeletionPlanToPath(arg1, arg2) {
+  return window['go']['main']['App']['ExportDeletionPlanToPath'](arg1, arg2);
+}
+
+export function ExportRedacted() {
+  return window['go']['main']['App']['ExportRedacted']();
+}
+
+export function ExportRedactedToPath(arg1) {
+  return window['go']['main']['App']['ExportRedactedToPath'](arg1);
+}
+
+export function ExportSnippets() {
+  return window['go']['main']['App']['ExportSnippets']();
+}
+
+export function ExportSnippetsToDirectory(arg1) {
+  return window['go']['main']['App']['ExportSnippetsToDirectory'](arg1);
+}
+
+export function FilterConversations(arg1) {
+  return window['go']['main']['App']['FilterConversations'](arg1);
+}
+
+export function GetActivityTimeline(arg1) {
+  return window['go']['main']['App']['GetActivityTimeline'](arg1);
+}
+
+export function GetAnnotations() {
+  return window['go']['main']['App']['GetAnnotations']();
+}
+
+export function GetCustomInstructionsTimeline() {
+  return window['go']['main']['App']['GetCustomInstructionsTimeline']();
+}
+
+export function GetDeletionPlan() {
+  return window['go']['main']['App']['GetDeletionPlan']();
+}
+
+export function GetLinks() {
+  return window['go']['main']['App']['GetLinks']();
+}
+
+export function GetParseWarnings() {
+  return window['go']['main']['App']['GetParseWarnings']();
+}
+
+export function GetRecentFiles() {
+  return window['go']['main']['App']['GetRecentFiles']();
+}
+
+export function GetSession() {
+  return window['go']['main']['App']['GetSession']();
+}
+
+export function GetSettings() {
+  return window['go']['main']['App']['GetSettings']();
+}
+
+export function GetSmartCollections() {
+  return window['go']['main']['App']['GetSmartCollections']();
+}
+
+export function GetSnippets(arg1, arg2) {
+  return window['go']['main']['App']['GetSnippets'](arg1, arg2);
+}
+
+export function GetStatistics() {
+  return window['go']['main']['App']['GetStatistics']();
+}
+
+export function GetStoreStatus() {
+  return window['go']['main']['App']['GetStoreStatus']();
+}
+
+export function GetTopics(arg1) {
+  return window['go']['main']['App']['GetTopics'](arg1);
+}
+
 export function LoadConversationsFromPath(arg1) {
   return window['go']['main']['App']['LoadConversationsFromPath'](arg1);
 }
@@ -9,3 +109,43 @@ export function LoadConversationsFromPath(arg1) {
 export function OpenConversationsFile() {
   return window['go']['main']['App']['OpenConversationsFile']();
 }
+
+export function RemoveFromDeletionPlan(arg1, arg2) {
+  return window['go']['main']['App']['RemoveFromDeletionPlan'](arg1, arg2);
+}
+
+export function SaveSearch(arg1, arg2) {
+  return window['go']['main']['App']['SaveSearch'](arg1, arg2);
+}
+
+export function SaveSettings(arg1) {
+  return window['go']['main']['App']['SaveSettings'](arg1);
+}
+
+export function ScanSensitiveData() {
+  return window['go']['main']['App']['ScanSensitiveData']();
+}
+
+export function SetConversationNote(arg1, arg2, arg3) {
+  return window['go']['main']['App']['SetConversationNote'](arg1, arg2, arg3);
+}
+
+export function SetConversationTags(arg1, arg2, arg3) {
+  return window['go']['main']['App']['SetConversationTags'](arg1, arg2, arg3);
+}
+
+export function SetDeletionDone(arg1, arg2, arg3) {
+  return window['go']['main']['App']['SetDeletionDone'](arg1, arg2, arg3);
+}
+
+export function SetMessageNote(arg1, arg2, arg3, arg4) {
+  return window['go']['main']['App']['SetMessageNote'](arg1, arg2, arg3, arg4);
+}
+
+export function StarConversation(arg1, arg2, arg3) {
+  return window['go']['main']['App']['StarConversation'](arg1, arg2, arg3);
+}
+
+export function Unlock(arg1) {
+  return window['go']['main']['App']['Unlock'](arg1);
+}
@@ -1,12 +1,254 @@
 export namespace models {
 	
+	export class ActivityCell {
+	    period: string;
+	    row: number;
+	    column: number;
+	    messages: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new ActivityCell(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.period = source["period"];
+	        this.row = source["row"];
+	        this.column = source["column"];
+	        this.messages = source["messages"];
+	    }
+	}
+	export class ActivityHeatmap {
+	    rowLabels: string[];
+	    columnLabels: string[];
+	    cells: ActivityCell[];
+	    maxMessages: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new ActivityHeatmap(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.rowLabels = source["rowLabels"];
+	        this.columnLabels = source["columnLabels"];
+	        this.cells = this.convertValues(source["cells"], ActivityCell);
+	        this.maxMessages = source["maxMessages"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class ActivityMonth {
+	    month: string;
+	    conversations: number;
+	    messages: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new ActivityMonth(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.month = source["month"];
+	        this.conversations = source["conversations"];
+	        this.messages = source["messages"];
+	    }
+	}
+	export class ActivityTimeline {
+	    granularity: string;
+	    heatmap: ActivityHeatmap;
+	    monthly: ActivityMonth[];
+	    messageCount: number;
+	    undatedMessages: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new ActivityTimeline(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.granularity = source["granularity"];
+	        this.heatmap = this.convertValues(source["heatmap"], ActivityHeatmap);
+	        this.monthly = this.convertValues(source["monthly"], ActivityMonth);
+	        this.messageCount = source["messageCount"];
+	        this.undatedMessages = source["undatedMessages"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class MessageNote {
+	    messageIndex: number;
+	    messageSpeaker?: string;
+	    messageHash?: string;
+	    messageTimestamp?: string;
+	    note: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new MessageNote(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.messageIndex = source["messageIndex"];
+	        this.messageSpeaker = source["messageSpeaker"];
+	        this.messageHash = source["messageHash"];
+	        this.messageTimestamp = source["messageTimestamp"];
+	        this.note = source["note"];
+	    }
+	}
+	export class ConversationAnnotation {
+	    provider?: string;
+	    conversationId: string;
+	    starred?: boolean;
+	    tags?: string[];
+	    note?: string;
+	    messageNotes?: MessageNote[];
+	    updatedAt?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new ConversationAnnotation(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.provider = source["provider"];
+	        this.conversationId = source["conversationId"];
+	        this.starred = source["starred"];
+	        this.tags = source["tags"];
+	        this.note = source["note"];
+	        this.messageNotes = this.convertValues(source["messageNotes"], MessageNote);
+	        this.updatedAt = source["updatedAt"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class Annotations {
+	    updatedAt?: string;
+	    conversations: ConversationAnnotation[];
+	
+	    static createFrom(source: any = {}) {
+	        return new Annotations(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.updatedAt = source["updatedAt"];
+	        this.conversations = this.convertValues(source["conversations"], ConversationAnnotation);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class Citation {
+	    title?: string;
+	    url: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new Citation(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.title = source["title"];
+	        this.url = source["url"];
+	    }
+	}
+	export class CollectionConversation {
+	    provider?: string;
+	    conversationId: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new CollectionConversation(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.provider = source["provider"];
+	        this.conversationId = source["conversationId"];
+	    }
+	}
+	
 	export class ConversationEntry {
+	    provider?: string;
 	    conversationId: string;
 	    conversationName: string;
 	    conversationCreatedAt: string;
+	    conversationTitle?: string;
+	    messageIndex: number;
 	    speaker: string;
 	    message: string;
 	    messageTimestamp: string;
+	    kind?: string;
+	    recipient?: string;
+	    toolName?: string;
+	    model?: string;
+	    finishReason?: string;
+	    citations?: Citation[];
+	    searchQueries?: string[];
+	    tokenCount?: number;
 	
 	    static createFrom(source: any = {}) {
 	        return new ConversationEntry(source);
@@ -14,14 +256,625 @@ export namespace models {
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.provider = source["provider"];
 	        this.conversationId = source["conversationId"];
 	        this.conversationName = source["conversationName"];
 	        this.conversationCreatedAt = source["conversationCreatedAt"];
+	        this.conversationTitle = source["conversationTitle"];
+	        this.messageIndex = source["messageIndex"];
 	        this.speaker = source["speaker"];
 	        this.message = source["message"];
 	        this.messageTimestamp = source["messageTimestamp"];
+	        this.kind = source["kind"];
+	        this.recipient = source["recipient"];
+	        this.toolName = source["toolName"];
+	        this.model = source["model"];
+	        this.finishReason = source["finishReason"];
+	        this.citations = this.convertValues(source["citations"], Citation);
+	        this.searchQueries = source["searchQueries"];
+	        this.tokenCount = source["tokenCount"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class ConversationLength {
+	    provider?: string;
+	    conversationId: string;
+	    name: string;
+	    messages: number;
+	    tokens: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new ConversationLength(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.provider = source["provider"];
+	        this.conversationId = source["conversationId"];
+	        this.name = source["name"];
+	        this.messages = source["messages"];
+	        this.tokens = source["tokens"];
+	    }
+	}
+	export class CustomInstructionsVersion {
+	    userProfile?: string;
+	    userInstructions?: string;
+	    firstSeen: string;
+	    lastSeen: string;
+	    conversationIds: string[];
+	
+	    static createFrom(source: any = {}) {
+	        return new CustomInstructionsVersion(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.userProfile = source["userProfile"];
+	        this.userInstructions = source["userInstructions"];
+	        this.firstSeen = source["firstSeen"];
+	        this.lastSeen = source["lastSeen"];
+	        this.conversationIds = source["conversationIds"];
+	    }
+	}
+	export class DeletionCriteria {
+	    conversations?: CollectionConversation[];
+	    query?: string;
+	    provider?: string;
+	    olderThanDays?: number;
+	    minSeverity?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new DeletionCriteria(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.conversations = this.convertValues(source["conversations"], CollectionConversation);
+	        this.query = source["query"];
+	        this.provider = source["provider"];
+	        this.olderThanDays = source["olderThanDays"];
+	        this.minSeverity = source["minSeverity"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class DeletionItem {
+	    provider?: string;
+	    conversationId: string;
+	    conversationName: string;
+	    createdAt: string;
+	    url?: string;
+	    reasons: string[];
+	    done: boolean;
+	    doneAt?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new DeletionItem(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.provider = source["provider"];
+	        this.conversationId = source["conversationId"];
+	        this.conversationName = source["conversationName"];
+	        this.createdAt = source["createdAt"];
+	        this.url = source["url"];
+	        this.reasons = source["reasons"];
+	        this.done = source["done"];
+	        this.doneAt = source["doneAt"];
+	    }
+	}
+	export class DeletionPlan {
+	    updatedAt?: string;
+	    items: DeletionItem[];
+	
+	    static createFrom(source: any = {}) {
+	        return new DeletionPlan(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.updatedAt = source["updatedAt"];
+	        this.items = this.convertValues(source["items"], DeletionItem);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class HourCount {
+	    hour: number;
+	    messages: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new HourCount(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.hour = source["hour"];
+	        this.messages = source["messages"];
 	    }
 	}
+	export class LabelCount {
+	    label: string;
+	    messages: number;
+	    tokens: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new LabelCount(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.label = source["label"];
+	        this.messages = source["messages"];
+	        this.tokens = source["tokens"];
+	    }
+	}
+	export class LinkReference {
+	    provider?: string;
+	    conversationId: string;
+	    conversationName: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new LinkReference(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.provider = source["provider"];
+	        this.conversationId = source["conversationId"];
+	        this.conversationName = source["conversationName"];
+	    }
+	}
+	export class Link {
+	    url: string;
+	    title?: string;
+	    domain: string;
+	    mentions: number;
+	    citations: number;
+	    conversations: LinkReference[];
+	
+	    static createFrom(source: any = {}) {
+	        return new Link(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.url = source["url"];
+	        this.title = source["title"];
+	        this.domain = source["domain"];
+	        this.mentions = source["mentions"];
+	        this.citations = source["citations"];
+	        this.conversations = this.convertValues(source["conversations"], LinkReference);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	
+	
+	export class ParseWarning {
+	    index: number;
+	    conversationId: string;
+	    message: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new ParseWarning(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.index = source["index"];
+	        this.conversationId = source["conversationId"];
+	        this.message = source["message"];
+	    }
+	}
+	export class PeriodCount {
+	    period: string;
+	    conversations: number;
+	    messages: number;
+	    tokens: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new PeriodCount(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.period = source["period"];
+	        this.conversations = source["conversations"];
+	        this.messages = source["messages"];
+	        this.tokens = source["tokens"];
+	    }
+	}
+	export class RecentFile {
+	    path: string;
+	    name: string;
+	    exists: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new RecentFile(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.path = source["path"];
+	        this.name = source["name"];
+	        this.exists = source["exists"];
+	    }
+	}
+	export class SensitiveFinding {
+	    type: string;
+	    severity: string;
+	    provider?: string;
+	    conversationId: string;
+	    conversationName: string;
+	    messageIndex: number;
+	    speaker: string;
+	    messageTimestamp: string;
+	    attachment?: string;
+	    start: number;
+	    end: number;
+	    line: number;
+	    column: number;
+	    preview: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new SensitiveFinding(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.type = source["type"];
+	        this.severity = source["severity"];
+	        this.provider = source["provider"];
+	        this.conversationId = source["conversationId"];
+	        this.conversationName = source["conversationName"];
+	        this.messageIndex = source["messageIndex"];
+	        this.speaker = source["speaker"];
+	        this.messageTimestamp = source["messageTimestamp"];
+	        this.attachment = source["attachment"];
+	        this.start = source["start"];
+	        this.end = source["end"];
+	        this.line = source["line"];
+	        this.column = source["column"];
+	        this.preview = source["preview"];
+	    }
+	}
+	export class WindowSettings {
+	    width?: number;
+	    height?: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new WindowSettings(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.width = source["width"];
+	        this.height = source["height"];
+	    }
+	}
+	export class Settings {
+	    lastOpenedPaths: string[];
+	    sortMode: string;
+	    expandedConversations: string[];
+	    window: WindowSettings;
+	    theme: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new Settings(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.lastOpenedPaths = source["lastOpenedPaths"];
+	        this.sortMode = source["sortMode"];
+	        this.expandedConversations = source["expandedConversations"];
+	        this.window = this.convertValues(source["window"], WindowSettings);
+	        this.theme = source["theme"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class Session {
+	    settings: Settings;
+	    sourcePath?: string;
+	    entries: ConversationEntry[];
+	    restoreError?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new Session(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.settings = this.convertValues(source["settings"], Settings);
+	        this.sourcePath = source["sourcePath"];
+	        this.entries = this.convertValues(source["entries"], ConversationEntry);
+	        this.restoreError = source["restoreError"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	
+	export class SmartCollection {
+	    id: string;
+	    name: string;
+	    query: string;
+	    count: number;
+	    conversations: CollectionConversation[];
+	    error?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new SmartCollection(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.name = source["name"];
+	        this.query = source["query"];
+	        this.count = source["count"];
+	        this.conversations = this.convertValues(source["conversations"], CollectionConversation);
+	        this.error = source["error"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class Snippet {
+	    provider?: string;
+	    conversationId: string;
+	    conversationName: string;
+	    messageIndex: number;
+	    speaker: string;
+	    messageTimestamp: string;
+	    language: string;
+	    code: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new Snippet(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.provider = source["provider"];
+	        this.conversationId = source["conversationId"];
+	        this.conversationName = source["conversationName"];
+	        this.messageIndex = source["messageIndex"];
+	        this.speaker = source["speaker"];
+	        this.messageTimestamp = source["messageTimestamp"];
+	        this.language = source["language"];
+	        this.code = source["code"];
+	    }
+	}
+	export class Statistics {
+	    conversationCount: number;
+	    messageCount: number;
+	    tokenCount: number;
+	    averageConversationLength: number;
+	    daily: PeriodCount[];
+	    weekly: PeriodCount[];
+	    monthly: PeriodCount[];
+	    messagesPerSpeaker: LabelCount[];
+	    messagesPerModel: LabelCount[];
+	    busiestHours: HourCount[];
+	    longestConversations: ConversationLength[];
+	
+	    static createFrom(source: any = {}) {
+	        return new Statistics(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.conversationCount = source["conversationCount"];
+	        this.messageCount = source["messageCount"];
+	        this.tokenCount = source["tokenCount"];
+	        this.averageConversationLength = source["averageConversationLength"];
+	        this.daily = this.convertValues(source["daily"], PeriodCount);
+	        this.weekly = this.convertValues(source["weekly"], PeriodCount);
+	        this.monthly = this.convertValues(source["monthly"], PeriodCount);
+	        this.messagesPerSpeaker = this.convertValues(source["messagesPerSpeaker"], LabelCount);
+	        this.messagesPerModel = this.convertValues(source["messagesPerModel"], LabelCount);
+	        this.busiestHours = this.convertValues(source["busiestHours"], HourCount);
+	        this.longestConversations = this.convertValues(source["longestConversations"], ConversationLength);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class StoreStatus {
+	    encrypted: boolean;
+	    locked: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new StoreStatus(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.encrypted = source["encrypted"];
+	        this.locked = source["locked"];
+	    }
+	}
+	export class TopicConversation {
+	    provider?: string;
+	    conversationId: string;
+	    conversationName: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new TopicConversation(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.provider = source["provider"];
+	        this.conversationId = source["conversationId"];
+	        this.conversationName = source["conversationName"];
+	    }
+	}
+	export class Topic {
+	    label: string;
+	    keywords: string[];
+	    conversations: TopicConversation[];
+	
+	    static createFrom(source: any = {}) {
+	        return new Topic(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.label = source["label"];
+	        this.keywords = source["keywords"];
+	        this.conversations = this.convertValues(source["conversations"], TopicConversation);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	
 
 }
 
@@ -2,10 +2,12 @@ package main
 
 import (
 	"embed"
+	"os"
 
 	"github.com/wailsapp/wails/v2"
 	"github.com/wailsapp/wails/v2/pkg/options"
 	"github.com/wailsapp/wails/v2/pkg/options/assetserver"
+	"github.com/wailsapp/wails/v2/pkg/options/mac"
 )
 
 //go:embed all:frontend/dist
@@ -14,17 +16,33 @@ var assets embed.FS
 func main() {
 	// Create an instance of the app structure
 	app := NewApp()
+	// An export passed on the command line, or by "open with", is opened on startup.
+	app.pendingPath = exportPathFromArgs(os.Args[1:], "")
+	// The window opens at the size of the last session.
+	width, height := app.initialWindowSize()
 
 	// Create application with options
 	err := wails.Run(&options.App{
 		Title:  "chat-explorer",
-		Width:  1024,
-		Height: 768,
+		Width:  width,
+		Height: height,
 		AssetServer: &assetserver.Options{
 			Assets: assets,
 		},
 		BackgroundColour: &options.RGBA{R: 27, G: 38, B: 54, A: 1},
 		OnStartup:        app.startup,
+		OnBeforeClose:    app.beforeClose,
+		SingleInstanceLock: &options.SingleInstanceLock{
+			UniqueId:               "chat-explorer-single-instance",
+			OnSecondInstanceLaunch: app.onSecondInstanceLaunch,
+		},
+		DragAndDrop: &options.DragAndDrop{
+			EnableFileDrop:     true,
+			DisableWebViewDrop: true,
+		},
+		Mac: &mac.Options{
+			OnFileOpen: app.openFile,
+		},
 		Bind: []interface{}{
 			app,
 		},
@@ -0,0 +1,231 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Activity timeline granularities.
+const (
+	// ActivityByDay lays the heatmap out like a contribution calendar: one cell per
+	// day, a row per weekday and a column per week.
+	ActivityByDay = "day"
+	// ActivityByHour lays the heatmap out as a punch card: a row per weekday and a
+	// column per hour of the day.
+	ActivityByHour = "hour"
+)
+
+var weekdayLabels = []string{"Sun", "Mon", "Tue", "Wed", "Thu", "Fri", "Sat"}
+
+// ActivityTimeline is the message activity of a set of conversations, bucketed in the
+// location passed to ComputeActivityTimeline. Messages without a usable timestamp fall
+// back to their conversation's CreatedAt and are counted as undated otherwise.
+type ActivityTimeline struct {
+	Granularity string          `json:"granularity"`
+	Heatmap     ActivityHeatmap `json:"heatmap"`
+	// Monthly has one entry per month from the first active month to the last, including
+	// the months without activity, for a bar chart.
+	Monthly         []ActivityMonth `json:"monthly"`
+	MessageCount    int             `json:"messageCount"`
+	UndatedMessages int             `json:"undatedMessages"`
+}
+
+// ActivityHeatmap is a grid of RowLabels by ColumnLabels. Cells lists every cell in the
+// grid, row-major, so the frontend can draw it without filling gaps; in a day heatmap
+// the days before the first active day of the first week are left out.
+type ActivityHeatmap struct {
+	RowLabels    []string       `json:"rowLabels"`
+	ColumnLabels []string       `json:"columnLabels"`
+	Cells        []ActivityCell `json:"cells"`
+	// MaxMessages is the busiest cell's count, for scaling colours.
+	MaxMessages int `json:"maxMessages"`
+}
+
+// ActivityCell counts the messages of one heatmap cell. Period is the day
+// ("2006-01-02") in a day heatmap, and the weekday and hour ("Mon 14:00") in an hour
+// heatmap.
+type ActivityCell struct {
+	Period   string `json:"period"`
+	Row      int    `json:"row"`
+	Column   int    `json:"column"`
+	Messages int    `json:"messages"`
+}
+
+// ActivityMonth counts the conversations started and the messages sent in a month
+// ("2006-01").
+type ActivityMonth struct {
+	Month         string `json:"month"`
+	Conversations int    `json:"conversations"`
+	Messages      int    `json:"messages"`
+}
+
+// ComputeActivityTimeline buckets the messages of conversations by granularity, which
+// is ActivityByDay or ActivityByHour, in location.
+func ComputeActivityTimeline(conversations []Conversation, granularity string, location *time.Location) (ActivityTimeline, error) {
+	normalizedGranularity := strings.ToLower(strings.TrimSpace(granularity))
+	if normalizedGranularity != ActivityByDay && normalizedGranularity != ActivityByHour {
+		return ActivityTimeline{}, fmt.Errorf("unknown activity granularity %q: use %q or %q", granularity, ActivityByDay, ActivityByHour)
+	}
+	if location == nil {
+		location = time.UTC
+	}
+
+	timeline := ActivityTimeline{Granularity: normalizedGranularity}
+	messageTimes := make([]time.Time, 0)
+	monthCounts := make(map[string]*ActivityMonth)
+	countMonth := func(moment time.Time, conversations int, messages int) {
+		key := monthPeriod(moment)
+		count, exists := monthCounts[key]
+		if !exists {
+			count = &ActivityMonth{Month: key}
+			monthCounts[key] = count
+		}
+		count.Conversations += conversations
+		count.Messages += messages
+	}
+
+	for _, conversation := range conversations {
+		conversationTime, hasConversationTime := parseTimestamp(conversation.CreatedAt)
+		if hasConversationTime {
+			countMonth(conversationTime.In(location), 1, 0)
+		}
+
+		for _, message := range conversation.Messages {
+			timeline.MessageCount++
+			messageTime, hasMessageTime := parseTimestamp(message.Timestamp)
+			if !hasMessageTime {
+				messageTime, hasMessageTime = conversationTime, hasConversationTime
+			}
+			if !hasMessageTime {
+				timeline.UndatedMessages++
+				continue
+			}
+
+			localTime := messageTime.In(location)
+			messageTimes = append(messageTimes, localTime)
+			countMonth(localTime, 0, 1)
+		}
+	}
+
+	if normalizedGranularity == ActivityByHour {
+		timeline.Heatmap = hourHeatmap(messageTimes)
+	} else {
+		timeline.Heatmap = dayHeatmap(messageTimes, location)
+	}
+	timeline.Monthly = contiguousMonths(monthCounts, location)
+
+	return timeline, nil
+}
+
+// hourHeatmap counts messages per weekday and hour of the day.
+func hourHeatmap(messageTimes []time.Time) ActivityHeatmap {
+	heatmap := ActivityHeatmap{
+		RowLabels:    weekdayLabels,
+		ColumnLabels: make([]string, 24),
+		Cells:        make([]ActivityCell, 0, len(weekdayLabels)*24),
+	}
+	for hour := range heatmap.ColumnLabels {
+		heatmap.ColumnLabels[hour] = fmt.Sprintf("%02d:00", hour)
+	}
+	for row, weekday := range weekdayLabels {
+		for column, hour := range heatmap.ColumnLabels {
+			heatmap.Cells = append(heatmap.Cells, ActivityCell{Period: weekday + " " + hour, Row: row, Column: column})
+		}
+	}
+
+	for _, moment := range messageTimes {
+		cell := &heatmap.Cells[int(moment.Weekday())*24+moment.Hour()]
+		cell.Messages++
+		if cell.Messages > heatmap.MaxMessages {
+			heatmap.MaxMessages = cell.Messages
+		}
+	}
+
+	return heatmap
+}
+
+// dayHeatmap counts messages per day, from the week of the first message to the day of
+// the last. Columns are weeks starting on Sunday, labelled with their first day.
+func dayHeatmap(messageTimes []time.Time, location *time.Location) ActivityHeatmap {
+	heatmap := ActivityHeatmap{RowLabels: weekdayLabels, ColumnLabels: []string{}, Cells: []ActivityCell{}}
+	if len(messageTimes) == 0 {
+		return heatmap
+	}
+
+	dayCounts := make(map[string]int)
+	first, last := messageTimes[0], messageTimes[0]
+	for _, moment := range messageTimes {
+		dayCounts[dayPeriod(moment)]++
+		if moment.Before(first) {
+			first = moment
+		}
+		if moment.After(last) {
+			last = moment
+		}
+	}
+
+	// Days are stepped with time.Date rather than by adding 24 hours, which would drift
+	// across daylight saving changes.
+	firstDay := time.Date(first.Year(), first.Month(), first.Day(), 0, 0, 0, 0, location)
+	weekStart := firstDay.AddDate(0, 0, -int(firstDay.Weekday()))
+	lastPeriod := dayPeriod(last)
+	days := make([]ActivityCell, 0)
+	for offset := 0; ; offset++ {
+		day := time.Date(weekStart.Year(), weekStart.Month(), weekStart.Day()+offset, 0, 0, 0, 0, location)
+		period := dayPeriod(day)
+		if offset%7 == 0 {
+			heatmap.ColumnLabels = append(heatmap.ColumnLabels, period)
+		}
+		if !day.Before(firstDay) {
+			days = append(days, ActivityCell{Period: period, Row: int(day.Weekday()), Column: offset / 7, Messages: dayCounts[period]})
+		}
+		if period == lastPeriod {
+			break
+		}
+	}
+
+	for row := range weekdayLabels {
+		for _, cell := range days {
+			if cell.Row != row {
+				continue
+			}
+			heatmap.Cells = append(heatmap.Cells, cell)
+			if cell.Messages > heatmap.MaxMessages {
+				heatmap.MaxMessages = cell.Messages
+			}
+		}
+	}
+
+	return heatmap
+}
+
+// contiguousMonths orders the month counts and fills the months between them.
+func contiguousMonths(counts map[string]*ActivityMonth, location *time.Location) []ActivityMonth {
+	months := make([]ActivityMonth, 0, len(counts))
+	first, last := "", ""
+	for key := range counts {
+		if first == "" || key < first {
+			first = key
+		}
+		if key > last {
+			last = key
+		}
+	}
+	firstMonth, err := time.ParseInLocation("2006-01", first, location)
+	if err != nil {
+		return months
+	}
+
+	for offset := 0; ; offset++ {
+		key := monthPeriod(time.Date(firstMonth.Year(), firstMonth.Month()+time.Month(offset), 1, 0, 0, 0, 0, location))
+		if count, exists := counts[key]; exists {
+			months = append(months, *count)
+		} else {
+			months = append(months, ActivityMonth{Month: key})
+		}
+		if key == last {
+			return months
+		}
+	}
+}
@@ -0,0 +1,126 @@
+package models
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func activityFixture() []Conversation {
+	// 2024-01-06 23:30:00.25 UTC, as the ChatGPT parser formats a Unix float timestamp.
+	chatGPTCreatedAt := 1704583800.25
+	chatGPTTimestamp := formatUnixTimestamp(&chatGPTCreatedAt)
+
+	return []Conversation{
+		{
+			Provider:  ProviderChatGPT,
+			ID:        "chatgpt",
+			CreatedAt: chatGPTTimestamp,
+			Messages: []Message{
+				{Speaker: "user", Text: "a", Timestamp: chatGPTTimestamp},
+				// No timestamp: counted at the conversation's creation.
+				{Speaker: "assistant", Text: "b"},
+			},
+		},
+		{
+			Provider:  ProviderClaude,
+			ID:        "claude",
+			CreatedAt: "2024-03-04T09:15:00.123456Z",
+			Messages: []Message{
+				{Speaker: "user", Text: "c", Timestamp: "2024-03-04T09:15:00.123456Z"},
+				{Speaker: "assistant", Text: "d", Timestamp: "2024-03-04T10:00:00+01:00"},
+			},
+		},
+		{
+			Provider: ProviderCopilot,
+			ID:       "undated",
+			Messages: []Message{{Speaker: "user", Text: "e"}},
+		},
+	}
+}
+
+func TestComputeActivityTimelineByDay(t *testing.T) {
+	location := time.FixedZone("UTC+1", 60*60)
+	got, err := ComputeActivityTimeline(activityFixture(), " Day ", location)
+	if err != nil {
+		t.Fatalf("ComputeActivityTimeline returned error: %v", err)
+	}
+
+	if got.Granularity != ActivityByDay || got.MessageCount != 5 || got.UndatedMessages != 1 {
+		t.Fatalf("unexpected totals %+v", got)
+	}
+
+	// 23:30 UTC on Saturday Jan 6 is already Sunday Jan 7 in UTC+1, so the first week
+	// starts that day. Mar 4 2024 is a Monday, nine weeks later.
+	heatmap := got.Heatmap
+	if len(heatmap.ColumnLabels) != 9 || heatmap.ColumnLabels[0] != "2024-01-07" || heatmap.ColumnLabels[8] != "2024-03-03" {
+		t.Fatalf("unexpected week columns %v", heatmap.ColumnLabels)
+	}
+	if len(heatmap.Cells) != 58 || heatmap.MaxMessages != 2 {
+		t.Fatalf("expected 58 days peaking at 2 messages, got %d cells peaking at %d", len(heatmap.Cells), heatmap.MaxMessages)
+	}
+	active := map[string]ActivityCell{}
+	for index, cell := range heatmap.Cells {
+		if index > 0 && cell.Row < heatmap.Cells[index-1].Row {
+			t.Fatalf("expected cells in row-major order, got %+v after %+v", cell, heatmap.Cells[index-1])
+		}
+		if cell.Messages > 0 {
+			active[cell.Period] = cell
+		}
+	}
+	want := map[string]ActivityCell{
+		"2024-01-07": {Period: "2024-01-07", Row: 0, Column: 0, Messages: 2},
+		"2024-03-04": {Period: "2024-03-04", Row: 1, Column: 8, Messages: 2},
+	}
+	if !reflect.DeepEqual(active, want) {
+		t.Fatalf("expected active days %+v, got %+v", want, active)
+	}
+
+	wantMonths := []ActivityMonth{
+		{Month: "2024-01", Conversations: 1, Messages: 2},
+		{Month: "2024-02"},
+		{Month: "2024-03", Conversations: 1, Messages: 2},
+	}
+	if !reflect.DeepEqual(got.Monthly, wantMonths) {
+		t.Fatalf("expected months %+v, got %+v", wantMonths, got.Monthly)
+	}
+}
+
+func TestComputeActivityTimelineByHour(t *testing.T) {
+	location := time.FixedZone("UTC+1", 60*60)
+	got, err := ComputeActivityTimeline(activityFixture(), ActivityByHour, location)
+	if err != nil {
+		t.Fatalf("ComputeActivityTimeline returned error: %v", err)
+	}
+
+	heatmap := got.Heatmap
+	if len(heatmap.RowLabels) != 7 || len(heatmap.ColumnLabels) != 24 || len(heatmap.Cells) != 7*24 {
+		t.Fatalf("expected a 7x24 grid, got %d rows, %d columns and %d cells", len(heatmap.RowLabels), len(heatmap.ColumnLabels), len(heatmap.Cells))
+	}
+
+	active := map[string]int{}
+	for _, cell := range heatmap.Cells {
+		if cell.Messages > 0 {
+			active[cell.Period] = cell.Messages
+		}
+	}
+	// 09:15 UTC and 10:00+01:00 both fall in the 10:00 hour of UTC+1.
+	want := map[string]int{"Sun 00:00": 2, "Mon 10:00": 2}
+	if !reflect.DeepEqual(active, want) || heatmap.MaxMessages != 2 {
+		t.Fatalf("expected active hours %v, got %v (max %d)", want, active, heatmap.MaxMessages)
+	}
+}
+
+func TestComputeActivityTimelineEdgeCases(t *testing.T) {
+	if _, err := ComputeActivityTimeline(nil, "week", time.UTC); err == nil {
+		t.Fatal("expected an unknown granularity to be rejected")
+	}
+
+	got, err := ComputeActivityTimeline(nil, ActivityByDay, nil)
+	if err != nil {
+		t.Fatalf("ComputeActivityTimeline returned error: %v", err)
+	}
+	if len(got.Heatmap.Cells) != 0 || len(got.Heatmap.ColumnLabels) != 0 || len(got.Monthly) != 0 {
+		t.Fatalf("expected an empty timeline, got %+v", got)
+	}
+}
@@ -0,0 +1,276 @@
+package models
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"sort"
+	"strings"
+	"time"
+)
+
+// AnnotationsFileName is the local store file holding the user's stars, tags and notes.
+const AnnotationsFileName = "annotations.json"
+
+// Annotations are the user's own additions to conversations. They are keyed by
+// provider and conversation ID, so they reattach when a newer export of the same
+// account is loaded.
+type Annotations struct {
+	UpdatedAt     string                   `json:"updatedAt,omitempty"`
+	Conversations []ConversationAnnotation `json:"conversations"`
+}
+
+// ConversationAnnotation is what the user added to one conversation.
+type ConversationAnnotation struct {
+	Provider       string        `json:"provider,omitempty"`
+	ConversationID string        `json:"conversationId"`
+	Starred        bool          `json:"starred,omitempty"`
+	Tags           []string      `json:"tags,omitempty"`
+	Note           string        `json:"note,omitempty"`
+	MessageNotes   []MessageNote `json:"messageNotes,omitempty"`
+	UpdatedAt      string        `json:"updatedAt,omitempty"`
+}
+
+// MessageNote is a note on one message. The speaker and a hash of the message text
+// are kept so the note can follow its message if a newer export shifts the message
+// indexes; the timestamp only tells apart messages with the same speaker and text.
+type MessageNote struct {
+	MessageIndex     int    `json:"messageIndex"`
+	MessageSpeaker   string `json:"messageSpeaker,omitempty"`
+	MessageHash      string `json:"messageHash,omitempty"`
+	MessageTimestamp string `json:"messageTimestamp,omitempty"`
+	Note             string `json:"note"`
+}
+
+// NewMessageNote returns a note on message, the message at messageIndex. A zero
+// message, for a conversation that is not loaded, records the index alone.
+func NewMessageNote(messageIndex int, message Message, note string) MessageNote {
+	messageNote := MessageNote{MessageIndex: messageIndex, MessageTimestamp: message.Timestamp, Note: note}
+	if message.Speaker != "" || message.Text != "" {
+		messageNote.MessageSpeaker = message.Speaker
+		messageNote.MessageHash = messageTextHash(message.Text)
+	}
+
+	return messageNote
+}
+
+// matches reports whether message is the one the note was written on. Notes saved
+// before speakers and hashes were recorded match on the timestamp alone.
+func (note MessageNote) matches(message Message) bool {
+	if note.MessageHash != "" {
+		return message.Speaker == note.MessageSpeaker && messageTextHash(message.Text) == note.MessageHash
+	}
+
+	return note.MessageTimestamp != "" && message.Timestamp == note.MessageTimestamp
+}
+
+func messageTextHash(text string) string {
+	sum := sha256.Sum256([]byte(text))
+	return hex.EncodeToString(sum[:])
+}
+
+func (annotation ConversationAnnotation) isEmpty() bool {
+	return !annotation.Starred && len(annotation.Tags) == 0 && annotation.Note == "" && len(annotation.MessageNotes) == 0
+}
+
+// UpdateConversationAnnotation applies update to the annotation of a conversation,
+// creating it when missing and dropping it once it holds nothing.
+func UpdateConversationAnnotation(
+	annotations Annotations,
+	provider string,
+	conversationID string,
+	now time.Time,
+	update func(annotation *ConversationAnnotation),
+) (Annotations, error) {
+	if strings.TrimSpace(conversationID) == "" {
+		return annotations, fmt.Errorf("conversation id is required")
+	}
+
+	conversations := append([]ConversationAnnotation(nil), annotations.Conversations...)
+	index := -1
+	for candidateIndex, candidate := range conversations {
+		if candidate.Provider == provider && candidate.ConversationID == conversationID {
+			index = candidateIndex
+			break
+		}
+	}
+	if index == -1 {
+		conversations = append(conversations, ConversationAnnotation{Provider: provider, ConversationID: conversationID})
+		index = len(conversations) - 1
+	}
+
+	annotation := conversations[index]
+	annotation.Tags = append([]string(nil), annotation.Tags...)
+	annotation.MessageNotes = append([]MessageNote(nil), annotation.MessageNotes...)
+	update(&annotation)
+	annotation.UpdatedAt = now.UTC().Format(time.RFC3339)
+	conversations[index] = annotation
+	if annotation.isEmpty() {
+		conversations = append(conversations[:index], conversations[index+1:]...)
+	}
+
+	annotations.Conversations = conversations
+	annotations.UpdatedAt = now.UTC().Format(time.RFC3339)
+	return annotations, nil
+}
+
+// NormalizeTags trims the tags and drops blank and duplicate ones, ignoring case and
+// keeping the first spelling, then sorts them.
+func NormalizeTags(tags []string) []string {
+	normalized := make([]string, 0, len(tags))
+	seen := make(map[string]struct{}, len(tags))
+	for _, tag := range tags {
+		trimmed := strings.Join(strings.Fields(tag), " ")
+		key := strings.ToLower(trimmed)
+		if trimmed == "" {
+			continue
+		}
+		if _, duplicate := seen[key]; duplicate {
+			continue
+		}
+		seen[key] = struct{}{}
+		normalized = append(normalized, trimmed)
+	}
+	sort.SliceStable(normalized, func(i, j int) bool {
+		return strings.ToLower(normalized[i]) < strings.ToLower(normalized[j])
+	})
+
+	return normalized
+}
+
+// SetMessageNote replaces the note on message, the message at messageIndex; a blank
+// note removes it.
+func (annotation *ConversationAnnotation) SetMessageNote(messageIndex int, message Message, note string) {
+	trimmedNote := strings.TrimSpace(note)
+	notes := annotation.MessageNotes[:0]
+	for _, existing := range annotation.MessageNotes {
+		if existing.MessageIndex != messageIndex {
+			notes = append(notes, existing)
+		}
+	}
+	if trimmedNote != "" {
+		notes = append(notes, NewMessageNote(messageIndex, message, trimmedNote))
+	}
+	sort.SliceStable(notes, func(i, j int) bool {
+		return notes[i].MessageIndex < notes[j].MessageIndex
+	})
+	annotation.MessageNotes = notes
+}
+
+// ReattachMessageNotes moves notes whose message no longer sits at the recorded index
+// to the message of the conversation with the recorded speaker and text, as happens
+// when a newer export adds messages a previous one left out. Gemini prompts and
+// answers, and the messages of an API log exchange, share a timestamp, so it only
+// breaks ties between messages with the same speaker and text, followed by the
+// distance from the recorded index. Each message keeps at most one note. Notes
+// without an anchor, or whose message is not found, keep their index.
+func ReattachMessageNotes(annotations Annotations, conversations []Conversation) Annotations {
+	conversationsByKey := make(map[string]Conversation, len(conversations))
+	for _, conversation := range conversations {
+		conversationsByKey[conversation.Provider+"\x00"+conversation.ID] = conversation
+	}
+
+	reattached := make([]ConversationAnnotation, len(annotations.Conversations))
+	for index, annotation := range annotations.Conversations {
+		reattached[index] = annotation
+		conversation, ok := conversationsByKey[annotation.Provider+"\x00"+annotation.ConversationID]
+		if !ok || len(annotation.MessageNotes) == 0 {
+			continue
+		}
+		reattached[index].MessageNotes = reattachConversationNotes(annotation.MessageNotes, conversation.Messages)
+	}
+
+	annotations.Conversations = reattached
+	return annotations
+}
+
+func reattachConversationNotes(notes []MessageNote, messages []Message) []MessageNote {
+	reattached := append([]MessageNote(nil), notes...)
+	claimed := make(map[int]bool, len(notes))
+	anchored := make([]int, 0, len(notes))
+	for noteIndex, note := range notes {
+		if note.MessageHash == "" && note.MessageTimestamp == "" {
+			claimed[note.MessageIndex] = true
+			continue
+		}
+		anchored = append(anchored, noteIndex)
+	}
+	// Earlier notes pick first, so notes on repeated text keep their order.
+	sort.SliceStable(anchored, func(i, j int) bool {
+		return notes[anchored[i]].MessageIndex < notes[anchored[j]].MessageIndex
+	})
+
+	for _, noteIndex := range anchored {
+		note := notes[noteIndex]
+		best := -1
+		for messageIndex, message := range messages {
+			if claimed[messageIndex] || !note.matches(message) {
+				continue
+			}
+			if best == -1 || isCloserNoteAnchor(note, messages, messageIndex, best) {
+				best = messageIndex
+			}
+		}
+		if best != -1 {
+			claimed[best] = true
+			reattached[noteIndex].MessageIndex = best
+		}
+	}
+
+	return reattached
+}
+
+// isCloserNoteAnchor reports whether the message at candidate is a better home for
+// note than the one at current: first by matching timestamp, then by distance from
+// the recorded index.
+func isCloserNoteAnchor(note MessageNote, messages []Message, candidate int, current int) bool {
+	candidateTimestamp := note.MessageTimestamp != "" && messages[candidate].Timestamp == note.MessageTimestamp
+	currentTimestamp := note.MessageTimestamp != "" && messages[current].Timestamp == note.MessageTimestamp
+	if candidateTimestamp != currentTimestamp {
+		return candidateTimestamp
+	}
+
+	return absInt(candidate-note.MessageIndex) < absInt(current-note.MessageIndex)
+}
+
+func absInt(value int) int {
+	if value < 0 {
+		return -value
+	}
+
+	return value
+}
+
+// LoadAnnotations reads the annotations from store. A missing file is an empty set.
+func LoadAnnotations(store *LocalStore) (Annotations, error) {
+	data, err := store.ReadFile(AnnotationsFileName)
+	if errors.Is(err, fs.ErrNotExist) {
+		return Annotations{Conversations: []ConversationAnnotation{}}, nil
+	}
+	if err != nil {
+		return Annotations{}, fmt.Errorf("read annotations: %w", err)
+	}
+
+	var annotations Annotations
+	if err := json.Unmarshal(data, &annotations); err != nil {
+		return Annotations{}, fmt.Errorf("decode annotations: %w", err)
+	}
+	if annotations.Conversations == nil {
+		annotations.Conversations = []ConversationAnnotation{}
+	}
+
+	return annotations, nil
+}
+
+// SaveAnnotations writes annotations to store.
+func SaveAnnotations(store *LocalStore, annotations Annotations) error {
+	data, err := json.MarshalIndent(annotations, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encode annotations: %w", err)
+	}
+
+	return store.WriteFile(AnnotationsFileName, data)
+}
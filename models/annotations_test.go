@@ -0,0 +1,184 @@
+package models
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestUpdateConversationAnnotation(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	annotations := Annotations{Conversations: []ConversationAnnotation{}}
+
+	annotations, err := UpdateConversationAnnotation(annotations, "chatgpt", "conv-1", now, func(annotation *ConversationAnnotation) {
+		annotation.Starred = true
+		annotation.Tags = NormalizeTags([]string{" travel ", "Japan", "travel", "", "japan"})
+	})
+	if err != nil {
+		t.Fatalf("UpdateConversationAnnotation returned error: %v", err)
+	}
+	if len(annotations.Conversations) != 1 {
+		t.Fatalf("expected one annotation, got %+v", annotations.Conversations)
+	}
+	got := annotations.Conversations[0]
+	if !got.Starred || !reflect.DeepEqual(got.Tags, []string{"Japan", "travel"}) || got.UpdatedAt != "2026-03-01T12:00:00Z" {
+		t.Fatalf("unexpected annotation %+v", got)
+	}
+
+	annotations, _ = UpdateConversationAnnotation(annotations, "chatgpt", "conv-1", now, func(annotation *ConversationAnnotation) {
+		annotation.SetMessageNote(3, Message{Speaker: "assistant", Text: "Done.", Timestamp: "2026-01-01T00:00:03Z"}, " check this ")
+		annotation.SetMessageNote(1, Message{}, "first")
+	})
+	notes := annotations.Conversations[0].MessageNotes
+	if len(notes) != 2 || notes[0].MessageIndex != 1 || notes[1].Note != "check this" {
+		t.Fatalf("unexpected message notes %+v", notes)
+	}
+	if notes[0].MessageHash != "" || notes[1].MessageSpeaker != "assistant" || notes[1].MessageHash != messageTextHash("Done.") {
+		t.Fatalf("expected only the loaded message to be anchored, got %+v", notes)
+	}
+
+	annotations, _ = UpdateConversationAnnotation(annotations, "chatgpt", "conv-1", now, func(annotation *ConversationAnnotation) {
+		annotation.Starred = false
+		annotation.Tags = nil
+		annotation.SetMessageNote(1, Message{}, "")
+		annotation.SetMessageNote(3, Message{}, "  ")
+	})
+	if len(annotations.Conversations) != 0 {
+		t.Fatalf("expected an emptied annotation to be dropped, got %+v", annotations.Conversations)
+	}
+
+	if _, err := UpdateConversationAnnotation(annotations, "chatgpt", " ", now, func(*ConversationAnnotation) {}); err == nil {
+		t.Fatalf("expected an error without a conversation id")
+	}
+}
+
+func TestReattachMessageNotes(t *testing.T) {
+	prompt := Message{Speaker: "user", Text: "prompt", Timestamp: "2026-01-01T00:00:00Z"}
+	answer := Message{Speaker: "assistant", Text: "answer", Timestamp: "2026-01-01T00:00:01Z"}
+	annotations := Annotations{Conversations: []ConversationAnnotation{
+		{
+			Provider:       "chatgpt",
+			ConversationID: "conv-1",
+			MessageNotes: []MessageNote{
+				NewMessageNote(1, answer, "moved"),
+				NewMessageNote(0, prompt, "in place"),
+				{MessageIndex: 5, Note: "no anchor"},
+			},
+		},
+		{Provider: "claude", ConversationID: "gone", Note: "kept"},
+	}}
+	// The newer export recorded a tool call before the annotated answer.
+	conversations := []Conversation{{
+		Provider: "chatgpt",
+		ID:       "conv-1",
+		Messages: []Message{
+			prompt,
+			{Speaker: "assistant", Text: "tool call", Timestamp: "2026-01-01T00:00:00.5Z"},
+			answer,
+		},
+	}}
+
+	reattached := ReattachMessageNotes(annotations, conversations)
+	if got := noteIndexes(reattached.Conversations[0].MessageNotes); !reflect.DeepEqual(got, []int{2, 0, 5}) {
+		t.Fatalf("expected message indexes [2 0 5], got %v", got)
+	}
+	if annotations.Conversations[0].MessageNotes[0].MessageIndex != 1 {
+		t.Fatalf("expected the original annotations to stay untouched")
+	}
+	if reattached.Conversations[1].Note != "kept" {
+		t.Fatalf("expected annotations of missing conversations to be kept, got %+v", reattached.Conversations[1])
+	}
+}
+
+func TestReattachMessageNotesMatchesSpeakerAndText(t *testing.T) {
+	// Gemini prompts and answers, like the messages of an API log exchange, share a
+	// timestamp.
+	geminiPrompt := Message{Speaker: "user", Text: "Plan a trip", Timestamp: "2026-01-01T00:00:00Z"}
+	geminiAnswer := Message{Speaker: "assistant", Text: "Day one: Kyoto", Timestamp: "2026-01-01T00:00:00Z"}
+	firstContinue := Message{Speaker: "user", Text: "continue", Timestamp: "2026-01-01T00:01:00Z"}
+	secondContinue := Message{Speaker: "user", Text: "continue", Timestamp: "2026-01-01T00:02:00Z"}
+
+	tests := []struct {
+		name     string
+		notes    []MessageNote
+		messages []Message
+		want     []int
+	}{
+		{
+			name:     "follows the answer rather than the prompt with its timestamp",
+			notes:    []MessageNote{NewMessageNote(1, geminiAnswer, "answer")},
+			messages: []Message{{Speaker: "user", Text: "Hello", Timestamp: "2025-12-31T00:00:00Z"}, geminiPrompt, geminiAnswer},
+			want:     []int{2},
+		},
+		{
+			name:     "breaks ties between repeated text with the timestamp",
+			notes:    []MessageNote{NewMessageNote(1, secondContinue, "second")},
+			messages: []Message{geminiPrompt, firstContinue, geminiAnswer, secondContinue},
+			want:     []int{3},
+		},
+		{
+			name:     "moves notes on repeated text to different messages in order",
+			notes:    []MessageNote{NewMessageNote(0, Message{Speaker: "user", Text: "continue"}, "first"), NewMessageNote(1, Message{Speaker: "user", Text: "continue"}, "second")},
+			messages: []Message{geminiPrompt, {Speaker: "user", Text: "continue"}, {Speaker: "user", Text: "continue"}},
+			want:     []int{1, 2},
+		},
+		{
+			name:     "keeps the index when the message is gone",
+			notes:    []MessageNote{NewMessageNote(1, Message{Speaker: "user", Text: "deleted"}, "orphan")},
+			messages: []Message{geminiPrompt, geminiAnswer},
+			want:     []int{1},
+		},
+		{
+			name:     "matches notes saved without a hash on the timestamp",
+			notes:    []MessageNote{{MessageIndex: 0, MessageTimestamp: "2026-01-01T00:01:00Z", Note: "legacy"}},
+			messages: []Message{geminiPrompt, firstContinue},
+			want:     []int{1},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			annotations := Annotations{Conversations: []ConversationAnnotation{{Provider: ProviderGemini, ConversationID: "g-1", MessageNotes: tt.notes}}}
+			conversations := []Conversation{{Provider: ProviderGemini, ID: "g-1", Messages: tt.messages}}
+
+			reattached := ReattachMessageNotes(annotations, conversations)
+			if got := noteIndexes(reattached.Conversations[0].MessageNotes); !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("expected message indexes %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func noteIndexes(notes []MessageNote) []int {
+	indexes := []int{}
+	for _, note := range notes {
+		indexes = append(indexes, note.MessageIndex)
+	}
+
+	return indexes
+}
+
+func TestAnnotationsRoundTrip(t *testing.T) {
+	store, err := OpenLocalStore(filepath.Join(t.TempDir(), "chat-explorer"))
+	if err != nil {
+		t.Fatalf("OpenLocalStore returned error: %v", err)
+	}
+
+	empty, err := LoadAnnotations(store)
+	if err != nil || empty.Conversations == nil || len(empty.Conversations) != 0 {
+		t.Fatalf("expected empty annotations, got %+v (%v)", empty, err)
+	}
+
+	annotations := Annotations{
+		UpdatedAt:     "2026-03-01T12:00:00Z",
+		Conversations: []ConversationAnnotation{{Provider: "claude", ConversationID: "c1", Note: "Follow up"}},
+	}
+	if err := SaveAnnotations(store, annotations); err != nil {
+		t.Fatalf("SaveAnnotations returned error: %v", err)
+	}
+	loaded, err := LoadAnnotations(store)
+	if err != nil || !reflect.DeepEqual(loaded, annotations) {
+		t.Fatalf("expected %+v, got %+v (%v)", annotations, loaded, err)
+	}
+}
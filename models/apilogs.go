@@ -0,0 +1,354 @@
+package models
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// API usage logs hold one request/response exchange per record, typically one per
+// JSONL line. The request carries the conversation so far and the response the
+// model's reply. Both OpenAI-compatible chat completions and the Anthropic Messages
+// API are recognized, either bare or inside the envelopes used by batch APIs and
+// logging proxies ({"request": ..., "response": ...}, {"custom_id", "body"}, ...).
+
+// unixMillisecondsThreshold separates Unix timestamps in seconds from ones in
+// milliseconds; seconds stay below it until the year 33658.
+const unixMillisecondsThreshold = 1e12
+
+type rawAPIMessage struct {
+	Role       string           `json:"role"`
+	Content    any              `json:"content"`
+	Name       string           `json:"name"`
+	ToolCalls  []rawAPIToolCall `json:"tool_calls"`
+	ToolCallID string           `json:"tool_call_id"`
+}
+
+type rawAPIToolCall struct {
+	ID       string `json:"id"`
+	Function struct {
+		Name      string `json:"name"`
+		Arguments string `json:"arguments"`
+	} `json:"function"`
+}
+
+type rawAPIChoice struct {
+	Message      rawAPIMessage `json:"message"`
+	FinishReason string        `json:"finish_reason"`
+}
+
+type apiExchange struct {
+	id        string
+	model     string
+	timestamp string
+	request   map[string]json.RawMessage
+	response  map[string]json.RawMessage
+}
+
+type anthropicAPIFormat struct{}
+
+func (anthropicAPIFormat) Detect(fields map[string]json.RawMessage) bool {
+	exchange, ok := splitAPIExchange(fields)
+	if !ok {
+		return false
+	}
+
+	if decodeJSONString(exchange.response["type"]) == "message" {
+		return true
+	}
+	if strings.HasPrefix(strings.ToLower(exchange.model), "claude") {
+		return true
+	}
+	_, hasSystem := exchange.request["system"]
+	_, hasAnthropicVersion := exchange.request["anthropic_version"]
+	return hasSystem || hasAnthropicVersion
+}
+
+func (anthropicAPIFormat) Parse(rawConversationJSON json.RawMessage) ([]Conversation, error) {
+	return parseAPIExchange(rawConversationJSON, ProviderAnthropic)
+}
+
+type openAIAPIFormat struct{}
+
+func (openAIAPIFormat) Detect(fields map[string]json.RawMessage) bool {
+	_, ok := splitAPIExchange(fields)
+	return ok
+}
+
+func (openAIAPIFormat) Parse(rawConversationJSON json.RawMessage) ([]Conversation, error) {
+	return parseAPIExchange(rawConversationJSON, ProviderOpenAI)
+}
+
+func parseAPIExchange(rawConversationJSON json.RawMessage, provider string) ([]Conversation, error) {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(rawConversationJSON, &fields); err != nil {
+		return nil, fmt.Errorf("decode %s api log: %w", provider, err)
+	}
+
+	exchange, ok := splitAPIExchange(fields)
+	if !ok {
+		return nil, fmt.Errorf("decode %s api log: no request messages or response", provider)
+	}
+
+	messages := make([]Message, 0, 8)
+	toolNames := make(map[string]string)
+	if rawSystem, hasSystem := exchange.request["system"]; hasSystem {
+		var system any
+		if err := json.Unmarshal(rawSystem, &system); err != nil {
+			return nil, fmt.Errorf("decode %s api system prompt: %w", provider, err)
+		}
+		messages = appendAPIMessage(messages, rawAPIMessage{Role: "system", Content: system}, exchange.timestamp, toolNames)
+	}
+
+	if rawMessages, hasMessages := exchange.request["messages"]; hasMessages {
+		var requestMessages []rawAPIMessage
+		if err := json.Unmarshal(rawMessages, &requestMessages); err != nil {
+			return nil, fmt.Errorf("decode %s api messages: %w", provider, err)
+		}
+		for _, requestMessage := range requestMessages {
+			messages = appendAPIMessage(messages, requestMessage, exchange.timestamp, toolNames)
+		}
+	}
+
+	if reply, hasReply, err := decodeAPIReply(exchange.response); err != nil {
+		return nil, fmt.Errorf("decode %s api response: %w", provider, err)
+	} else if hasReply {
+		requestMessageCount := len(messages)
+		messages = appendAPIMessage(messages, reply, exchange.timestamp, toolNames)
+		if len(messages) > requestMessageCount {
+			messages[requestMessageCount].Model = exchange.model
+			messages[requestMessageCount].FinishReason = decodeAPIFinishReason(exchange.response)
+		}
+	}
+
+	conversationID := exchange.id
+	if conversationID == "" {
+		conversationID = apiConversationID(provider, rawConversationJSON)
+	}
+
+	return []Conversation{{
+		Provider:  provider,
+		ID:        conversationID,
+		Name:      resolveConversationName("", messages),
+		CreatedAt: exchange.timestamp,
+		Messages:  messages,
+	}}, nil
+}
+
+// apiConversationID derives a stable identifier from the record itself for logs
+// that carry neither a custom_id nor a response id.
+func apiConversationID(provider string, rawConversationJSON json.RawMessage) string {
+	digest := sha256.Sum256(bytes.TrimSpace(rawConversationJSON))
+	return provider + "-" + hex.EncodeToString(digest[:8])
+}
+
+// appendAPIMessage appends the text of an API message followed by any tool calls
+// (OpenAI tool_calls, Anthropic tool_use blocks) and tool results (Anthropic
+// tool_result blocks) it carries. toolNames maps tool call IDs to tool names so
+// results can be attributed to the tool that produced them.
+func appendAPIMessage(messages []Message, message rawAPIMessage, timestamp string, toolNames map[string]string) []Message {
+	speaker := strings.TrimSpace(message.Role)
+	if speaker == "" {
+		speaker = "unknown"
+	}
+
+	parts := make([]string, 0, 1)
+	toolMessages := make([]Message, 0)
+	if blocks, isBlocks := message.Content.([]any); isBlocks {
+		for _, block := range blocks {
+			blockFields, _ := block.(map[string]any)
+			switch blockFields["type"] {
+			case "tool_use":
+				name := metadataString(blockFields, "name")
+				toolNames[metadataString(blockFields, "id")] = name
+				input, _ := json.Marshal(blockFields["input"])
+				toolMessages = append(toolMessages, Message{
+					Speaker:   speaker,
+					Text:      string(input),
+					Timestamp: timestamp,
+					Kind:      MessageKindToolCall,
+					Recipient: name,
+				})
+			case "tool_result":
+				resultParts := make([]string, 0, 1)
+				collectText(blockFields["content"], &resultParts)
+				if len(resultParts) == 0 {
+					continue
+				}
+				toolMessages = append(toolMessages, Message{
+					Speaker:   "tool",
+					Text:      strings.Join(resultParts, "\n"),
+					Timestamp: timestamp,
+					Kind:      MessageKindToolResult,
+					ToolName:  toolNames[metadataString(blockFields, "tool_use_id")],
+				})
+			default:
+				collectText(block, &parts)
+			}
+		}
+	} else {
+		collectText(message.Content, &parts)
+	}
+
+	for _, toolCall := range message.ToolCalls {
+		name := strings.TrimSpace(toolCall.Function.Name)
+		toolNames[toolCall.ID] = name
+		toolMessages = append(toolMessages, Message{
+			Speaker:   speaker,
+			Text:      strings.TrimSpace(toolCall.Function.Arguments),
+			Timestamp: timestamp,
+			Kind:      MessageKindToolCall,
+			Recipient: name,
+		})
+	}
+
+	if text := strings.Join(parts, "\n"); text != "" {
+		textMessage := Message{Speaker: speaker, Text: text, Timestamp: timestamp}
+		if kind := defaultMessageKind(speaker); kind == MessageKindToolResult {
+			textMessage.Kind = kind
+			textMessage.ToolName = firstNonBlank(toolNames[message.ToolCallID], message.Name)
+		}
+		messages = append(messages, textMessage)
+	}
+
+	return append(messages, toolMessages...)
+}
+
+// decodeAPIReply returns the assistant message of a chat completion (first choice) or
+// of an Anthropic message response.
+func decodeAPIReply(response map[string]json.RawMessage) (rawAPIMessage, bool, error) {
+	if rawChoices, hasChoices := response["choices"]; hasChoices {
+		var choices []rawAPIChoice
+		if err := json.Unmarshal(rawChoices, &choices); err != nil {
+			return rawAPIMessage{}, false, err
+		}
+		if len(choices) == 0 {
+			return rawAPIMessage{}, false, nil
+		}
+		reply := choices[0].Message
+		if reply.Role == "" {
+			reply.Role = "assistant"
+		}
+		return reply, true, nil
+	}
+
+	if rawContent, hasContent := response["content"]; hasContent {
+		reply := rawAPIMessage{Role: decodeJSONString(response["role"])}
+		if err := json.Unmarshal(rawContent, &reply.Content); err != nil {
+			return rawAPIMessage{}, false, err
+		}
+		if reply.Role == "" {
+			reply.Role = "assistant"
+		}
+		return reply, true, nil
+	}
+
+	return rawAPIMessage{}, false, nil
+}
+
+// decodeAPIFinishReason returns the first choice's finish_reason of a chat completion
+// or the stop_reason of an Anthropic message.
+func decodeAPIFinishReason(response map[string]json.RawMessage) string {
+	var choices []rawAPIChoice
+	if err := json.Unmarshal(response["choices"], &choices); err == nil && len(choices) > 0 {
+		return strings.TrimSpace(choices[0].FinishReason)
+	}
+
+	return decodeJSONString(response["stop_reason"])
+}
+
+// splitAPIExchange locates the request and response of a logged API call. It reports
+// false when the record has neither request messages nor a recognizable response.
+func splitAPIExchange(fields map[string]json.RawMessage) (apiExchange, bool) {
+	exchange := apiExchange{}
+
+	switch {
+	case decodeJSONObject(fields["request"]) != nil:
+		exchange.request = decodeJSONObject(fields["request"])
+	case decodeJSONObject(fields["body"]) != nil:
+		// OpenAI batch input lines: {"custom_id", "method", "url", "body": {...}}.
+		exchange.request = decodeJSONObject(fields["body"])
+	case decodeJSONObject(fields["params"]) != nil:
+		// Anthropic batch requests: {"custom_id", "params": {...}}.
+		exchange.request = decodeJSONObject(fields["params"])
+	default:
+		if _, hasMessages := fields["messages"]; hasMessages {
+			exchange.request = fields
+		}
+	}
+
+	if response := decodeJSONObject(fields["response"]); response != nil {
+		exchange.response = response
+		// OpenAI batch output lines nest the completion under response.body.
+		if body := decodeJSONObject(response["body"]); body != nil {
+			exchange.response = body
+		}
+	} else if result := decodeJSONObject(fields["result"]); result != nil {
+		// Anthropic batch results: {"custom_id", "result": {"type", "message": {...}}}.
+		exchange.response = decodeJSONObject(result["message"])
+	} else if isAPIResponse(fields) {
+		exchange.response = fields
+	}
+
+	_, hasRequestMessages := exchange.request["messages"]
+	if !hasRequestMessages && !isAPIResponse(exchange.response) {
+		return apiExchange{}, false
+	}
+
+	exchange.id = firstNonBlank(
+		decodeJSONString(fields["custom_id"]),
+		decodeJSONString(exchange.response["id"]),
+		decodeJSONString(fields["id"]),
+	)
+	exchange.model = firstNonBlank(decodeJSONString(exchange.request["model"]), decodeJSONString(exchange.response["model"]))
+	exchange.timestamp = firstNonBlank(
+		decodeLogTimestamp(exchange.response["created"]),
+		decodeLogTimestamp(fields["timestamp"]),
+		decodeLogTimestamp(fields["created_at"]),
+		decodeLogTimestamp(fields["time"]),
+	)
+
+	return exchange, true
+}
+
+func isAPIResponse(fields map[string]json.RawMessage) bool {
+	if _, hasChoices := fields["choices"]; hasChoices {
+		return true
+	}
+
+	return decodeJSONString(fields["type"]) == "message"
+}
+
+// decodeLogTimestamp accepts Unix seconds or milliseconds as well as textual timestamps.
+func decodeLogTimestamp(raw json.RawMessage) string {
+	var unixTimestamp float64
+	if err := json.Unmarshal(raw, &unixTimestamp); err == nil {
+		if unixTimestamp >= unixMillisecondsThreshold {
+			unixTimestamp /= 1000
+		}
+		return formatUnixTimestamp(&unixTimestamp)
+	}
+
+	return normalizeTimestamp(decodeJSONString(raw))
+}
+
+func decodeJSONObject(raw json.RawMessage) map[string]json.RawMessage {
+	var object map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &object); err != nil {
+		return nil
+	}
+
+	return object
+}
+
+func decodeJSONString(raw json.RawMessage) string {
+	var value string
+	if err := json.Unmarshal(raw, &value); err != nil {
+		return ""
+	}
+
+	return strings.TrimSpace(value)
+}
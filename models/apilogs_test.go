@@ -0,0 +1,170 @@
+package models
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+const (
+	openAIChatCompletionsFixturePath = "testdata/openai_chat_completions.jsonl"
+	anthropicMessagesFixturePath     = "testdata/anthropic_messages.jsonl"
+)
+
+func TestParseOpenAIChatCompletionsJSONL(t *testing.T) {
+	fixture := loadFixture(t, openAIChatCompletionsFixturePath)
+	result, err := ParseConversations(strings.NewReader(fixture), ParseOptions{})
+	if err != nil {
+		t.Fatalf("ParseConversations returned error: %v", err)
+	}
+
+	datasetID := apiConversationID(ProviderOpenAI, json.RawMessage(fixtureLine(t, fixture, 0)))
+	want := []ConversationEntry{
+		apiEntry(ProviderOpenAI, datasetID, "Name a prime number.", "", "system", "You are terse.", MessageKindSystem, 0, 4),
+		apiEntry(ProviderOpenAI, datasetID, "Name a prime number.", "", "user", "Name a prime number.", MessageKindPrompt, 1, 5),
+		apiEntry(ProviderOpenAI, datasetID, "Name a prime number.", "", "assistant", "7", MessageKindAnswer, 2, 1),
+		apiEntry(ProviderOpenAI, "chatcmpl-abc", "Translate 'cat' to French", "2024-05-29T16:26:40Z", "user", "Translate 'cat' to French", MessageKindPrompt, 0, 6),
+		withReplyMetadata(apiEntry(ProviderOpenAI, "chatcmpl-abc", "Translate 'cat' to French", "2024-05-29T16:26:40Z", "assistant", "chat", MessageKindAnswer, 1, 1), "gpt-4o-mini", "stop"),
+		apiEntry(ProviderOpenAI, "req-42", "Batch answer", "2024-05-29T16:28:20Z", "assistant", "Batch answer", MessageKindAnswer, 0, 2),
+	}
+
+	assertConversationEntries(t, result.Entries(), want)
+}
+
+func TestParseAnthropicMessagesJSONL(t *testing.T) {
+	fixture := loadFixture(t, anthropicMessagesFixturePath)
+	result, err := ParseConversations(strings.NewReader(fixture), ParseOptions{})
+	if err != nil {
+		t.Fatalf("ParseConversations returned error: %v", err)
+	}
+
+	requestOnlyID := apiConversationID(ProviderAnthropic, json.RawMessage(fixtureLine(t, fixture, 2)))
+	want := []ConversationEntry{
+		apiEntry(ProviderAnthropic, "msg_01ABC", "Why is the sky blue?", "2025-02-03T04:05:06Z", "system", "Answer in one sentence.", MessageKindSystem, 0, 6),
+		apiEntry(ProviderAnthropic, "msg_01ABC", "Why is the sky blue?", "2025-02-03T04:05:06Z", "user", "Why is the sky blue?", MessageKindPrompt, 1, 7),
+		withReplyMetadata(apiEntry(ProviderAnthropic, "msg_01ABC", "Why is the sky blue?", "2025-02-03T04:05:06Z", "assistant", "Rayleigh scattering favors shorter wavelengths.", MessageKindAnswer, 2, 8), "claude-3-5-sonnet-20241022", "end_turn"),
+		apiEntry(ProviderAnthropic, "batch-7", "Batched reply", "", "assistant", "Batched reply", MessageKindAnswer, 0, 4),
+		apiEntry(ProviderAnthropic, requestOnlyID, "First turn", "2025-02-03T04:05:06Z", "user", "First turn", MessageKindPrompt, 0, 3),
+		apiEntry(ProviderAnthropic, requestOnlyID, "First turn", "2025-02-03T04:05:06Z", "assistant", "Second turn", MessageKindAnswer, 1, 3),
+		apiEntry(ProviderAnthropic, requestOnlyID, "First turn", "2025-02-03T04:05:06Z", "user", "Third turn", MessageKindPrompt, 2, 3),
+	}
+
+	assertConversationEntries(t, result.Entries(), want)
+}
+
+func TestParseJSONLLenient(t *testing.T) {
+	input := strings.Join([]string{
+		`{"custom_id":"ok-1","messages":[{"role":"user","content":"hello"}]}`,
+		`{"custom_id":"broken", "messages": [`,
+		`{"custom_id":"ok-2","messages":[{"role":"user","content":"again"}]}`,
+	}, "\n")
+
+	t.Run("strict mode fails on a corrupt line", func(t *testing.T) {
+		_, err := ParseConversations(strings.NewReader(input), ParseOptions{})
+		assertErrorContains(t, err, "decode conversations json")
+	})
+
+	t.Run("lenient mode reports the corrupt line and keeps the rest", func(t *testing.T) {
+		result, err := ParseConversations(strings.NewReader(input), ParseOptions{Lenient: true})
+		if err != nil {
+			t.Fatalf("ParseConversations returned error: %v", err)
+		}
+
+		assertConversationEntries(t, result.Entries(), []ConversationEntry{
+			apiEntry(ProviderOpenAI, "ok-1", "hello", "", "user", "hello", MessageKindPrompt, 0, 1),
+			apiEntry(ProviderOpenAI, "ok-2", "again", "", "user", "again", MessageKindPrompt, 0, 1),
+		})
+		if len(result.Warnings) != 1 || result.Warnings[0].Index != 1 {
+			t.Fatalf("expected one warning for line index 1, got %+v", result.Warnings)
+		}
+	})
+}
+
+func TestIsJSONLines(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  bool
+	}{
+		{name: "single line", input: `{"messages":[]}`, want: false},
+		{name: "several complete lines", input: "{\"a\":1}\n{\"b\":2}\n", want: true},
+		{name: "pretty-printed document", input: "[\n  {\"a\": 1}\n]", want: false},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			if got := isJSONLines([]byte(testCase.input)); got != testCase.want {
+				t.Fatalf("isJSONLines = %v, want %v", got, testCase.want)
+			}
+		})
+	}
+}
+
+func fixtureLine(t *testing.T, fixture string, index int) string {
+	t.Helper()
+
+	lines := strings.Split(strings.TrimSpace(fixture), "\n")
+	if index >= len(lines) {
+		t.Fatalf("fixture has %d lines, wanted line %d", len(lines), index)
+	}
+
+	return lines[index]
+}
+
+func withReplyMetadata(entry ConversationEntry, model string, finishReason string) ConversationEntry {
+	entry.Model = model
+	entry.FinishReason = finishReason
+	return entry
+}
+
+func apiEntry(provider string, conversationID string, conversationName string, timestamp string, speaker string, message string, kind string, messageIndex int, tokenCount int) ConversationEntry {
+	return ConversationEntry{
+		Provider:              provider,
+		ConversationID:        conversationID,
+		ConversationName:      conversationName,
+		ConversationCreatedAt: timestamp,
+		Speaker:               speaker,
+		Message:               message,
+		MessageTimestamp:      timestamp,
+		Kind:                  kind,
+		MessageIndex:          messageIndex,
+		TokenCount:            tokenCount,
+	}
+}
+
+func TestParseAPILogToolCalls(t *testing.T) {
+	input := strings.Join([]string{
+		`{"custom_id":"openai-tools","messages":[` +
+			`{"role":"user","content":"Weather in Oslo?"},` +
+			`{"role":"assistant","content":null,"tool_calls":[{"id":"call_1","type":"function","function":{"name":"get_weather","arguments":"{\"city\":\"Oslo\"}"}}]},` +
+			`{"role":"tool","tool_call_id":"call_1","content":"4°C"},` +
+			`{"role":"assistant","content":"It is 4°C."}]}`,
+		`{"custom_id":"anthropic-tools","model":"claude-3-5-sonnet","messages":[` +
+			`{"role":"user","content":"Weather in Oslo?"},` +
+			`{"role":"assistant","content":[{"type":"text","text":"Let me check."},{"type":"tool_use","id":"toolu_1","name":"get_weather","input":{"city":"Oslo"}}]},` +
+			`{"role":"user","content":[{"type":"tool_result","tool_use_id":"toolu_1","content":[{"type":"text","text":"4°C"}]}]}]}`,
+	}, "\n")
+
+	result, err := ParseConversations(strings.NewReader(input), ParseOptions{})
+	if err != nil {
+		t.Fatalf("ParseConversations returned error: %v", err)
+	}
+
+	toolEntry := func(provider string, id string, speaker string, message string, kind string, messageIndex int, tokenCount int, recipient string, toolName string) ConversationEntry {
+		toolEntry := apiEntry(provider, id, "Weather in Oslo?", "", speaker, message, kind, messageIndex, tokenCount)
+		toolEntry.Recipient = recipient
+		toolEntry.ToolName = toolName
+		return toolEntry
+	}
+
+	assertConversationEntries(t, result.Entries(), []ConversationEntry{
+		toolEntry(ProviderOpenAI, "openai-tools", "user", "Weather in Oslo?", MessageKindPrompt, 0, 4, "", ""),
+		toolEntry(ProviderOpenAI, "openai-tools", "assistant", `{"city":"Oslo"}`, MessageKindToolCall, 1, 6, "get_weather", ""),
+		toolEntry(ProviderOpenAI, "openai-tools", "tool", "4°C", MessageKindToolResult, 2, 2, "", "get_weather"),
+		toolEntry(ProviderOpenAI, "openai-tools", "assistant", "It is 4°C.", MessageKindAnswer, 3, 6, "", ""),
+		toolEntry(ProviderAnthropic, "anthropic-tools", "user", "Weather in Oslo?", MessageKindPrompt, 0, 5, "", ""),
+		toolEntry(ProviderAnthropic, "anthropic-tools", "assistant", "Let me check.", MessageKindAnswer, 1, 5, "", ""),
+		toolEntry(ProviderAnthropic, "anthropic-tools", "assistant", `{"city":"Oslo"}`, MessageKindToolCall, 2, 7, "get_weather", ""),
+		toolEntry(ProviderAnthropic, "anthropic-tools", "tool", "4°C", MessageKindToolResult, 3, 3, "", "get_weather"),
+	})
+}
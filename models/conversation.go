@@ -0,0 +1,133 @@
+package models
+
+import "strings"
+
+// Conversation is the normalized form every Format produces, independent of the
+// provider's export schema.
+type Conversation struct {
+	Provider string `json:"provider"`
+	ID       string `json:"id"`
+	Name     string `json:"name"`
+	// GeneratedTitle is derived from the first exchange when Name is blank or a
+	// placeholder such as "New chat"; Name keeps the provider's title.
+	GeneratedTitle string         `json:"generatedTitle,omitempty"`
+	CreatedAt      string         `json:"createdAt"`
+	Messages       []Message      `json:"messages"`
+	SystemContext  *SystemContext `json:"systemContext,omitempty"`
+	// TokenCount is the sum of the messages' estimated token counts.
+	TokenCount int `json:"tokenCount"`
+}
+
+// SystemContext is the hidden context a provider injected into a conversation, such as
+// the custom instructions ChatGPT sends ahead of the first prompt.
+type SystemContext struct {
+	UserProfile      string `json:"userProfile,omitempty"`
+	UserInstructions string `json:"userInstructions,omitempty"`
+}
+
+// Message kinds separate the human-facing exchange from tool and reasoning chatter.
+const (
+	MessageKindPrompt     = "prompt"
+	MessageKindAnswer     = "answer"
+	MessageKindToolCall   = "tool_call"
+	MessageKindToolResult = "tool_result"
+	MessageKindReasoning  = "reasoning"
+	MessageKindSystem     = "system"
+)
+
+// Message is a single visible message inside a Conversation. Model, FinishReason,
+// Citations and SearchQueries are only set by formats whose exports record them.
+// Recipient names the tool a tool call is addressed to; ToolName the tool that
+// produced a tool result. TokenCount is estimated with the tokenizer of the message's
+// model, see TokenizerForModel. CodeLanguage is set when the whole message is code,
+// such as ChatGPT "code" content. Attachments are the files sent with the message whose
+// contents the export includes.
+type Message struct {
+	Speaker       string       `json:"speaker"`
+	Text          string       `json:"text"`
+	Timestamp     string       `json:"timestamp"`
+	Kind          string       `json:"kind,omitempty"`
+	Recipient     string       `json:"recipient,omitempty"`
+	ToolName      string       `json:"toolName,omitempty"`
+	Model         string       `json:"model,omitempty"`
+	FinishReason  string       `json:"finishReason,omitempty"`
+	Citations     []Citation   `json:"citations,omitempty"`
+	SearchQueries []string     `json:"searchQueries,omitempty"`
+	TokenCount    int          `json:"tokenCount,omitempty"`
+	CodeLanguage  string       `json:"codeLanguage,omitempty"`
+	Attachments   []Attachment `json:"attachments,omitempty"`
+}
+
+// Attachment is a file sent with a message. Content holds the text the provider
+// extracted from it and is empty when the export only names the file.
+type Attachment struct {
+	Name     string `json:"name"`
+	FileType string `json:"fileType,omitempty"`
+	Content  string `json:"content,omitempty"`
+}
+
+// Citation is a source an answer referenced, such as a web page found while browsing.
+type Citation struct {
+	Title string `json:"title,omitempty"`
+	URL   string `json:"url"`
+}
+
+// Entries flattens the conversation into the per-message rows sent to the frontend.
+func (c Conversation) Entries() []ConversationEntry {
+	entries := make([]ConversationEntry, 0, len(c.Messages))
+	for index, message := range c.Messages {
+		entries = append(entries, ConversationEntry{
+			Provider:              c.Provider,
+			ConversationID:        c.ID,
+			ConversationName:      c.Name,
+			ConversationCreatedAt: c.CreatedAt,
+			ConversationTitle:     c.GeneratedTitle,
+			MessageIndex:          index,
+			Speaker:               message.Speaker,
+			Message:               message.Text,
+			MessageTimestamp:      message.Timestamp,
+			Kind:                  message.Kind,
+			Recipient:             message.Recipient,
+			ToolName:              message.ToolName,
+			Model:                 message.Model,
+			FinishReason:          message.FinishReason,
+			Citations:             message.Citations,
+			SearchQueries:         message.SearchQueries,
+			TokenCount:            message.TokenCount,
+		})
+	}
+
+	return entries
+}
+
+// defaultMessageKind infers the kind of a message from its speaker, for formats that
+// do not classify messages themselves. Unrecognized speakers stay unclassified.
+func defaultMessageKind(speaker string) string {
+	switch strings.ToLower(strings.TrimSpace(speaker)) {
+	case "user", "human":
+		return MessageKindPrompt
+	case "assistant", "bot", "ai", "model":
+		return MessageKindAnswer
+	case "system", "developer":
+		return MessageKindSystem
+	case "tool", "function":
+		return MessageKindToolResult
+	default:
+		return ""
+	}
+}
+
+// FlattenConversations returns the entries of every conversation, in order.
+func FlattenConversations(conversations []Conversation) []ConversationEntry {
+	entryCount := 0
+	for _, conversation := range conversations {
+		entryCount += len(conversation.Messages)
+	}
+
+	entries := make([]ConversationEntry, 0, entryCount)
+	for _, conversation := range conversations {
+		entries = append(entries, conversation.Entries()...)
+	}
+
+	return entries
+}
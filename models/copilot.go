@@ -0,0 +1,85 @@
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// Microsoft Copilot exports each conversation as an object holding a flat,
+// chronological message list; messages are authored by "user" or "bot".
+
+type rawCopilotConversation struct {
+	ConversationID string              `json:"conversationId"`
+	Title          string              `json:"title"`
+	CreatedAt      string              `json:"createdAt"`
+	Messages       []rawCopilotMessage `json:"messages"`
+}
+
+type rawCopilotMessage struct {
+	Author    string `json:"author"`
+	Role      string `json:"role"`
+	Text      string `json:"text"`
+	Content   any    `json:"content"`
+	CreatedAt string `json:"createdAt"`
+	Timestamp string `json:"timestamp"`
+}
+
+type copilotFormat struct{}
+
+func (copilotFormat) Detect(fields map[string]json.RawMessage) bool {
+	_, hasConversationID := fields["conversationId"]
+	_, hasMessages := fields["messages"]
+	return hasConversationID && hasMessages
+}
+
+func (copilotFormat) Parse(rawConversationJSON json.RawMessage) ([]Conversation, error) {
+	var conversation rawCopilotConversation
+	if err := json.Unmarshal(rawConversationJSON, &conversation); err != nil {
+		return nil, fmt.Errorf("decode copilot conversation: %w", err)
+	}
+
+	messages := make([]Message, 0, len(conversation.Messages))
+	oldestMessageTimestamp := ""
+	for _, rawMessage := range conversation.Messages {
+		text := strings.TrimSpace(rawMessage.Text)
+		if text == "" {
+			parts := make([]string, 0, 1)
+			collectText(rawMessage.Content, &parts)
+			text = strings.Join(parts, "\n")
+		}
+		if text == "" {
+			continue
+		}
+
+		speaker := rawMessage.Author
+		if strings.TrimSpace(speaker) == "" {
+			speaker = rawMessage.Role
+		}
+		timestamp := rawMessage.CreatedAt
+		if strings.TrimSpace(timestamp) == "" {
+			timestamp = rawMessage.Timestamp
+		}
+		timestamp = normalizeTimestamp(timestamp)
+
+		oldestMessageTimestamp = olderTimestamp(oldestMessageTimestamp, timestamp)
+		messages = append(messages, Message{
+			Speaker:   normalizeAssistantSpeaker(speaker),
+			Text:      text,
+			Timestamp: timestamp,
+		})
+	}
+
+	createdAt := normalizeTimestamp(conversation.CreatedAt)
+	if createdAt == "" {
+		createdAt = oldestMessageTimestamp
+	}
+
+	return []Conversation{{
+		Provider:  ProviderCopilot,
+		ID:        strings.TrimSpace(conversation.ConversationID),
+		Name:      resolveConversationName(conversation.Title, messages),
+		CreatedAt: createdAt,
+		Messages:  messages,
+	}}, nil
+}
@@ -0,0 +1,70 @@
+package models
+
+import (
+	"strings"
+	"testing"
+)
+
+const (
+	copilotConversationsJSONFixturePath = "testdata/copilot_conversations.json"
+	copilotActivityCSVFixturePath       = "testdata/copilot_activity.csv"
+)
+
+func TestParseCopilotConversationsJSON(t *testing.T) {
+	result, err := ParseConversations(strings.NewReader(loadFixture(t, copilotConversationsJSONFixturePath)), ParseOptions{})
+	if err != nil {
+		t.Fatalf("ParseConversations returned error: %v", err)
+	}
+
+	want := []ConversationEntry{
+		copilotEntry("copilot-conv-1", "Trip planning", "2025-05-01T09:00:00Z", "user", "Plan a weekend in Lisbon", "2025-05-01T09:00:05Z", MessageKindPrompt, 0, 5),
+		copilotEntry("copilot-conv-1", "Trip planning", "2025-05-01T09:00:00Z", "assistant", "Day 1: Alfama and the castle.\nDay 2: Belém and pastéis.", "2025-05-01T09:00:12Z", MessageKindAnswer, 1, 20),
+		copilotEntry("copilot-conv-2", "Summarize this email thread", "2025-05-02T12:30:00Z", "user", "Summarize this email thread", "2025-05-02T12:30:00Z", MessageKindPrompt, 0, 6),
+		copilotEntry("copilot-conv-2", "Summarize this email thread", "2025-05-02T12:30:00Z", "assistant", "The team agreed to ship on Friday.", "2025-05-02T12:30:04Z", MessageKindAnswer, 1, 8),
+	}
+
+	assertConversationEntries(t, result.Entries(), want)
+}
+
+func TestParseCopilotActivityCSV(t *testing.T) {
+	result, err := ParseConversations(strings.NewReader(loadFixture(t, copilotActivityCSVFixturePath)), ParseOptions{})
+	if err != nil {
+		t.Fatalf("ParseConversations returned error: %v", err)
+	}
+
+	tripID := csvConversationID(ProviderCopilot, "Trip planning")
+	emailID := csvConversationID(ProviderCopilot, "Email summary")
+	want := []ConversationEntry{
+		copilotEntry(tripID, "Trip planning", "2025-05-01T09:00:05Z", "user", "Plan a weekend in Lisbon", "2025-05-01T09:00:05Z", MessageKindPrompt, 0, 5),
+		copilotEntry(tripID, "Trip planning", "2025-05-01T09:00:05Z", "assistant", "Day 1: Alfama and the castle.\nDay 2: Belém and pastéis.", "2025-05-01T09:00:12Z", MessageKindAnswer, 1, 20),
+		copilotEntry(emailID, "Email summary", "2025-05-02T12:30:00Z", "user", "Summarize this email thread", "2025-05-02T12:30:00Z", MessageKindPrompt, 0, 6),
+		copilotEntry(emailID, "Email summary", "2025-05-02T12:30:00Z", "assistant", `The team agreed to ship on Friday, "no later" than 5pm.`, "2025-05-02T12:30:04Z", MessageKindAnswer, 1, 17),
+	}
+
+	assertConversationEntries(t, result.Entries(), want)
+}
+
+func copilotEntry(
+	conversationID string,
+	conversationName string,
+	conversationCreatedAt string,
+	speaker string,
+	message string,
+	messageTimestamp string,
+	kind string,
+	messageIndex int,
+	tokenCount int,
+) ConversationEntry {
+	return ConversationEntry{
+		Provider:              ProviderCopilot,
+		ConversationID:        conversationID,
+		ConversationName:      conversationName,
+		ConversationCreatedAt: conversationCreatedAt,
+		Speaker:               speaker,
+		Message:               message,
+		MessageTimestamp:      messageTimestamp,
+		Kind:                  kind,
+		MessageIndex:          messageIndex,
+		TokenCount:            tokenCount,
+	}
+}
@@ -0,0 +1,225 @@
+package models
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/csv"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// CSV exports come in two layouts: one row per message (speaker and message columns,
+// as in Copilot's activity export) or one row per exchange (query and answer columns,
+// as in Perplexity thread exports). Column names vary between tools and locales, so
+// headers are matched against aliases rather than exact names.
+
+type csvColumn int
+
+const (
+	csvColumnConversation csvColumn = iota
+	csvColumnTitle
+	csvColumnTimestamp
+	csvColumnSpeaker
+	csvColumnMessage
+	csvColumnQuery
+	csvColumnAnswer
+	csvColumnProvider
+)
+
+// csvColumnAliases maps normalized header names (lower case, without spaces,
+// underscores or dashes) to the column they hold.
+var csvColumnAliases = map[string]csvColumn{
+	"conversationid":    csvColumnConversation,
+	"threadid":          csvColumnConversation,
+	"chatid":            csvColumnConversation,
+	"sessionid":         csvColumnConversation,
+	"title":             csvColumnTitle,
+	"conversation":      csvColumnTitle,
+	"thread":            csvColumnTitle,
+	"conversationtitle": csvColumnTitle,
+	"conversationname":  csvColumnTitle,
+	"threadtitle":       csvColumnTitle,
+	"time":              csvColumnTimestamp,
+	"timestamp":         csvColumnTimestamp,
+	"createdat":         csvColumnTimestamp,
+	"createtime":        csvColumnTimestamp,
+	"date":              csvColumnTimestamp,
+	"datetime":          csvColumnTimestamp,
+	"author":            csvColumnSpeaker,
+	"role":              csvColumnSpeaker,
+	"speaker":           csvColumnSpeaker,
+	"sender":            csvColumnSpeaker,
+	"message":           csvColumnMessage,
+	"text":              csvColumnMessage,
+	"content":           csvColumnMessage,
+	"query":             csvColumnQuery,
+	"question":          csvColumnQuery,
+	"prompt":            csvColumnQuery,
+	"answer":            csvColumnAnswer,
+	"response":          csvColumnAnswer,
+	"reply":             csvColumnAnswer,
+	"provider":          csvColumnProvider,
+}
+
+var csvDelimiters = []rune{',', ';', '\t'}
+
+// looksLikeCSV reports whether the first line of a document has a delimiter that
+// could separate CSV columns.
+func looksLikeCSV(reader *bufio.Reader) bool {
+	header, _ := reader.Peek(sniffLength)
+	firstLine, _, _ := bytes.Cut(header, []byte("\n"))
+	return detectCSVDelimiter(firstLine) != 0
+}
+
+func detectCSVDelimiter(line []byte) rune {
+	bestDelimiter := rune(0)
+	bestCount := 0
+	for _, delimiter := range csvDelimiters {
+		if count := bytes.Count(line, []byte(string(delimiter))); count > bestCount {
+			bestDelimiter = delimiter
+			bestCount = count
+		}
+	}
+
+	return bestDelimiter
+}
+
+type csvConversation struct {
+	conversation Conversation
+	oldest       string
+}
+
+func parseConversationsCSV(input *bufio.Reader, options ParseOptions) (ParseResult, error) {
+	header, _ := input.Peek(sniffLength)
+	firstLine, _, _ := bytes.Cut(header, []byte("\n"))
+
+	reader := csv.NewReader(input)
+	reader.Comma = detectCSVDelimiter(firstLine)
+	reader.FieldsPerRecord = -1
+
+	headerRow, err := reader.Read()
+	if err != nil {
+		return ParseResult{}, fmt.Errorf("read csv header: %w", err)
+	}
+
+	columns := mapCSVColumns(headerRow)
+	_, hasSpeaker := columns[csvColumnSpeaker]
+	_, hasMessage := columns[csvColumnMessage]
+	_, hasQuery := columns[csvColumnQuery]
+	exchangeLayout := hasQuery && !(hasSpeaker && hasMessage)
+	if !exchangeLayout && !hasMessage {
+		return ParseResult{}, fmt.Errorf("csv header has no message or query column: %s", strings.Join(headerRow, ", "))
+	}
+
+	defaultProvider := ProviderCopilot
+	if exchangeLayout {
+		defaultProvider = ProviderPerplexity
+	}
+
+	result := ParseResult{Conversations: []Conversation{}, Warnings: []ParseWarning{}}
+	conversationsByKey := make(map[string]*csvConversation)
+	conversationKeys := make([]string, 0, 16)
+	for rowIndex := 0; ; rowIndex++ {
+		row, readErr := reader.Read()
+		if errors.Is(readErr, io.EOF) {
+			break
+		}
+		if readErr != nil {
+			if !options.Lenient {
+				return ParseResult{}, fmt.Errorf("read csv row %d: %w", rowIndex, readErr)
+			}
+			result.Warnings = append(result.Warnings, ParseWarning{Index: rowIndex, Message: readErr.Error(), Err: readErr})
+			continue
+		}
+
+		cell := func(column csvColumn) string {
+			columnIndex, exists := columns[column]
+			if !exists || columnIndex >= len(row) {
+				return ""
+			}
+			return strings.TrimSpace(row[columnIndex])
+		}
+
+		conversationID := cell(csvColumnConversation)
+		key := conversationID
+		if key == "" {
+			key = cell(csvColumnTitle)
+		}
+		if key == "" && exchangeLayout {
+			// Without a thread column every exchange stands on its own.
+			key = fmt.Sprintf("row-%d", rowIndex)
+		}
+
+		grouped, exists := conversationsByKey[key]
+		if !exists {
+			provider := strings.ToLower(cell(csvColumnProvider))
+			if provider == "" {
+				provider = defaultProvider
+			}
+			if conversationID == "" {
+				conversationID = csvConversationID(provider, key)
+			}
+			grouped = &csvConversation{conversation: Conversation{
+				Provider: provider,
+				ID:       conversationID,
+				Name:     cell(csvColumnTitle),
+			}}
+			conversationsByKey[key] = grouped
+			conversationKeys = append(conversationKeys, key)
+		}
+
+		timestamp := normalizeTimestamp(cell(csvColumnTimestamp))
+		grouped.oldest = olderTimestamp(grouped.oldest, timestamp)
+		if exchangeLayout {
+			grouped.conversation.Messages = appendExchange(grouped.conversation.Messages, cell(csvColumnQuery), cell(csvColumnAnswer), timestamp)
+			continue
+		}
+
+		text := cell(csvColumnMessage)
+		if text == "" {
+			continue
+		}
+		grouped.conversation.Messages = append(grouped.conversation.Messages, Message{
+			Speaker:   normalizeAssistantSpeaker(cell(csvColumnSpeaker)),
+			Text:      text,
+			Timestamp: timestamp,
+		})
+	}
+
+	for _, key := range conversationKeys {
+		grouped := conversationsByKey[key]
+		grouped.conversation.Name = resolveConversationName(grouped.conversation.Name, grouped.conversation.Messages)
+		grouped.conversation.CreatedAt = grouped.oldest
+		result.Conversations = appendVisibleConversations(result.Conversations, []Conversation{grouped.conversation})
+	}
+
+	return result, nil
+}
+
+func mapCSVColumns(headerRow []string) map[csvColumn]int {
+	normalizer := strings.NewReplacer(" ", "", "_", "", "-", "")
+	columns := make(map[csvColumn]int, len(headerRow))
+	for index, name := range headerRow {
+		normalizedName := normalizer.Replace(strings.ToLower(strings.TrimSpace(name)))
+		column, exists := csvColumnAliases[normalizedName]
+		if !exists {
+			continue
+		}
+		if _, alreadyMapped := columns[column]; !alreadyMapped {
+			columns[column] = index
+		}
+	}
+
+	return columns
+}
+
+// csvConversationID derives a stable identifier for CSV conversations that have no
+// ID column, since spreadsheet exports often group rows by title instead.
+func csvConversationID(provider string, key string) string {
+	digest := sha256.Sum256([]byte(provider + "\x00" + key))
+	return provider + "-" + hex.EncodeToString(digest[:8])
+}
@@ -0,0 +1,85 @@
+package models
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseConversationsCSV(t *testing.T) {
+	tests := []struct {
+		name            string
+		input           string
+		options         ParseOptions
+		wantEntries     []ConversationEntry
+		wantErrContains string
+	}{
+		{
+			name:  "matches header aliases case-insensitively and honours a provider column",
+			input: "Session ID\tRole\tContent\tCreated At\tProvider\ns-1\tHuman\thello\t2025-01-01 10:00\tInternalBot\ns-1\tAI\thi there\t2025-01-01 10:01\tInternalBot\n",
+			wantEntries: []ConversationEntry{
+				{Provider: "internalbot", ConversationID: "s-1", ConversationName: "hello", ConversationCreatedAt: "2025-01-01T10:00:00Z", Speaker: "user", Message: "hello", MessageTimestamp: "2025-01-01T10:00:00Z", Kind: MessageKindPrompt, TokenCount: 1},
+				{Provider: "internalbot", ConversationID: "s-1", ConversationName: "hello", ConversationCreatedAt: "2025-01-01T10:00:00Z", Speaker: "assistant", Message: "hi there", MessageTimestamp: "2025-01-01T10:01:00Z", Kind: MessageKindAnswer, MessageIndex: 1, TokenCount: 2},
+			},
+		},
+		{
+			name:  "keeps unparseable timestamps as they are",
+			input: "conversation_id,author,message,time\nc-1,user,hello,yesterday\n",
+			wantEntries: []ConversationEntry{
+				copilotEntry("c-1", "hello", "yesterday", "user", "hello", "yesterday", MessageKindPrompt, 0, 1),
+			},
+		},
+		{
+			name:            "rejects headers without a message or query column",
+			input:           "name,email\nAda,ada@example.com\n",
+			wantErrContains: "csv header has no message or query column",
+		},
+		{
+			name:            "strict mode fails on malformed rows",
+			input:           "conversation_id,author,message\nc-1,user,a \"bare\" quote\nc-1,assistant,fine\n",
+			wantErrContains: "read csv row 0",
+		},
+		{
+			name:    "lenient mode skips malformed rows",
+			input:   "conversation_id,author,message\nc-1,user,a \"bare\" quote\nc-1,assistant,fine\n",
+			options: ParseOptions{Lenient: true},
+			wantEntries: []ConversationEntry{
+				copilotEntry("c-1", "fine", "", "assistant", "fine", "", MessageKindAnswer, 0, 1),
+			},
+		},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			result, err := ParseConversations(strings.NewReader(testCase.input), testCase.options)
+			if testCase.wantErrContains != "" {
+				assertErrorContains(t, err, testCase.wantErrContains)
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseConversations returned error: %v", err)
+			}
+
+			assertConversationEntries(t, result.Entries(), testCase.wantEntries)
+		})
+	}
+}
+
+func TestNormalizeTimestamp(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{input: "", want: ""},
+		{input: " 2025-05-02T14:30:00+02:00 ", want: "2025-05-02T12:30:00Z"},
+		{input: "2025-05-02 14:30:00", want: "2025-05-02T14:30:00Z"},
+		{input: "5/2/2025 2:30:00 PM", want: "2025-05-02T14:30:00Z"},
+		{input: "2025-05-02", want: "2025-05-02T00:00:00Z"},
+		{input: "last week", want: "last week"},
+	}
+
+	for _, testCase := range tests {
+		if got := normalizeTimestamp(testCase.input); got != testCase.want {
+			t.Fatalf("normalizeTimestamp(%q) = %q, want %q", testCase.input, got, testCase.want)
+		}
+	}
+}
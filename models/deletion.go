@@ -0,0 +1,414 @@
+package models
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Deletion plan export formats.
+const (
+	DeletionPlanFormatMarkdown = "markdown"
+	DeletionPlanFormatCSV      = "csv"
+)
+
+// DeletionCriteria selects the conversations to add to a deletion plan. Conversations
+// listed in Conversations are always selected, matched on provider and ID. The
+// remaining fields form a filter whose set fields must all match; a criteria without
+// them selects nothing else.
+type DeletionCriteria struct {
+	Conversations []CollectionConversation `json:"conversations,omitempty"`
+	// Query matches the conversation name or any message text, ignoring case.
+	Query    string `json:"query,omitempty"`
+	Provider string `json:"provider,omitempty"`
+	// OlderThanDays matches conversations created more than that many days ago.
+	OlderThanDays int `json:"olderThanDays,omitempty"`
+	// MinSeverity matches conversations with a sensitive data finding at least this
+	// severe, see ScanSensitiveData.
+	MinSeverity string `json:"minSeverity,omitempty"`
+}
+
+// DeletionPlan is a checklist of conversations to delete at their provider.
+type DeletionPlan struct {
+	UpdatedAt string         `json:"updatedAt,omitempty"`
+	Items     []DeletionItem `json:"items"`
+}
+
+// DeletionItem is one conversation of a deletion plan. URL opens the conversation at
+// its provider and is blank for providers without conversation links.
+type DeletionItem struct {
+	Provider         string   `json:"provider,omitempty"`
+	ConversationID   string   `json:"conversationId"`
+	ConversationName string   `json:"conversationName"`
+	CreatedAt        string   `json:"createdAt"`
+	URL              string   `json:"url,omitempty"`
+	Reasons          []string `json:"reasons"`
+	Done             bool     `json:"done"`
+	DoneAt           string   `json:"doneAt,omitempty"`
+}
+
+func (item DeletionItem) key() string {
+	return item.Provider + "\x00" + item.ConversationID
+}
+
+// ConversationURL returns the provider's web address of a conversation, or "" when
+// the provider has none.
+func ConversationURL(provider string, conversationID string) string {
+	trimmedID := strings.TrimSpace(conversationID)
+	if trimmedID == "" {
+		return ""
+	}
+
+	switch provider {
+	case ProviderChatGPT:
+		return "https://chatgpt.com/c/" + url.PathEscape(trimmedID)
+	case ProviderClaude:
+		return "https://claude.ai/chat/" + url.PathEscape(trimmedID)
+	default:
+		return ""
+	}
+}
+
+// SelectForDeletion returns a plan item for every conversation the criteria select,
+// with the reasons it was selected. now anchors OlderThanDays. It returns an error for
+// a MinSeverity that is not a known severity.
+func SelectForDeletion(conversations []Conversation, criteria DeletionCriteria, now time.Time) ([]DeletionItem, error) {
+	minSeverity := strings.ToLower(strings.TrimSpace(criteria.MinSeverity))
+	if _, known := severityRanks[minSeverity]; minSeverity != "" && !known {
+		return nil, fmt.Errorf("unknown severity %q: use %q, %q, %q or %q", criteria.MinSeverity, SeverityCritical, SeverityHigh, SeverityMedium, SeverityLow)
+	}
+
+	// IDs are only unique per provider, so a marked ID selects no other provider's
+	// conversation.
+	marked := make(map[string]struct{}, len(criteria.Conversations))
+	for _, conversation := range criteria.Conversations {
+		marked[strings.TrimSpace(conversation.Provider)+"\x00"+strings.TrimSpace(conversation.ConversationID)] = struct{}{}
+	}
+
+	query := strings.ToLower(strings.TrimSpace(criteria.Query))
+	provider := strings.TrimSpace(criteria.Provider)
+	hasFilter := query != "" || provider != "" || criteria.OlderThanDays > 0 || minSeverity != ""
+
+	var conversationSeverities map[string]string
+	if minSeverity != "" {
+		conversationSeverities = mostSevereFindings(ScanSensitiveData(conversations))
+	}
+	cutoff := now.AddDate(0, 0, -criteria.OlderThanDays)
+
+	items := make([]DeletionItem, 0, len(criteria.Conversations))
+	for _, conversation := range conversations {
+		reasons := make([]string, 0, 2)
+		if _, isMarked := marked[conversation.Provider+"\x00"+conversation.ID]; isMarked {
+			reasons = append(reasons, "marked")
+		}
+
+		if hasFilter {
+			filterReasons, matches := deletionFilterReasons(conversation, query, provider, criteria.OlderThanDays, cutoff, minSeverity, conversationSeverities)
+			if matches {
+				reasons = append(reasons, filterReasons...)
+			}
+		}
+		if len(reasons) == 0 {
+			continue
+		}
+
+		items = append(items, DeletionItem{
+			Provider:         conversation.Provider,
+			ConversationID:   conversation.ID,
+			ConversationName: conversation.Name,
+			CreatedAt:        conversation.CreatedAt,
+			URL:              ConversationURL(conversation.Provider, conversation.ID),
+			Reasons:          reasons,
+		})
+	}
+
+	return items, nil
+}
+
+func deletionFilterReasons(
+	conversation Conversation,
+	query string,
+	provider string,
+	olderThanDays int,
+	cutoff time.Time,
+	minSeverity string,
+	conversationSeverities map[string]string,
+) ([]string, bool) {
+	reasons := make([]string, 0, 3)
+	if provider != "" {
+		if conversation.Provider != provider {
+			return nil, false
+		}
+		reasons = append(reasons, "provider "+provider)
+	}
+	if query != "" {
+		if !conversationContains(conversation, query) {
+			return nil, false
+		}
+		reasons = append(reasons, fmt.Sprintf("matches %q", query))
+	}
+	if olderThanDays > 0 {
+		createdAt, ok := parseTimestamp(conversation.CreatedAt)
+		if !ok || !createdAt.Before(cutoff) {
+			return nil, false
+		}
+		reasons = append(reasons, fmt.Sprintf("older than %d days", olderThanDays))
+	}
+	if minSeverity != "" {
+		severity, found := conversationSeverities[conversation.Provider+"\x00"+conversation.ID]
+		if !found || severityRanks[severity] > severityRanks[minSeverity] {
+			return nil, false
+		}
+		reasons = append(reasons, "sensitive data ("+severity+")")
+	}
+
+	return reasons, true
+}
+
+func conversationContains(conversation Conversation, query string) bool {
+	if strings.Contains(strings.ToLower(conversation.Name), query) {
+		return true
+	}
+	for _, message := range conversation.Messages {
+		if strings.Contains(strings.ToLower(message.Text), query) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// mostSevereFindings maps each conversation with findings to its worst severity.
+func mostSevereFindings(findings []SensitiveFinding) map[string]string {
+	severities := make(map[string]string, len(findings))
+	for _, finding := range findings {
+		key := finding.Provider + "\x00" + finding.ConversationID
+		if current, found := severities[key]; !found || severityRanks[finding.Severity] < severityRanks[current] {
+			severities[key] = finding.Severity
+		}
+	}
+
+	return severities
+}
+
+// AddToDeletionPlan merges items into plan. Conversations already planned keep their
+// done state and gain any new reasons.
+func AddToDeletionPlan(plan DeletionPlan, items []DeletionItem) DeletionPlan {
+	merged := append([]DeletionItem(nil), plan.Items...)
+	indexes := make(map[string]int, len(merged))
+	for index, item := range merged {
+		indexes[item.key()] = index
+	}
+
+	for _, item := range items {
+		index, exists := indexes[item.key()]
+		if !exists {
+			indexes[item.key()] = len(merged)
+			merged = append(merged, item)
+			continue
+		}
+
+		existing := &merged[index]
+		existing.Reasons = appendMissingStrings(existing.Reasons, item.Reasons)
+	}
+
+	plan.Items = merged
+	return plan
+}
+
+func appendMissingStrings(values []string, additions []string) []string {
+	merged := append([]string(nil), values...)
+	for _, addition := range additions {
+		found := false
+		for _, value := range merged {
+			if value == addition {
+				found = true
+				break
+			}
+		}
+		if !found {
+			merged = append(merged, addition)
+		}
+	}
+
+	return merged
+}
+
+// SetDeletionItemDone records whether a planned conversation was deleted. It reports
+// false when the conversation is not in the plan.
+func SetDeletionItemDone(plan DeletionPlan, provider string, conversationID string, done bool, now time.Time) (DeletionPlan, bool) {
+	items := append([]DeletionItem(nil), plan.Items...)
+	for index := range items {
+		item := &items[index]
+		if item.Provider != provider || item.ConversationID != conversationID {
+			continue
+		}
+
+		item.Done = done
+		item.DoneAt = ""
+		if done {
+			item.DoneAt = now.UTC().Format(time.RFC3339)
+		}
+		plan.Items = items
+		return plan, true
+	}
+
+	return plan, false
+}
+
+// RemoveFromDeletionPlan drops a conversation from the plan.
+func RemoveFromDeletionPlan(plan DeletionPlan, provider string, conversationID string) DeletionPlan {
+	items := make([]DeletionItem, 0, len(plan.Items))
+	for _, item := range plan.Items {
+		if item.Provider == provider && item.ConversationID == conversationID {
+			continue
+		}
+		items = append(items, item)
+	}
+
+	plan.Items = items
+	return plan
+}
+
+// DeletionPlanFileName is the file of the local store holding the deletion plan.
+const DeletionPlanFileName = "deletion-plan.json"
+
+// LoadDeletionPlan reads the plan saved in store. A missing file is an empty plan.
+func LoadDeletionPlan(store *LocalStore) (DeletionPlan, error) {
+	data, err := store.ReadFile(DeletionPlanFileName)
+	if errors.Is(err, fs.ErrNotExist) {
+		return DeletionPlan{Items: []DeletionItem{}}, nil
+	}
+	if err != nil {
+		return DeletionPlan{}, fmt.Errorf("read deletion plan: %w", err)
+	}
+
+	var plan DeletionPlan
+	if err := json.Unmarshal(data, &plan); err != nil {
+		return DeletionPlan{}, fmt.Errorf("decode deletion plan: %w", err)
+	}
+	if plan.Items == nil {
+		plan.Items = []DeletionItem{}
+	}
+
+	return plan, nil
+}
+
+// SaveDeletionPlan writes plan to store.
+func SaveDeletionPlan(store *LocalStore, plan DeletionPlan) error {
+	data, err := json.MarshalIndent(plan, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encode deletion plan: %w", err)
+	}
+
+	return store.WriteFile(DeletionPlanFileName, data)
+}
+
+// WriteDeletionPlan exports plan as a Markdown checklist or as CSV.
+func WriteDeletionPlan(output io.Writer, plan DeletionPlan, format string) error {
+	switch format {
+	case DeletionPlanFormatMarkdown:
+		return writeDeletionPlanMarkdown(output, plan)
+	case DeletionPlanFormatCSV:
+		return writeDeletionPlanCSV(output, plan)
+	default:
+		return fmt.Errorf("unknown deletion plan format %q", format)
+	}
+}
+
+func writeDeletionPlanMarkdown(output io.Writer, plan DeletionPlan) error {
+	var document strings.Builder
+	document.WriteString("# Deletion plan\n\n")
+	doneCount := 0
+	for _, item := range plan.Items {
+		if item.Done {
+			doneCount++
+		}
+	}
+	fmt.Fprintf(&document, "%d of %d conversations deleted.\n\n", doneCount, len(plan.Items))
+
+	for _, item := range sortedDeletionItems(plan.Items) {
+		checkbox := "[ ]"
+		if item.Done {
+			checkbox = "[x]"
+		}
+		name := escapeMarkdownLinkText(firstNonBlank(item.ConversationName, item.ConversationID))
+		if item.URL != "" {
+			name = "[" + name + "](" + item.URL + ")"
+		}
+
+		details := []string{firstNonBlank(item.Provider, "unknown provider")}
+		if item.CreatedAt != "" {
+			details = append(details, "created "+item.CreatedAt)
+		}
+		if len(item.Reasons) > 0 {
+			details = append(details, strings.Join(item.Reasons, ", "))
+		}
+		fmt.Fprintf(&document, "- %s %s (%s)\n", checkbox, name, strings.Join(details, "; "))
+	}
+
+	if _, err := io.WriteString(output, document.String()); err != nil {
+		return fmt.Errorf("write deletion plan: %w", err)
+	}
+
+	return nil
+}
+
+var markdownLinkTextEscaper = strings.NewReplacer(`\`, `\\`, "[", `\[`, "]", `\]`, "\n", " ")
+
+func escapeMarkdownLinkText(text string) string {
+	return markdownLinkTextEscaper.Replace(text)
+}
+
+func writeDeletionPlanCSV(output io.Writer, plan DeletionPlan) error {
+	writer := csv.NewWriter(output)
+	rows := [][]string{{"provider", "conversation_id", "conversation_name", "created_at", "url", "reasons", "done", "done_at"}}
+	for _, item := range sortedDeletionItems(plan.Items) {
+		rows = append(rows, []string{
+			escapeCSVFormula(item.Provider),
+			escapeCSVFormula(item.ConversationID),
+			escapeCSVFormula(item.ConversationName),
+			escapeCSVFormula(item.CreatedAt),
+			escapeCSVFormula(item.URL),
+			escapeCSVFormula(strings.Join(item.Reasons, "; ")),
+			strconv.FormatBool(item.Done),
+			escapeCSVFormula(item.DoneAt),
+		})
+	}
+
+	if err := writer.WriteAll(rows); err != nil {
+		return fmt.Errorf("write deletion plan: %w", err)
+	}
+
+	return nil
+}
+
+// escapeCSVFormula prefixes a cell that a spreadsheet would run as a formula with a
+// quote, so a conversation named "=HYPERLINK(...)" opens as text. A leading tab or
+// carriage return also starts a formula in some spreadsheets.
+func escapeCSVFormula(value string) string {
+	if value != "" && strings.ContainsRune("=+-@\t\r", rune(value[0])) {
+		return "'" + value
+	}
+
+	return value
+}
+
+// sortedDeletionItems lists pending conversations before deleted ones, keeping the
+// order they were planned in.
+func sortedDeletionItems(items []DeletionItem) []DeletionItem {
+	sorted := append([]DeletionItem(nil), items...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return !sorted[i].Done && sorted[j].Done
+	})
+
+	return sorted
+}
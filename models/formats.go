@@ -0,0 +1,102 @@
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+)
+
+// Format normalizes the conversation records of one chat provider. Implementations
+// are registered with RegisterFormat and consulted for every record in an export.
+type Format interface {
+	// Detect reports whether a decoded conversation object belongs to this format.
+	Detect(fields map[string]json.RawMessage) bool
+	// Parse normalizes a single conversation object. A record may expand to several
+	// conversations or to none (for example, activity records that carry no exchange).
+	Parse(raw json.RawMessage) ([]Conversation, error)
+}
+
+// builtinFormats are consulted in order and the first format that detects a record
+// parses it. Claude records have no field unique to them beyond chat_messages, so
+// Claude also serves as the fallback for records no format claims.
+var builtinFormats = []Format{
+	chatGPTFormat{},
+	geminiFormat{},
+	copilotFormat{},
+	perplexityFormat{},
+	anthropicAPIFormat{},
+	openAIAPIFormat{},
+	claudeFormat{},
+}
+
+var (
+	formatsMu sync.RWMutex
+	// registeredFormats are the formats added with RegisterFormat, in registration order.
+	registeredFormats []Format
+)
+
+// RegisterFormat adds a format after the ones already registered. Registered formats
+// are consulted before the built-in ones, several of which claim any record with a
+// generic key such as messages, so a custom format sees every record first.
+func RegisterFormat(format Format) {
+	if format == nil {
+		panic("models: RegisterFormat called with nil format")
+	}
+
+	formatsMu.Lock()
+	defer formatsMu.Unlock()
+	registeredFormats = append(registeredFormats, format)
+}
+
+// Formats returns the formats in the order they are consulted: the registered ones,
+// then the built-in ones.
+func Formats() []Format {
+	formatsMu.RLock()
+	defer formatsMu.RUnlock()
+
+	formats := make([]Format, 0, len(registeredFormats)+len(builtinFormats))
+	formats = append(formats, registeredFormats...)
+	return append(formats, builtinFormats...)
+}
+
+func detectFormat(fields map[string]json.RawMessage) Format {
+	for _, format := range Formats() {
+		if format.Detect(fields) {
+			return format
+		}
+	}
+
+	return claudeFormat{}
+}
+
+type claudeFormat struct{}
+
+func (claudeFormat) Detect(fields map[string]json.RawMessage) bool {
+	_, hasChatMessages := fields["chat_messages"]
+	return hasChatMessages
+}
+
+func (claudeFormat) Parse(rawConversationJSON json.RawMessage) ([]Conversation, error) {
+	var conversation rawConversation
+	if err := json.Unmarshal(rawConversationJSON, &conversation); err != nil {
+		return nil, fmt.Errorf("decode claude conversation: %w", err)
+	}
+
+	return []Conversation{parseClaudeConversation(conversation)}, nil
+}
+
+type chatGPTFormat struct{}
+
+func (chatGPTFormat) Detect(fields map[string]json.RawMessage) bool {
+	_, hasMapping := fields["mapping"]
+	return hasMapping
+}
+
+func (chatGPTFormat) Parse(rawConversationJSON json.RawMessage) ([]Conversation, error) {
+	var conversation rawChatGPTConversation
+	if err := json.Unmarshal(rawConversationJSON, &conversation); err != nil {
+		return nil, fmt.Errorf("decode chatgpt conversation: %w", err)
+	}
+
+	return []Conversation{parseChatGPTConversation(conversation)}, nil
+}
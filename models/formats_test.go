@@ -0,0 +1,164 @@
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+type botLogFormat struct{}
+
+func (botLogFormat) Detect(fields map[string]json.RawMessage) bool {
+	_, hasTurns := fields["bot_turns"]
+	return hasTurns
+}
+
+func (botLogFormat) Parse(raw json.RawMessage) ([]Conversation, error) {
+	var log struct {
+		Session string   `json:"session"`
+		Turns   []string `json:"bot_turns"`
+	}
+	if err := json.Unmarshal(raw, &log); err != nil {
+		return nil, fmt.Errorf("decode bot log: %w", err)
+	}
+
+	conversation := Conversation{Provider: "bot", ID: log.Session, Name: "Bot " + log.Session}
+	for index, turn := range log.Turns {
+		speaker := "user"
+		if index%2 == 1 {
+			speaker = "assistant"
+		}
+		conversation.Messages = append(conversation.Messages, Message{Speaker: speaker, Text: turn})
+	}
+
+	return []Conversation{conversation}, nil
+}
+
+// withRegisteredFormat registers format for the duration of the test.
+func withRegisteredFormat(t *testing.T, format Format) {
+	t.Helper()
+
+	formatsMu.Lock()
+	previousFormats := registeredFormats
+	registeredFormats = append(append([]Format{}, previousFormats...), format)
+	formatsMu.Unlock()
+
+	t.Cleanup(func() {
+		formatsMu.Lock()
+		registeredFormats = previousFormats
+		formatsMu.Unlock()
+	})
+}
+
+func TestRegisterFormatParsesCustomRecords(t *testing.T) {
+	withRegisteredFormat(t, botLogFormat{})
+
+	input := `[
+		{ "session": "s-1", "bot_turns": ["hi", "hello there"] },
+		{ "session": "s-2", "bot_turns": [] },
+		{ "uuid": "claude-1", "name": "Claude", "chat_messages": [{ "sender": "human", "text": "still works" }] }
+	]`
+
+	result, err := ParseConversations(strings.NewReader(input), ParseOptions{})
+	if err != nil {
+		t.Fatalf("ParseConversations returned error: %v", err)
+	}
+
+	if len(result.Conversations) != 2 {
+		t.Fatalf("expected empty conversations to be dropped, got %+v", result.Conversations)
+	}
+
+	assertConversationEntries(t, result.Entries(), []ConversationEntry{
+		{Provider: "bot", ConversationID: "s-1", ConversationName: "Bot s-1", Speaker: "user", Message: "hi", Kind: MessageKindPrompt, TokenCount: 1},
+		{Provider: "bot", ConversationID: "s-1", ConversationName: "Bot s-1", Speaker: "assistant", Message: "hello there", Kind: MessageKindAnswer, MessageIndex: 1, TokenCount: 2},
+		{Provider: ProviderClaude, ConversationID: "claude-1", ConversationName: "Claude", Speaker: "human", Message: "still works", Kind: MessageKindPrompt, TokenCount: 3},
+	})
+}
+
+func TestRegisterFormatTakesPriorityOverBuiltins(t *testing.T) {
+	builtinCount := len(Formats())
+	withRegisteredFormat(t, botLogFormat{})
+
+	formats := Formats()
+	if len(formats) != builtinCount+1 {
+		t.Fatalf("expected %d formats, got %d", builtinCount+1, len(formats))
+	}
+	if _, ok := formats[0].(botLogFormat); !ok {
+		t.Fatalf("expected custom format first, got %T", formats[0])
+	}
+	if _, ok := formats[1].(chatGPTFormat); !ok {
+		t.Fatalf("expected chatgpt format after the custom ones, got %T", formats[1])
+	}
+
+	formats[0] = nil
+	if Formats()[0] == nil {
+		t.Fatal("expected Formats to return a copy")
+	}
+}
+
+// chatbotLogFormat reads an in-house chatbot log whose records look like OpenAI API
+// logs, Copilot conversations and Perplexity threads at once.
+type chatbotLogFormat struct{}
+
+func (chatbotLogFormat) Detect(fields map[string]json.RawMessage) bool {
+	_, hasBot := fields["bot"]
+	return hasBot
+}
+
+func (chatbotLogFormat) Parse(raw json.RawMessage) ([]Conversation, error) {
+	var log struct {
+		ConversationID string `json:"conversationId"`
+		Messages       []struct {
+			From string `json:"from"`
+			Body string `json:"body"`
+		} `json:"messages"`
+	}
+	if err := json.Unmarshal(raw, &log); err != nil {
+		return nil, fmt.Errorf("decode chatbot log: %w", err)
+	}
+
+	conversation := Conversation{Provider: "helpdesk", ID: log.ConversationID, Name: "Helpdesk"}
+	for _, message := range log.Messages {
+		conversation.Messages = append(conversation.Messages, Message{Speaker: message.From, Text: message.Body})
+	}
+
+	return []Conversation{conversation}, nil
+}
+
+func TestRegisterFormatClaimsRecordsWithGenericKeys(t *testing.T) {
+	withRegisteredFormat(t, chatbotLogFormat{})
+
+	input := `[
+		{ "bot": "helpdesk", "conversationId": "h-1", "messages": [{ "from": "customer", "body": "my order is late" }], "entries": [] },
+		{ "custom_id": "api-1", "messages": [{ "role": "user", "content": "still an api log" }] }
+	]`
+
+	result, err := ParseConversations(strings.NewReader(input), ParseOptions{})
+	if err != nil {
+		t.Fatalf("ParseConversations returned error: %v", err)
+	}
+
+	assertConversationEntries(t, result.Entries(), []ConversationEntry{
+		{Provider: "helpdesk", ConversationID: "h-1", ConversationName: "Helpdesk", Speaker: "customer", Message: "my order is late", TokenCount: 4},
+		{Provider: ProviderOpenAI, ConversationID: "api-1", ConversationName: "still an api log", Speaker: "user", Message: "still an api log", Kind: MessageKindPrompt, TokenCount: 4},
+	})
+}
+
+func TestConversationEntries(t *testing.T) {
+	conversation := Conversation{
+		Provider:  ProviderChatGPT,
+		ID:        "c-1",
+		Name:      "Name",
+		CreatedAt: "2026-01-01T00:00:00Z",
+		Messages: []Message{
+			{Speaker: "user", Text: "q", Timestamp: "2026-01-01T00:00:01Z"},
+			{Speaker: "assistant", Text: "a", Timestamp: "2026-01-01T00:00:02Z"},
+		},
+	}
+
+	assertConversationEntries(t, FlattenConversations([]Conversation{conversation, {ID: "empty"}}), []ConversationEntry{
+		{Provider: ProviderChatGPT, ConversationID: "c-1", ConversationName: "Name", ConversationCreatedAt: "2026-01-01T00:00:00Z", Speaker: "user", Message: "q", MessageTimestamp: "2026-01-01T00:00:01Z"},
+		{Provider: ProviderChatGPT, ConversationID: "c-1", ConversationName: "Name", ConversationCreatedAt: "2026-01-01T00:00:00Z", Speaker: "assistant", Message: "a", MessageTimestamp: "2026-01-01T00:00:02Z", MessageIndex: 1},
+	})
+}
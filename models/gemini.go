@@ -0,0 +1,352 @@
+package models
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+	"time"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// Google Takeout exports Gemini history as "My Activity" records rather than
+// conversations: every prompt is its own record with the response as sanitized HTML
+// and no thread identifier. Each prompted record becomes a two-message conversation.
+
+const geminiPromptPrefix = "Prompted"
+
+// geminiActivityHeaders are the record headers used by Gemini and its predecessor Bard.
+var geminiActivityHeaders = []string{"Gemini Apps", "Bard"}
+
+// geminiHTMLTimeLayouts covers the locale-dependent timestamps in My Activity.html,
+// without the trailing zone, which parseGeminiHTMLTime reads itself.
+var geminiHTMLTimeLayouts = []string{
+	"Jan 2, 2006, 3:04:05 PM",
+	"2 Jan 2006, 15:04:05",
+}
+
+// geminiZoneOffsets are the UTC offsets, in hours, of the zone abbreviations Google
+// prints in My Activity.html. time.Parse would read any other abbreviation as UTC, so
+// timestamps in an abbreviation missing here are left unparsed. Ambiguous
+// abbreviations take their North American meaning, as CST does in Google's en-US locale.
+var geminiZoneOffsets = map[string]float64{
+	"UTC": 0, "GMT": 0, "WET": 0, "WEST": 1, "BST": 1,
+	"CET": 1, "CEST": 2, "EET": 2, "EEST": 3, "MSK": 3,
+	"EST": -5, "EDT": -4, "CST": -6, "CDT": -5, "MST": -7, "MDT": -6,
+	"PST": -8, "PDT": -7, "AKST": -9, "AKDT": -8, "HST": -10,
+	"JST": 9, "KST": 9, "HKT": 8, "SGT": 8, "AWST": 8,
+	"ACST": 9.5, "ACDT": 10.5, "AEST": 10, "AEDT": 11, "NZST": 12, "NZDT": 13,
+}
+
+type rawGeminiActivity struct {
+	Header       string              `json:"header"`
+	Title        string              `json:"title"`
+	Time         string              `json:"time"`
+	Products     []string            `json:"products"`
+	SafeHTMLItem []rawGeminiSafeHTML `json:"safeHtmlItem"`
+}
+
+type rawGeminiSafeHTML struct {
+	HTML string `json:"html"`
+}
+
+type geminiFormat struct{}
+
+func (geminiFormat) Detect(fields map[string]json.RawMessage) bool {
+	var header string
+	if err := json.Unmarshal(fields["header"], &header); err == nil && isGeminiActivityHeader(header) {
+		return true
+	}
+
+	var products []string
+	if err := json.Unmarshal(fields["products"], &products); err == nil {
+		for _, product := range products {
+			if isGeminiActivityHeader(product) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+func (geminiFormat) Parse(rawConversationJSON json.RawMessage) ([]Conversation, error) {
+	var activity rawGeminiActivity
+	if err := json.Unmarshal(rawConversationJSON, &activity); err != nil {
+		return nil, fmt.Errorf("decode gemini activity: %w", err)
+	}
+
+	responseParts := make([]string, 0, len(activity.SafeHTMLItem))
+	for _, item := range activity.SafeHTMLItem {
+		if text := htmlToText(item.HTML); text != "" {
+			responseParts = append(responseParts, text)
+		}
+	}
+
+	return geminiActivityConversations(activity.Title, strings.TrimSpace(activity.Time), strings.Join(responseParts, "\n\n")), nil
+}
+
+func isGeminiActivityHeader(header string) bool {
+	trimmedHeader := strings.TrimSpace(header)
+	for _, geminiHeader := range geminiActivityHeaders {
+		if strings.EqualFold(trimmedHeader, geminiHeader) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// geminiActivityConversations turns one prompted activity into a user prompt and model
+// response. Activities other than prompts (feedback, extension usage, ...) carry no exchange.
+func geminiActivityConversations(title string, timestamp string, response string) []Conversation {
+	prompt, isPrompt := strings.CutPrefix(normalizeSpaces(strings.TrimSpace(title)), geminiPromptPrefix)
+	prompt = strings.TrimSpace(prompt)
+	if !isPrompt || prompt == "" {
+		return nil
+	}
+	response = strings.TrimSpace(response)
+
+	conversation := Conversation{
+		Provider:  ProviderGemini,
+		ID:        geminiConversationID(timestamp, prompt, response),
+		Name:      conversationNameFromPrompt(prompt),
+		CreatedAt: timestamp,
+		Messages:  []Message{{Speaker: "user", Text: prompt, Timestamp: timestamp}},
+	}
+	if response != "" {
+		conversation.Messages = append(conversation.Messages, Message{
+			Speaker:   "assistant",
+			Text:      response,
+			Timestamp: timestamp,
+		})
+	}
+
+	return []Conversation{conversation}
+}
+
+// geminiConversationID derives an identifier from the activity itself, since Takeout
+// has none and several prompts can share a timestamp, or have none.
+func geminiConversationID(timestamp string, prompt string, response string) string {
+	digest := sha256.Sum256([]byte(timestamp + "\x00" + prompt + "\x00" + response))
+	return ProviderGemini + "-" + hex.EncodeToString(digest[:8])
+}
+
+// parseGeminiActivityHTML reads the "My Activity.html" flavour of the Takeout export.
+func parseGeminiActivityHTML(input io.Reader) ([]Conversation, error) {
+	document, err := html.Parse(input)
+	if err != nil {
+		return nil, fmt.Errorf("decode gemini activity html: %w", err)
+	}
+
+	conversations := make([]Conversation, 0, 16)
+	for _, cell := range findElementsByClass(document, "outer-cell") {
+		headerCells := findElementsByClass(cell, "header-cell")
+		contentCells := findElementsByClass(cell, "content-cell")
+		if len(headerCells) == 0 || len(contentCells) == 0 {
+			continue
+		}
+		if !isGeminiActivityHeader(textContent(headerCells[0])) {
+			continue
+		}
+
+		title, timestamp, responseHTML := splitGeminiContentCell(contentCells[0])
+		conversations = append(conversations, geminiActivityConversations(title, parseGeminiHTMLTime(timestamp), htmlToText(responseHTML))...)
+	}
+
+	return conversations, nil
+}
+
+// splitGeminiContentCell separates "Prompted ...<br>timestamp<br>response" into its parts.
+func splitGeminiContentCell(cell *html.Node) (string, string, string) {
+	segments := []string{"", ""}
+	segmentIndex := 0
+	var response bytes.Buffer
+	for child := cell.FirstChild; child != nil; child = child.NextSibling {
+		if segmentIndex < len(segments) {
+			if child.Type == html.ElementNode && child.DataAtom == atom.Br {
+				segmentIndex++
+				continue
+			}
+			if child.Type != html.ElementNode || !htmlBlockElements[child.DataAtom] {
+				segments[segmentIndex] += textContent(child)
+				continue
+			}
+			// A block element starts the response even when the timestamp has no trailing <br>.
+			segmentIndex = len(segments)
+		}
+
+		if err := html.Render(&response, child); err != nil {
+			continue
+		}
+	}
+
+	return strings.TrimSpace(segments[0]), strings.TrimSpace(segments[1]), response.String()
+}
+
+// parseGeminiHTMLTime converts a My Activity.html timestamp to RFC 3339. Timestamps in
+// an unknown zone are returned as they are, which later stages treat as undated.
+func parseGeminiHTMLTime(value string) string {
+	normalizedValue := normalizeSpaces(strings.TrimSpace(value))
+	separator := strings.LastIndex(normalizedValue, " ")
+	if separator < 0 {
+		return normalizedValue
+	}
+	clock, zone := normalizedValue[:separator], normalizedValue[separator+1:]
+
+	var location *time.Location
+	if offset, known := geminiZoneOffsets[strings.ToUpper(zone)]; known {
+		location = time.FixedZone(zone, int(offset*60*60))
+	} else if zoneTime, err := time.Parse("Z07:00", zone); err == nil {
+		_, offset := zoneTime.Zone()
+		location = time.FixedZone(zone, offset)
+	} else {
+		return normalizedValue
+	}
+
+	for _, layout := range geminiHTMLTimeLayouts {
+		if parsedTime, err := time.ParseInLocation(layout, clock, location); err == nil {
+			return parsedTime.UTC().Format(time.RFC3339Nano)
+		}
+	}
+
+	return normalizedValue
+}
+
+// normalizeSpaces replaces the non-breaking spaces Google uses in titles and timestamps.
+func normalizeSpaces(value string) string {
+	return strings.NewReplacer("\u00a0", " ", "\u202f", " ", "\u2003", " ").Replace(value)
+}
+
+func findElementsByClass(root *html.Node, className string) []*html.Node {
+	matches := make([]*html.Node, 0, 4)
+
+	var walk func(*html.Node)
+	walk = func(node *html.Node) {
+		if node.Type == html.ElementNode && hasClass(node, className) {
+			matches = append(matches, node)
+			return
+		}
+		for child := node.FirstChild; child != nil; child = child.NextSibling {
+			walk(child)
+		}
+	}
+	walk(root)
+
+	return matches
+}
+
+func hasClass(node *html.Node, className string) bool {
+	for _, attribute := range node.Attr {
+		if attribute.Key != "class" {
+			continue
+		}
+		for _, class := range strings.Fields(attribute.Val) {
+			if class == className {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+func textContent(node *html.Node) string {
+	var builder strings.Builder
+
+	var walk func(*html.Node)
+	walk = func(current *html.Node) {
+		if current.Type == html.TextNode {
+			builder.WriteString(current.Data)
+		}
+		for child := current.FirstChild; child != nil; child = child.NextSibling {
+			walk(child)
+		}
+	}
+	walk(node)
+
+	return normalizeSpaces(builder.String())
+}
+
+// htmlBlockElements start a new line when converting HTML to plain text.
+var htmlBlockElements = map[atom.Atom]bool{
+	atom.P: true, atom.Div: true, atom.Br: true, atom.Li: true, atom.Tr: true,
+	atom.H1: true, atom.H2: true, atom.H3: true, atom.H4: true, atom.H5: true, atom.H6: true,
+	atom.Pre: true, atom.Blockquote: true, atom.Ul: true, atom.Ol: true, atom.Table: true,
+}
+
+// htmlToText flattens an HTML fragment to readable plain text, keeping line structure
+// for block elements and the exact whitespace inside <pre>.
+func htmlToText(fragment string) string {
+	if strings.TrimSpace(fragment) == "" {
+		return ""
+	}
+
+	tokenizer := html.NewTokenizer(strings.NewReader(fragment))
+	var builder strings.Builder
+	preDepth := 0
+	for {
+		tokenType := tokenizer.Next()
+		switch tokenType {
+		case html.ErrorToken:
+			return cleanExtractedText(builder.String())
+		case html.TextToken:
+			text := string(tokenizer.Text())
+			if preDepth == 0 {
+				text = htmlWhitespacePattern.ReplaceAllString(text, " ")
+				if builder.Len() == 0 || strings.HasSuffix(builder.String(), "\n") {
+					text = strings.TrimLeft(text, " ")
+				}
+			}
+			builder.WriteString(text)
+		case html.StartTagToken, html.EndTagToken, html.SelfClosingTagToken:
+			tagName, _ := tokenizer.TagName()
+			tagAtom := atom.Lookup(tagName)
+			switch {
+			case tagAtom == atom.Pre && tokenType == html.StartTagToken:
+				preDepth++
+			case tagAtom == atom.Pre && tokenType == html.EndTagToken && preDepth > 0:
+				preDepth--
+			}
+
+			if tagAtom == atom.Li {
+				if tokenType == html.StartTagToken {
+					builder.WriteString("\n- ")
+				}
+				continue
+			}
+			if htmlBlockElements[tagAtom] {
+				builder.WriteString("\n")
+			}
+		}
+	}
+}
+
+var htmlWhitespacePattern = regexp.MustCompile(`\s+`)
+
+// cleanExtractedText trims trailing spaces and collapses runs of blank lines.
+func cleanExtractedText(text string) string {
+	lines := strings.Split(normalizeSpaces(text), "\n")
+	cleanedLines := make([]string, 0, len(lines))
+	previousBlank := false
+	for _, line := range lines {
+		line = strings.TrimRight(line, " \t")
+		isBlank := strings.TrimSpace(line) == ""
+		if isBlank && previousBlank {
+			continue
+		}
+		if isBlank {
+			line = ""
+		}
+		previousBlank = isBlank
+		cleanedLines = append(cleanedLines, line)
+	}
+
+	return strings.Trim(strings.Join(cleanedLines, "\n"), "\n")
+}
@@ -0,0 +1,151 @@
+package models
+
+import (
+	"os"
+	"strings"
+	"testing"
+)
+
+const (
+	geminiActivityJSONFixturePath = "testdata/gemini_my_activity.json"
+	geminiActivityHTMLFixturePath = "testdata/gemini_my_activity.html"
+)
+
+// The conversation IDs of the Gemini fixtures, derived from each activity.
+var (
+	geminiFranceID     = geminiConversationID("2025-03-04T10:15:30.123Z", "What is the capital of France?", "The capital of France is Paris.\n\n- Population: about 2.1 million\n- River: Seine")
+	geminiHaikuID      = geminiConversationID("2023-10-01T08:00:00Z", "Write a haiku about autumn", "Crisp leaves drift and fall\nGolden light on quiet paths\nThe year exhales slow")
+	geminiHelloWorldID = geminiConversationID("2025-03-05T09:00:00Z", "Show me a Go hello world", "Here you go:\n\npackage main\n\nfunc main() {\n    println(\"hello\")\n}")
+	geminiHTMLFranceID = geminiConversationID("2025-03-04T10:15:30Z", "What is the capital of France?", "The capital of France is Paris.")
+	geminiHTMLThanksID = geminiConversationID("2025-03-05T09:00:00Z", "Thanks!", "You're welcome & good luck.")
+)
+
+func loadFixture(t *testing.T, path string) string {
+	t.Helper()
+
+	bytes, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read fixture %s: %v", path, err)
+	}
+
+	return string(bytes)
+}
+
+func TestParseGeminiActivityJSON(t *testing.T) {
+	result, err := ParseConversations(strings.NewReader(loadFixture(t, geminiActivityJSONFixturePath)), ParseOptions{})
+	if err != nil {
+		t.Fatalf("ParseConversations returned error: %v", err)
+	}
+
+	want := []ConversationEntry{
+		geminiEntry(geminiFranceID, "What is the capital of France?", "2025-03-04T10:15:30.123Z", "user", "What is the capital of France?", MessageKindPrompt, 0, 7),
+		geminiEntry(geminiFranceID, "What is the capital of France?", "2025-03-04T10:15:30.123Z", "assistant", "The capital of France is Paris.\n\n- Population: about 2.1 million\n- River: Seine", MessageKindAnswer, 1, 22),
+		geminiEntry(geminiHaikuID, "Write a haiku about autumn", "2023-10-01T08:00:00Z", "user", "Write a haiku about autumn", MessageKindPrompt, 0, 6),
+		geminiEntry(geminiHaikuID, "Write a haiku about autumn", "2023-10-01T08:00:00Z", "assistant", "Crisp leaves drift and fall\nGolden light on quiet paths\nThe year exhales slow", MessageKindAnswer, 1, 19),
+		geminiEntry(geminiHelloWorldID, "Show me a Go hello world", "2025-03-05T09:00:00Z", "user", "Show me a Go hello world", MessageKindPrompt, 0, 6),
+		geminiEntry(geminiHelloWorldID, "Show me a Go hello world", "2025-03-05T09:00:00Z", "assistant", "Here you go:\n\npackage main\n\nfunc main() {\n    println(\"hello\")\n}", MessageKindAnswer, 1, 17),
+	}
+
+	assertConversationEntries(t, result.Entries(), want)
+}
+
+func TestParseGeminiActivityHTML(t *testing.T) {
+	result, err := ParseConversations(strings.NewReader(loadFixture(t, geminiActivityHTMLFixturePath)), ParseOptions{})
+	if err != nil {
+		t.Fatalf("ParseConversations returned error: %v", err)
+	}
+
+	want := []ConversationEntry{
+		geminiEntry(geminiHTMLFranceID, "What is the capital of France?", "2025-03-04T10:15:30Z", "user", "What is the capital of France?", MessageKindPrompt, 0, 7),
+		geminiEntry(geminiHTMLFranceID, "What is the capital of France?", "2025-03-04T10:15:30Z", "assistant", "The capital of France is Paris.", MessageKindAnswer, 1, 7),
+		geminiEntry(geminiHTMLThanksID, "Thanks!", "2025-03-05T09:00:00Z", "user", "Thanks!", MessageKindPrompt, 0, 2),
+		geminiEntry(geminiHTMLThanksID, "Thanks!", "2025-03-05T09:00:00Z", "assistant", "You're welcome & good luck.", MessageKindAnswer, 1, 7),
+	}
+
+	assertConversationEntries(t, result.Entries(), want)
+}
+
+func TestParseGeminiHTMLTime(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{name: "utc", input: "Mar 4, 2025, 10:15:30\u202fAM UTC", want: "2025-03-04T10:15:30Z"},
+		{name: "central european time", input: "4 Mar 2025, 10:15:30 CET", want: "2025-03-04T09:15:30Z"},
+		{name: "pacific daylight time", input: "Jul 4, 2025, 1:00:00 PM PDT", want: "2025-07-04T20:00:00Z"},
+		{name: "half hour offset", input: "4 Mar 2025, 10:15:30 ACST", want: "2025-03-04T00:45:30Z"},
+		{name: "numeric offset", input: "4 Mar 2025, 10:15:30 +05:30", want: "2025-03-04T04:45:30Z"},
+		{name: "unknown abbreviation stays unparsed", input: "4 Mar 2025, 10:15:30 XYZT", want: "4 Mar 2025, 10:15:30 XYZT"},
+		{name: "missing zone stays unparsed", input: "4 Mar 2025", want: "4 Mar 2025"},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			if got := parseGeminiHTMLTime(testCase.input); got != testCase.want {
+				t.Fatalf("expected %q, got %q", testCase.want, got)
+			}
+		})
+	}
+}
+
+func TestGeminiConversationIDsAreDistinct(t *testing.T) {
+	activities := [][]string{
+		{"Prompted first", "2025-03-04T10:15:30Z", "one"},
+		{"Prompted second", "2025-03-04T10:15:30Z", "two"},
+		{"Prompted third", "", "three"},
+		{"Prompted fourth", "", "four"},
+	}
+
+	seen := map[string]bool{}
+	for _, activity := range activities {
+		conversations := geminiActivityConversations(activity[0], activity[1], activity[2])
+		if len(conversations) != 1 {
+			t.Fatalf("expected one conversation for %q, got %d", activity[0], len(conversations))
+		}
+		if id := conversations[0].ID; seen[id] {
+			t.Fatalf("duplicate conversation ID %q for %q", id, activity[0])
+		} else {
+			seen[id] = true
+		}
+	}
+}
+
+func TestHTMLToText(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{name: "returns empty for blank input", input: "  ", want: ""},
+		{name: "joins inline elements without extra spaces", input: "<p>Use <code>go test</code>, then <b>ship</b>.</p>", want: "Use go test, then ship."},
+		{name: "collapses source whitespace", input: "<p>one\n   two</p>", want: "one two"},
+		{name: "separates paragraphs", input: "<p>first</p><p>second</p>", want: "first\n\nsecond"},
+		{name: "renders list items as bullets", input: "<ol><li>a</li><li>b</li></ol>", want: "- a\n- b"},
+		{name: "keeps preformatted indentation", input: "<pre>if x {\n\treturn\n}</pre>", want: "if x {\n\treturn\n}"},
+		{name: "unescapes entities", input: "Tom &amp; Jerry &lt;3", want: "Tom & Jerry <3"},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			if got := htmlToText(testCase.input); got != testCase.want {
+				t.Fatalf("expected %q, got %q", testCase.want, got)
+			}
+		})
+	}
+}
+
+func geminiEntry(conversationID string, conversationName string, timestamp string, speaker string, message string, kind string, messageIndex int, tokenCount int) ConversationEntry {
+	return ConversationEntry{
+		Provider:              ProviderGemini,
+		ConversationID:        conversationID,
+		ConversationName:      conversationName,
+		ConversationCreatedAt: timestamp,
+		Speaker:               speaker,
+		Message:               message,
+		MessageTimestamp:      timestamp,
+		Kind:                  kind,
+		MessageIndex:          messageIndex,
+		TokenCount:            tokenCount,
+	}
+}
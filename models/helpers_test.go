@@ -2,12 +2,17 @@ package models
 
 import (
 	"archive/zip"
+	"bytes"
+	"compress/gzip"
 	"errors"
 	"io/fs"
 	"os"
 	"path/filepath"
+	"reflect"
 	"strings"
 	"testing"
+
+	"github.com/klauspost/compress/zstd"
 )
 
 const goldenConversationsFixturePath = "testdata/conversations_golden.json"
@@ -25,41 +30,41 @@ func loadGoldenConversationsJSON(t *testing.T) string {
 
 func expectedGoldenEntries() []ConversationEntry {
 	return []ConversationEntry{
-		entry(
+		parsedEntry(entry(
 			"conv-1",
 			"Setup",
 			"human",
 			"How do I export data?",
 			"2026-01-02T03:04:05Z",
-		),
-		entry(
+		), ProviderClaude, MessageKindPrompt, 0, 7),
+		parsedEntry(entry(
 			"conv-2",
 			"Setup",
 			"assistant",
 			"Open Settings and click Export data.",
 			"2026-01-02T03:04:30Z",
-		),
-		entry(
+		), ProviderClaude, MessageKindAnswer, 0, 8),
+		parsedEntry(entry(
 			"conv-3",
 			"Multiline",
 			"assistant",
 			"Line one\nLine two",
 			"2026-01-02T03:05:00Z",
-		),
-		entry(
+		), ProviderClaude, MessageKindAnswer, 0, 6),
+		parsedEntry(entry(
 			"conv-4",
 			"International",
 			"研究者🧪",
 			"¡Hola! Привет こんにちは 👋",
 			"2026-01-02T03:05:30Z",
-		),
-		entry(
+		), ProviderClaude, "", 0, 11),
+		parsedEntry(entry(
 			"conv-5",
 			"",
 			"unknown",
 			"Fallback speaker + untitled name",
 			"2026-01-02T03:06:00Z",
-		),
+		), ProviderClaude, "", 0, 7),
 	}
 }
 
@@ -90,13 +95,13 @@ func sampleChatGPTConversationsJSON() string {
 
 func expectedSampleChatGPTEntries() []ConversationEntry {
 	return []ConversationEntry{
-		entry(
+		parsedEntry(entry(
 			"cgpt-loader-1",
 			"ChatGPT Export",
 			"user",
 			"hello from chatgpt export",
 			"2023-11-14T22:20:01Z",
-		),
+		), ProviderChatGPT, MessageKindPrompt, 0, 6),
 	}
 }
 
@@ -135,6 +140,16 @@ func entryWithCreatedAt(
 	}
 }
 
+// parsedEntry fills in the fields every parser derives for a message: the provider, the
+// kind, the message's position in its conversation and its estimated token count.
+func parsedEntry(entry ConversationEntry, provider string, kind string, messageIndex int, tokenCount int) ConversationEntry {
+	entry.Provider = provider
+	entry.Kind = kind
+	entry.MessageIndex = messageIndex
+	entry.TokenCount = tokenCount
+	return entry
+}
+
 func assertConversationEntries(t *testing.T, got []ConversationEntry, want []ConversationEntry) {
 	t.Helper()
 
@@ -148,8 +163,7 @@ func assertConversationEntries(t *testing.T, got []ConversationEntry, want []Con
 		if wantEntry.ConversationCreatedAt == "" {
 			gotEntry.ConversationCreatedAt = ""
 		}
-
-		if gotEntry != wantEntry {
+		if !reflect.DeepEqual(gotEntry, wantEntry) {
 			t.Fatalf("entry %d mismatch\nwant: %+v\ngot:  %+v", index, want[index], got[index])
 		}
 	}
@@ -200,13 +214,46 @@ func writeZipFixture(t *testing.T, dir string, fileName string, files map[string
 	t.Helper()
 
 	path := filepath.Join(dir, fileName)
-	file, err := os.Create(path)
+	if err := os.WriteFile(path, buildZipArchive(t, files), 0o600); err != nil {
+		t.Fatalf("failed to write zip fixture %s: %v", fileName, err)
+	}
+
+	return path
+}
+
+func writeGzipFixture(t *testing.T, dir string, fileName string, content string) string {
+	t.Helper()
+
+	path := filepath.Join(dir, fileName)
+	if err := os.WriteFile(path, gzipBytes(t, content), 0o600); err != nil {
+		t.Fatalf("failed to write gzip fixture %s: %v", fileName, err)
+	}
+
+	return path
+}
+
+func writeZstdFixture(t *testing.T, dir string, fileName string, content string) string {
+	t.Helper()
+
+	encoder, err := zstd.NewWriter(nil)
 	if err != nil {
-		t.Fatalf("failed to create zip fixture %s: %v", fileName, err)
+		t.Fatalf("failed to create zstd encoder: %v", err)
 	}
-	defer file.Close()
+	defer encoder.Close()
+
+	path := filepath.Join(dir, fileName)
+	if err := os.WriteFile(path, encoder.EncodeAll([]byte(content), nil), 0o600); err != nil {
+		t.Fatalf("failed to write zstd fixture %s: %v", fileName, err)
+	}
+
+	return path
+}
+
+func buildZipArchive(t *testing.T, files map[string]string) []byte {
+	t.Helper()
 
-	zipWriter := zip.NewWriter(file)
+	var buffer bytes.Buffer
+	zipWriter := zip.NewWriter(&buffer)
 	for entryName, content := range files {
 		entryWriter, createErr := zipWriter.Create(entryName)
 		if createErr != nil {
@@ -217,8 +264,23 @@ func writeZipFixture(t *testing.T, dir string, fileName string, files map[string
 		}
 	}
 	if err := zipWriter.Close(); err != nil {
-		t.Fatalf("failed to finalize zip fixture %s: %v", fileName, err)
+		t.Fatalf("failed to finalize zip archive: %v", err)
 	}
 
-	return path
+	return buffer.Bytes()
+}
+
+func gzipBytes(t *testing.T, content string) []byte {
+	t.Helper()
+
+	var buffer bytes.Buffer
+	gzipWriter := gzip.NewWriter(&buffer)
+	if _, err := gzipWriter.Write([]byte(content)); err != nil {
+		t.Fatalf("failed to write gzip content: %v", err)
+	}
+	if err := gzipWriter.Close(); err != nil {
+		t.Fatalf("failed to finalize gzip content: %v", err)
+	}
+
+	return buffer.Bytes()
 }
@@ -0,0 +1,196 @@
+package models
+
+import (
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Link is a distinct URL referenced across the loaded conversations, either mentioned in
+// a message or cited as a source.
+type Link struct {
+	URL    string `json:"url"`
+	Title  string `json:"title,omitempty"`
+	Domain string `json:"domain"`
+	// Mentions counts the messages referencing the link; Citations counts those that
+	// recorded it as a source.
+	Mentions      int             `json:"mentions"`
+	Citations     int             `json:"citations"`
+	Conversations []LinkReference `json:"conversations"`
+}
+
+// LinkReference is a conversation that references a link.
+type LinkReference struct {
+	Provider         string `json:"provider,omitempty"`
+	ConversationID   string `json:"conversationId"`
+	ConversationName string `json:"conversationName"`
+}
+
+// messageURLPattern finds http(s) URLs in free text; trailing punctuation and unbalanced
+// closing parentheses are trimmed afterwards.
+var messageURLPattern = regexp.MustCompile(`https?://[^\s<>"'` + "`" + `\[\]{}|\\^]+`)
+
+// trackingQueryPrefixes are query parameters that only identify where a click came from,
+// such as the utm_source=chatgpt.com ChatGPT appends to cited pages.
+var trackingQueryPrefixes = []string{"utm_", "fbclid", "gclid", "msclkid"}
+
+// ExtractLinks catalogs every URL in the conversations' messages and citations. Links are
+// normalized before deduplication and ordered by mentions, then URL.
+func ExtractLinks(conversations []Conversation) []Link {
+	links := make([]*Link, 0, 16)
+	linkIndexes := make(map[string]int, 16)
+	addLink := func(conversation Conversation, seenURLs map[string]struct{}, rawURL string, title string, isCitation bool) {
+		normalizedURL, domain, ok := normalizeLinkURL(rawURL)
+		if !ok {
+			return
+		}
+		if _, alreadySeen := seenURLs[normalizedURL]; alreadySeen {
+			return
+		}
+		seenURLs[normalizedURL] = struct{}{}
+
+		index, exists := linkIndexes[normalizedURL]
+		if !exists {
+			index = len(links)
+			linkIndexes[normalizedURL] = index
+			links = append(links, &Link{URL: normalizedURL, Domain: domain})
+		}
+
+		link := links[index]
+		link.Mentions++
+		if isCitation {
+			link.Citations++
+		}
+		if link.Title == "" {
+			link.Title = strings.TrimSpace(title)
+		}
+		link.Conversations = appendLinkReference(link.Conversations, conversation)
+	}
+
+	for _, conversation := range conversations {
+		for _, message := range conversation.Messages {
+			seenURLs := make(map[string]struct{}, len(message.Citations))
+			for _, citation := range message.Citations {
+				addLink(conversation, seenURLs, citation.URL, citation.Title, true)
+			}
+			for _, rawURL := range findMessageURLs(message.Text) {
+				addLink(conversation, seenURLs, rawURL, "", false)
+			}
+		}
+	}
+
+	catalog := make([]Link, 0, len(links))
+	for _, link := range links {
+		catalog = append(catalog, *link)
+	}
+	sort.SliceStable(catalog, func(i, j int) bool {
+		if catalog[i].Mentions != catalog[j].Mentions {
+			return catalog[i].Mentions > catalog[j].Mentions
+		}
+		return catalog[i].URL < catalog[j].URL
+	})
+
+	return catalog
+}
+
+func appendLinkReference(references []LinkReference, conversation Conversation) []LinkReference {
+	for _, reference := range references {
+		if reference.ConversationID == conversation.ID {
+			return references
+		}
+	}
+
+	return append(references, LinkReference{
+		Provider:         conversation.Provider,
+		ConversationID:   conversation.ID,
+		ConversationName: conversation.Name,
+	})
+}
+
+func findMessageURLs(text string) []string {
+	if !strings.Contains(text, "http") {
+		return nil
+	}
+
+	matches := messageURLPattern.FindAllString(text, -1)
+	urls := make([]string, 0, len(matches))
+	for _, match := range matches {
+		if trimmedURL := trimURLSuffix(match); trimmedURL != "" {
+			urls = append(urls, trimmedURL)
+		}
+	}
+
+	return urls
+}
+
+// trimURLSuffix drops sentence punctuation after a URL and closing parentheses that were
+// not opened inside it, as in "(see https://example.com)." or Markdown links.
+func trimURLSuffix(rawURL string) string {
+	for rawURL != "" {
+		last := rawURL[len(rawURL)-1]
+		switch {
+		case strings.IndexByte(".,;:!?*_~", last) >= 0:
+			rawURL = rawURL[:len(rawURL)-1]
+		case last == ')' && strings.Count(rawURL, "(") < strings.Count(rawURL, ")"):
+			rawURL = rawURL[:len(rawURL)-1]
+		default:
+			return rawURL
+		}
+	}
+
+	return rawURL
+}
+
+// normalizeLinkURL lowercases the scheme and host, drops default ports, fragments,
+// tracking parameters and trailing slashes, and sorts the query.
+func normalizeLinkURL(rawURL string) (string, string, bool) {
+	parsedURL, err := url.Parse(strings.TrimSpace(rawURL))
+	if err != nil || parsedURL.Host == "" {
+		return "", "", false
+	}
+
+	parsedURL.Scheme = strings.ToLower(parsedURL.Scheme)
+	if parsedURL.Scheme != "http" && parsedURL.Scheme != "https" {
+		return "", "", false
+	}
+
+	host := strings.ToLower(parsedURL.Hostname())
+	port := parsedURL.Port()
+	if (parsedURL.Scheme == "http" && port == "80") || (parsedURL.Scheme == "https" && port == "443") {
+		port = ""
+	}
+	parsedURL.Host = host
+	if strings.Contains(host, ":") {
+		parsedURL.Host = "[" + host + "]"
+	}
+	if port != "" {
+		parsedURL.Host += ":" + port
+	}
+	parsedURL.Fragment = ""
+	parsedURL.RawFragment = ""
+	parsedURL.User = nil
+
+	query := parsedURL.Query()
+	for key := range query {
+		if isTrackingQueryParameter(key) {
+			query.Del(key)
+		}
+	}
+	parsedURL.RawQuery = query.Encode()
+	parsedURL.Path = strings.TrimRight(parsedURL.Path, "/")
+	parsedURL.RawPath = strings.TrimRight(parsedURL.RawPath, "/")
+
+	return parsedURL.String(), strings.TrimPrefix(host, "www."), true
+}
+
+func isTrackingQueryParameter(key string) bool {
+	lowerKey := strings.ToLower(key)
+	for _, prefix := range trackingQueryPrefixes {
+		if strings.HasPrefix(lowerKey, prefix) {
+			return true
+		}
+	}
+
+	return false
+}
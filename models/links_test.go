@@ -0,0 +1,170 @@
+package models
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestFindMessageURLs(t *testing.T) {
+	tests := []struct {
+		name     string
+		text     string
+		expected []string
+	}{
+		{name: "no urls", text: "Nothing to see here.", expected: nil},
+		{
+			name:     "sentence punctuation",
+			text:     "Read https://go.dev/doc/effective_go, then http://example.com/a?b=1.",
+			expected: []string{"https://go.dev/doc/effective_go", "http://example.com/a?b=1"},
+		},
+		{
+			name:     "parentheses",
+			text:     "(see https://en.wikipedia.org/wiki/Go_(programming_language)) and [docs](https://pkg.go.dev/net/url)",
+			expected: []string{"https://en.wikipedia.org/wiki/Go_(programming_language)", "https://pkg.go.dev/net/url"},
+		},
+		{name: "angle brackets", text: "<https://example.com/x>", expected: []string{"https://example.com/x"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := findMessageURLs(tt.text)
+			if len(got) == 0 && len(tt.expected) == 0 {
+				return
+			}
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Fatalf("expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestNormalizeLinkURL(t *testing.T) {
+	tests := []struct {
+		name           string
+		rawURL         string
+		expectedURL    string
+		expectedDomain string
+		expectedOK     bool
+	}{
+		{name: "host case and root slash", rawURL: "HTTPS://WWW.Example.com/", expectedURL: "https://www.example.com", expectedDomain: "example.com", expectedOK: true},
+		{name: "tracking parameters and fragment", rawURL: "https://go.dev/blog?utm_source=chatgpt.com&b=2&a=1#top", expectedURL: "https://go.dev/blog?a=1&b=2", expectedDomain: "go.dev", expectedOK: true},
+		{name: "default port", rawURL: "http://example.com:80/path", expectedURL: "http://example.com/path", expectedDomain: "example.com", expectedOK: true},
+		{name: "custom port", rawURL: "http://localhost:8080/", expectedURL: "http://localhost:8080", expectedDomain: "localhost", expectedOK: true},
+		{name: "ipv6 host", rawURL: "http://[::1]:8080/x", expectedURL: "http://[::1]:8080/x", expectedDomain: "::1", expectedOK: true},
+		{name: "not http", rawURL: "ftp://example.com/file", expectedOK: false},
+		{name: "no host", rawURL: "https://", expectedOK: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotURL, gotDomain, gotOK := normalizeLinkURL(tt.rawURL)
+			if gotOK != tt.expectedOK || gotURL != tt.expectedURL || gotDomain != tt.expectedDomain {
+				t.Fatalf("expected (%q, %q, %v), got (%q, %q, %v)", tt.expectedURL, tt.expectedDomain, tt.expectedOK, gotURL, gotDomain, gotOK)
+			}
+		})
+	}
+}
+
+func TestExtractLinks(t *testing.T) {
+	conversations := []Conversation{
+		{
+			Provider: ProviderChatGPT,
+			ID:       "conv-1",
+			Name:     "Go news",
+			Messages: []Message{
+				{Speaker: "user", Text: "What's new at https://go.dev/blog?"},
+				{
+					Speaker:   "assistant",
+					Text:      "See the blog (https://go.dev/blog/) and https://pkg.go.dev.",
+					Citations: []Citation{{Title: "The Go Blog", URL: "https://go.dev/blog?utm_source=chatgpt.com"}},
+				},
+			},
+		},
+		{
+			Provider: ProviderClaude,
+			ID:       "conv-2",
+			Name:     "Packages",
+			Messages: []Message{{Speaker: "assistant", Text: "Try https://pkg.go.dev/ and https://go.dev/blog#latest"}},
+		},
+	}
+
+	got := ExtractLinks(conversations)
+	expected := []Link{
+		{
+			URL:       "https://go.dev/blog",
+			Title:     "The Go Blog",
+			Domain:    "go.dev",
+			Mentions:  3,
+			Citations: 1,
+			Conversations: []LinkReference{
+				{Provider: ProviderChatGPT, ConversationID: "conv-1", ConversationName: "Go news"},
+				{Provider: ProviderClaude, ConversationID: "conv-2", ConversationName: "Packages"},
+			},
+		},
+		{
+			URL:      "https://pkg.go.dev",
+			Domain:   "pkg.go.dev",
+			Mentions: 2,
+			Conversations: []LinkReference{
+				{Provider: ProviderChatGPT, ConversationID: "conv-1", ConversationName: "Go news"},
+				{Provider: ProviderClaude, ConversationID: "conv-2", ConversationName: "Packages"},
+			},
+		},
+	}
+	if !reflect.DeepEqual(got, expected) {
+		t.Fatalf("unexpected links:\n got: %+v\nwant: %+v", got, expected)
+	}
+}
+
+func TestParseChatGPTContentReferences(t *testing.T) {
+	input := `[
+		{
+			"title": "Sources",
+			"conversation_id": "refs-1",
+			"mapping": {
+				"answer": {
+					"id": "answer", "parent": null, "children": [],
+					"message": {
+						"author": {"role": "assistant"},
+						"content": {"content_type": "text", "parts": ["Here is what I found."]},
+						"metadata": {
+							"content_references": [
+								{"type": "webpage", "title": "Single page", "url": "https://example.com/single"},
+								{
+									"type": "grouped_webpages",
+									"items": [
+										{
+											"title": "Grouped page",
+											"url": "https://example.com/grouped",
+											"supporting_websites": [{"title": "Supporting", "url": "https://example.org/support"}]
+										}
+									],
+									"safe_urls": ["https://example.com/grouped", "https://example.net/safe"]
+								}
+							]
+						}
+					}
+				}
+			}
+		}
+	]`
+
+	entries, err := ParseConversationsJSON(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("ParseConversationsJSON returned error: %v", err)
+	}
+	if len(entries) != 1 {
+		t.Fatalf("expected 1 entry, got %d", len(entries))
+	}
+
+	expected := []Citation{
+		{Title: "Single page", URL: "https://example.com/single"},
+		{Title: "Grouped page", URL: "https://example.com/grouped"},
+		{Title: "Supporting", URL: "https://example.org/support"},
+		{URL: "https://example.net/safe"},
+	}
+	if !reflect.DeepEqual(entries[0].Citations, expected) {
+		t.Fatalf("unexpected citations %+v", entries[0].Citations)
+	}
+}
@@ -2,70 +2,376 @@ package models
 
 import (
 	"archive/zip"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"errors"
 	"fmt"
+	"io"
 	"os"
-	"path/filepath"
+	"path"
 	"strings"
+
+	"github.com/klauspost/compress/zstd"
 )
 
 const conversationsFileName = "conversations.json"
 
+// maxNestedArchiveDepth bounds how many archive or compression layers are unwrapped,
+// e.g. a zip forwarded by email inside another zip, or a gzip of a zip.
+const maxNestedArchiveDepth = 4
+
+const magicHeaderLength = 4
+
+// maxDecompressedSize bounds the bytes read from any decompressed stream or archive
+// entry, including the nested zips spooled to disk, so a zip bomb fails with
+// ErrInputTooLarge instead of filling memory or disk. It is a variable so tests can
+// lower it.
+var maxDecompressedSize int64 = 2 << 30
+
+// spoolDir is where nested archives are copied for random access; blank uses the
+// system temporary directory. It is a variable so tests can check the copies are
+// removed.
+var spoolDir = ""
+
+// ErrInputTooLarge is returned when a decompressed layer of an export is larger than
+// maxDecompressedSize.
+var ErrInputTooLarge = errors.New("decompressed input is too large")
+
+type inputFormat int
+
+const (
+	inputFormatPlain inputFormat = iota
+	inputFormatZip
+	inputFormatGzip
+	inputFormatZstd
+)
+
+var (
+	zipMagic      = []byte("PK\x03\x04")
+	emptyZipMagic = []byte("PK\x05\x06")
+	gzipMagic     = []byte{0x1f, 0x8b}
+	zstdMagic     = []byte{0x28, 0xb5, 0x2f, 0xfd}
+)
+
+// compressedConversationsSuffixes are the extensions accepted after conversations.json
+// when looking for candidates inside an archive. Decoding still relies on magic bytes.
+var compressedConversationsSuffixes = []string{"", ".gz", ".zst", ".zstd"}
+
+// geminiActivityFileNames are the Google Takeout "My Activity" files. Every Google
+// product uses the same names, so they only count inside a Gemini Apps or Bard folder.
+var geminiActivityFileNames = []string{"myactivity.json", "my activity.json", "myactivity.html", "my activity.html"}
+
+// AmbiguousConversationsFileError is returned when an archive holds more than one
+// conversations.json candidate, so the loader refuses to guess which one is meant.
+type AmbiguousConversationsFileError struct {
+	Candidates []string
+}
+
+func (e *AmbiguousConversationsFileError) Error() string {
+	return fmt.Sprintf(
+		"multiple %s candidates found in zip archive: %s",
+		conversationsFileName,
+		strings.Join(e.Candidates, ", "),
+	)
+}
+
+type conversationsCandidate struct {
+	path string
+	file *zip.File
+	// depth is the number of layers unwrapped around the archive holding the candidate.
+	depth int
+}
+
 func LoadConversationEntries(path string) ([]ConversationEntry, error) {
-	trimmedPath := strings.TrimSpace(path)
-	if trimmedPath == "" {
-		return nil, fmt.Errorf("path is required")
+	result, err := LoadConversations(path, ParseOptions{})
+	if err != nil {
+		return nil, err
 	}
 
-	if strings.EqualFold(filepath.Ext(trimmedPath), ".zip") {
-		return loadConversationEntriesFromZip(trimmedPath)
+	return result.Entries(), nil
+}
+
+// LoadConversations loads an export from path, unwrapping archives and compression,
+// and parses it with the given options.
+func LoadConversations(path string, options ParseOptions) (ParseResult, error) {
+	var result ParseResult
+	err := readConversationsDocument(path, func(document io.Reader) error {
+		parsedResult, parseErr := ParseConversations(document, options)
+		if parseErr != nil {
+			return fmt.Errorf("parse conversations: %w", parseErr)
+		}
+		result = parsedResult
+		return nil
+	})
+	if err != nil {
+		return ParseResult{}, err
 	}
 
-	return loadConversationEntriesFromJSON(trimmedPath)
+	return result, nil
 }
 
-func loadConversationEntriesFromJSON(path string) ([]ConversationEntry, error) {
-	file, err := os.Open(path)
+// readConversationsDocument unwraps the archives and compression around the export at
+// path and hands the plain conversations document to read.
+func readConversationsDocument(path string, read func(document io.Reader) error) error {
+	trimmedPath := strings.TrimSpace(path)
+	if trimmedPath == "" {
+		return fmt.Errorf("path is required")
+	}
+
+	file, err := os.Open(trimmedPath)
 	if err != nil {
-		return nil, fmt.Errorf("open file: %w", err)
+		return fmt.Errorf("open file: %w", err)
 	}
 	defer file.Close()
 
-	entries, err := ParseConversationsJSON(file)
-	if err != nil {
-		return nil, fmt.Errorf("parse conversations json: %w", err)
+	header := make([]byte, magicHeaderLength)
+	headerLength, err := file.ReadAt(header, 0)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return fmt.Errorf("read file header: %w", err)
+	}
+
+	// Top-level zips are read in place so large exports are not copied into memory.
+	if detectInputFormat(header[:headerLength]) == inputFormatZip {
+		info, statErr := file.Stat()
+		if statErr != nil {
+			return fmt.Errorf("stat file: %w", statErr)
+		}
+
+		archive, zipErr := zip.NewReader(file, info.Size())
+		if zipErr != nil {
+			return fmt.Errorf("open zip archive: %w", zipErr)
+		}
+
+		return readDocumentFromZip(archive, 0, read)
+	}
+
+	return readDocumentFromStream(file, 0, read)
+}
+
+func detectInputFormat(header []byte) inputFormat {
+	switch {
+	case bytes.HasPrefix(header, zipMagic), bytes.HasPrefix(header, emptyZipMagic):
+		return inputFormatZip
+	case bytes.HasPrefix(header, gzipMagic):
+		return inputFormatGzip
+	case bytes.HasPrefix(header, zstdMagic):
+		return inputFormatZstd
+	default:
+		return inputFormatPlain
+	}
+}
+
+func readDocumentFromStream(input io.Reader, depth int, read func(document io.Reader) error) error {
+	reader := bufio.NewReader(input)
+	header, err := reader.Peek(magicHeaderLength)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return fmt.Errorf("read input header: %w", err)
+	}
+
+	format := detectInputFormat(header)
+	if format != inputFormatPlain && depth >= maxNestedArchiveDepth {
+		return fmt.Errorf("input is nested deeper than %d archive layers", maxNestedArchiveDepth)
+	}
+
+	switch format {
+	case inputFormatGzip:
+		gzipReader, gzipErr := gzip.NewReader(reader)
+		if gzipErr != nil {
+			return fmt.Errorf("open gzip stream: %w", gzipErr)
+		}
+		defer gzipReader.Close()
+
+		return readDocumentFromStream(limitDecompressed(gzipReader), depth+1, read)
+	case inputFormatZstd:
+		zstdReader, zstdErr := zstd.NewReader(reader)
+		if zstdErr != nil {
+			return fmt.Errorf("open zstd stream: %w", zstdErr)
+		}
+		defer zstdReader.Close()
+
+		return readDocumentFromStream(limitDecompressed(zstdReader), depth+1, read)
+	case inputFormatZip:
+		// zip needs random access, so a zip wrapped in a compressed stream is spooled.
+		spool := &archiveSpool{}
+		defer spool.close()
+
+		archive, spoolErr := spool.open(reader)
+		if spoolErr != nil {
+			return fmt.Errorf("open zip archive: %w", spoolErr)
+		}
+
+		return readDocumentFromZip(archive, depth+1, read)
 	}
 
-	return entries, nil
+	return read(reader)
 }
 
-func loadConversationEntriesFromZip(path string) ([]ConversationEntry, error) {
-	archive, err := zip.OpenReader(path)
+func readDocumentFromZip(archive *zip.Reader, depth int, read func(document io.Reader) error) error {
+	// The nested archives holding the candidates stay open until the document was read.
+	spool := &archiveSpool{}
+	defer spool.close()
+
+	candidates, err := collectConversationsCandidates(archive, "", depth, spool)
+	if err != nil {
+		return err
+	}
+
+	if len(candidates) == 0 {
+		return fmt.Errorf("%s not found in zip archive", conversationsFileName)
+	}
+	if len(candidates) > 1 {
+		candidatePaths := make([]string, 0, len(candidates))
+		for _, candidate := range candidates {
+			candidatePaths = append(candidatePaths, candidate.path)
+		}
+		return &AmbiguousConversationsFileError{Candidates: candidatePaths}
+	}
+
+	candidate := candidates[0]
+	reader, err := candidate.file.Open()
 	if err != nil {
-		return nil, fmt.Errorf("open zip archive: %w", err)
+		return fmt.Errorf("open %s from zip archive: %w", candidate.path, err)
+	}
+
+	readErr := readDocumentFromStream(limitDecompressed(reader), candidate.depth, read)
+	closeErr := reader.Close()
+	if readErr != nil {
+		return fmt.Errorf("load %s from zip archive: %w", candidate.path, readErr)
+	}
+	if closeErr != nil {
+		return fmt.Errorf("close %s from zip archive: %w", candidate.path, closeErr)
 	}
-	defer archive.Close()
 
+	return nil
+}
+
+// collectConversationsCandidates walks an archive, descending into nested zips spooled
+// to spool, and returns every entry that looks like a conversations.json export.
+func collectConversationsCandidates(archive *zip.Reader, prefix string, depth int, spool *archiveSpool) ([]conversationsCandidate, error) {
+	candidates := make([]conversationsCandidate, 0, 1)
 	for _, file := range archive.File {
-		if !strings.EqualFold(filepath.Base(file.Name), conversationsFileName) {
+		if file.FileInfo().IsDir() {
+			continue
+		}
+
+		entryPath := prefix + file.Name
+		if isConversationsFileName(file.Name) {
+			candidates = append(candidates, conversationsCandidate{path: entryPath, file: file, depth: depth})
+			continue
+		}
+		if depth >= maxNestedArchiveDepth {
 			continue
 		}
 
-		reader, openErr := file.Open()
-		if openErr != nil {
-			return nil, fmt.Errorf("open %s from zip archive: %w", conversationsFileName, openErr)
+		nestedArchive, err := openNestedZip(file, spool)
+		if err != nil {
+			return nil, fmt.Errorf("open nested archive %s: %w", entryPath, err)
+		}
+		if nestedArchive == nil {
+			continue
 		}
 
-		entries, parseErr := ParseConversationsJSON(reader)
-		closeErr := reader.Close()
-		if parseErr != nil {
-			return nil, fmt.Errorf("parse %s from zip archive: %w", conversationsFileName, parseErr)
+		nestedCandidates, err := collectConversationsCandidates(nestedArchive, entryPath+"/", depth+1, spool)
+		if err != nil {
+			return nil, err
 		}
-		if closeErr != nil {
-			return nil, fmt.Errorf("close %s from zip archive: %w", conversationsFileName, closeErr)
+		candidates = append(candidates, nestedCandidates...)
+	}
+
+	return candidates, nil
+}
+
+func isConversationsFileName(name string) bool {
+	baseName := strings.ToLower(path.Base(name))
+	for _, suffix := range compressedConversationsSuffixes {
+		if baseName == conversationsFileName+suffix {
+			return true
+		}
+	}
+
+	if isGeminiActivityHeader(path.Base(path.Dir(name))) {
+		for _, activityFileName := range geminiActivityFileNames {
+			if baseName == activityFileName {
+				return true
+			}
 		}
+	}
+
+	return false
+}
+
+// openNestedZip returns the archive stored in file, or nil when the entry is not a zip.
+// The archive is read from a copy in spool.
+func openNestedZip(file *zip.File, spool *archiveSpool) (*zip.Reader, error) {
+	reader, err := file.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+
+	bufferedReader := bufio.NewReader(reader)
+	header, err := bufferedReader.Peek(magicHeaderLength)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return nil, err
+	}
+	if detectInputFormat(header) != inputFormatZip {
+		return nil, nil
+	}
+
+	return spool.open(bufferedReader)
+}
+
+// archiveSpool copies zips read from a stream to temporary files, so they can be read
+// with random access without holding them in memory.
+type archiveSpool struct {
+	files []*os.File
+}
+
+// open copies at most maxDecompressedSize bytes of reader to a temporary file and opens
+// the zip archive it holds. The file is removed by close.
+func (s *archiveSpool) open(reader io.Reader) (*zip.Reader, error) {
+	file, err := os.CreateTemp(spoolDir, "chat-explorer-*.zip")
+	if err != nil {
+		return nil, fmt.Errorf("create temporary file: %w", err)
+	}
+	s.files = append(s.files, file)
+
+	size, err := io.Copy(file, limitDecompressed(reader))
+	if err != nil {
+		return nil, err
+	}
+
+	return zip.NewReader(file, size)
+}
+
+// close closes and removes every file spooled so far.
+func (s *archiveSpool) close() {
+	for _, file := range s.files {
+		_ = file.Close()
+		_ = os.Remove(file.Name())
+	}
+	s.files = nil
+}
+
+// limitDecompressed fails reads from reader with ErrInputTooLarge once more than
+// maxDecompressedSize bytes were read.
+func limitDecompressed(reader io.Reader) io.Reader {
+	return &sizeLimitedReader{reader: io.LimitReader(reader, maxDecompressedSize+1), limit: maxDecompressedSize}
+}
+
+type sizeLimitedReader struct {
+	reader io.Reader
+	limit  int64
+	read   int64
+}
 
-		return entries, nil
+func (r *sizeLimitedReader) Read(p []byte) (int, error) {
+	n, err := r.reader.Read(p)
+	r.read += int64(n)
+	if r.read > r.limit {
+		return n, fmt.Errorf("%w: over %d bytes", ErrInputTooLarge, r.limit)
 	}
 
-	return nil, fmt.Errorf("%s not found in zip archive", conversationsFileName)
+	return n, err
 }
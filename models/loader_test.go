@@ -1,8 +1,11 @@
 package models
 
 import (
+	"errors"
 	"os"
 	"runtime"
+	"sort"
+	"strings"
 	"testing"
 )
 
@@ -44,6 +47,34 @@ func TestLoadConversationEntries(t *testing.T) {
 			path:        writeZipFixture(t, tmpDir, "chatgpt-export.zip", map[string]string{"conversations.json": chatGPTConversationsJSON}),
 			wantEntries: chatGPTEntries,
 		},
+		{
+			name: "loads gemini my activity from google takeout zip",
+			path: writeZipFixture(t, tmpDir, "takeout.zip", map[string]string{
+				"Takeout/My Activity/Gemini Apps/MyActivity.json": loadFixture(t, geminiActivityJSONFixturePath),
+				"Takeout/My Activity/Search/MyActivity.json":      `[]`,
+				"Takeout/archive_browser.html":                    "<html></html>",
+			}),
+			wantEntries: []ConversationEntry{
+				geminiEntry(geminiFranceID, "What is the capital of France?", "2025-03-04T10:15:30.123Z", "user", "What is the capital of France?", MessageKindPrompt, 0, 7),
+				geminiEntry(geminiFranceID, "What is the capital of France?", "2025-03-04T10:15:30.123Z", "assistant", "The capital of France is Paris.\n\n- Population: about 2.1 million\n- River: Seine", MessageKindAnswer, 1, 22),
+				geminiEntry(geminiHaikuID, "Write a haiku about autumn", "2023-10-01T08:00:00Z", "user", "Write a haiku about autumn", MessageKindPrompt, 0, 6),
+				geminiEntry(geminiHaikuID, "Write a haiku about autumn", "2023-10-01T08:00:00Z", "assistant", "Crisp leaves drift and fall\nGolden light on quiet paths\nThe year exhales slow", MessageKindAnswer, 1, 19),
+				geminiEntry(geminiHelloWorldID, "Show me a Go hello world", "2025-03-05T09:00:00Z", "user", "Show me a Go hello world", MessageKindPrompt, 0, 6),
+				geminiEntry(geminiHelloWorldID, "Show me a Go hello world", "2025-03-05T09:00:00Z", "assistant", "Here you go:\n\npackage main\n\nfunc main() {\n    println(\"hello\")\n}", MessageKindAnswer, 1, 17),
+			},
+		},
+		{
+			name: "loads gemini my activity html from google takeout zip",
+			path: writeZipFixture(t, tmpDir, "takeout-html.zip", map[string]string{
+				"Takeout/My Activity/Gemini Apps/My Activity.html": loadFixture(t, geminiActivityHTMLFixturePath),
+			}),
+			wantEntries: []ConversationEntry{
+				geminiEntry(geminiHTMLFranceID, "What is the capital of France?", "2025-03-04T10:15:30Z", "user", "What is the capital of France?", MessageKindPrompt, 0, 7),
+				geminiEntry(geminiHTMLFranceID, "What is the capital of France?", "2025-03-04T10:15:30Z", "assistant", "The capital of France is Paris.", MessageKindAnswer, 1, 7),
+				geminiEntry(geminiHTMLThanksID, "Thanks!", "2025-03-05T09:00:00Z", "user", "Thanks!", MessageKindPrompt, 0, 2),
+				geminiEntry(geminiHTMLThanksID, "Thanks!", "2025-03-05T09:00:00Z", "assistant", "You're welcome & good luck.", MessageKindAnswer, 1, 7),
+			},
+		},
 		{
 			name:            "returns error when zip has no conversations json",
 			path:            writeZipFixture(t, tmpDir, "missing-conversations.zip", map[string]string{"projects.json": `[]`}),
@@ -55,10 +86,74 @@ func TestLoadConversationEntries(t *testing.T) {
 			wantErrContains: "path is required",
 		},
 		{
-			name:            "returns error for invalid zip file",
-			path:            writeJSONFixture(t, tmpDir, "broken.zip", "{not a zip}"),
+			name:        "detects json content by magic bytes despite zip extension",
+			path:        writeJSONFixture(t, tmpDir, "misnamed.zip", goldenConversationsJSON),
+			wantEntries: goldenEntries,
+		},
+		{
+			name:            "returns error for truncated zip archive",
+			path:            writeJSONFixture(t, tmpDir, "broken.zip", "PK\x03\x04not really a zip"),
 			wantErrContains: "open zip archive",
 		},
+		{
+			name:        "loads gzip compressed json export",
+			path:        writeGzipFixture(t, tmpDir, "conversations.json.gz", goldenConversationsJSON),
+			wantEntries: goldenEntries,
+		},
+		{
+			name: "loads gzip compressed jsonl api log",
+			path: writeGzipFixture(t, tmpDir, "api.jsonl.gz", `{"custom_id":"api-1","messages":[{"role":"user","content":"from a log"}]}`+"\n"),
+			wantEntries: []ConversationEntry{
+				{Provider: ProviderOpenAI, ConversationID: "api-1", ConversationName: "from a log", Speaker: "user", Message: "from a log", Kind: MessageKindPrompt, TokenCount: 3},
+			},
+		},
+		{
+			name:        "loads zstd compressed json export",
+			path:        writeZstdFixture(t, tmpDir, "conversations.zst", chatGPTConversationsJSON),
+			wantEntries: chatGPTEntries,
+		},
+		{
+			name:        "loads gzip compressed zip export",
+			path:        writeGzipFixture(t, tmpDir, "export.zip.gz", string(buildZipArchive(t, map[string]string{"conversations.json": goldenConversationsJSON}))),
+			wantEntries: goldenEntries,
+		},
+		{
+			name:        "loads gzip compressed conversations json inside zip export",
+			path:        writeZipFixture(t, tmpDir, "gzip-entry.zip", map[string]string{"conversations.json.gz": string(gzipBytes(t, goldenConversationsJSON))}),
+			wantEntries: goldenEntries,
+		},
+		{
+			name: "loads conversations json from zip nested inside zip",
+			path: writeZipFixture(t, tmpDir, "forwarded.zip", map[string]string{
+				"attachments/export.zip": string(buildZipArchive(t, map[string]string{"conversations.json": goldenConversationsJSON})),
+				"notes.txt":              "forwarded export",
+			}),
+			wantEntries: goldenEntries,
+		},
+		{
+			name: "counts the layers of a candidate from its own nested archive",
+			path: writeZipFixture(t, tmpDir, "deeply-forwarded.zip", map[string]string{
+				"1.zip": string(buildZipArchive(t, map[string]string{
+					"2.zip": string(buildZipArchive(t, map[string]string{
+						"3.zip": string(buildZipArchive(t, map[string]string{
+							"4.zip": string(buildZipArchive(t, map[string]string{
+								"conversations.json.gz": string(gzipBytes(t, goldenConversationsJSON)),
+							})),
+						})),
+					})),
+				})),
+			}),
+			wantErrContains: "nested deeper than 4 archive layers",
+		},
+		{
+			name: "lists candidates when zip has multiple conversations json files",
+			path: writeZipFixture(t, tmpDir, "ambiguous.zip", map[string]string{
+				"conversations.json":  goldenConversationsJSON,
+				"older/export.zip":    string(buildZipArchive(t, map[string]string{"conversations.json": chatGPTConversationsJSON})),
+				"older/memories.json": `[]`,
+			}),
+			wantErrContains: "older/export.zip/conversations.json",
+		},
 	}
 
 	for _, testCase := range tests {
@@ -78,6 +173,69 @@ func TestLoadConversationEntries(t *testing.T) {
 	}
 }
 
+func TestLoadConversationEntriesRejectsOversizedInput(t *testing.T) {
+	tmpDir := t.TempDir()
+	goldenConversationsJSON := loadGoldenConversationsJSON(t)
+
+	previousLimit := maxDecompressedSize
+	maxDecompressedSize = int64(len(goldenConversationsJSON) - 1)
+	t.Cleanup(func() {
+		maxDecompressedSize = previousLimit
+	})
+
+	paths := map[string]string{
+		"gzip stream":       writeGzipFixture(t, tmpDir, "conversations.json.gz", goldenConversationsJSON),
+		"zstd stream":       writeZstdFixture(t, tmpDir, "conversations.zst", goldenConversationsJSON),
+		"zip entry":         writeZipFixture(t, tmpDir, "export.zip", map[string]string{"conversations.json": goldenConversationsJSON}),
+		"gzip wrapped zip":  writeGzipFixture(t, tmpDir, "export.zip.gz", string(buildZipArchive(t, map[string]string{"conversations.json": goldenConversationsJSON}))),
+		"zip nested in zip": writeZipFixture(t, tmpDir, "forwarded.zip", map[string]string{"export.zip": string(buildZipArchive(t, map[string]string{"conversations.json": goldenConversationsJSON}))}),
+	}
+	for name, path := range paths {
+		t.Run(name, func(t *testing.T) {
+			_, err := LoadConversationEntries(path)
+			if !errors.Is(err, ErrInputTooLarge) {
+				t.Fatalf("expected ErrInputTooLarge, got %v", err)
+			}
+		})
+	}
+
+	// A plain file is not decompressed, so the limit does not apply.
+	if _, err := LoadConversationEntries(writeJSONFixture(t, tmpDir, "conversations.json", goldenConversationsJSON)); err != nil {
+		t.Fatalf("expected a plain export to load, got %v", err)
+	}
+}
+
+func TestLoadConversationEntriesRemovesSpooledArchives(t *testing.T) {
+	tmpDir := t.TempDir()
+	goldenConversationsJSON := loadGoldenConversationsJSON(t)
+	nestedArchive := string(buildZipArchive(t, map[string]string{"conversations.json": goldenConversationsJSON}))
+
+	previousSpoolDir := spoolDir
+	spoolDir = t.TempDir()
+	t.Cleanup(func() {
+		spoolDir = previousSpoolDir
+	})
+
+	paths := map[string]string{
+		"gzip wrapped zip":      writeGzipFixture(t, tmpDir, "export.zip.gz", nestedArchive),
+		"zip nested in zip":     writeZipFixture(t, tmpDir, "forwarded.zip", map[string]string{"export.zip": nestedArchive}),
+		"ambiguous nested zips": writeZipFixture(t, tmpDir, "ambiguous.zip", map[string]string{"a.zip": nestedArchive, "b.zip": nestedArchive}),
+	}
+	for name, path := range paths {
+		t.Run(name, func(t *testing.T) {
+			_, _ = LoadConversationEntries(path)
+
+			spooled, err := os.ReadDir(spoolDir)
+			if err != nil {
+				t.Fatalf("ReadDir returned error: %v", err)
+			}
+			if len(spooled) != 0 {
+				t.Fatalf("expected spooled archives to be removed, found %d files", len(spooled))
+			}
+		})
+	}
+}
+
 func TestLoadConversationEntriesPermissionDenied(t *testing.T) {
 	if runtime.GOOS == "windows" {
 		t.Skip("permission mode checks are unreliable on windows")
@@ -117,3 +275,50 @@ func TestLoadConversationEntriesPermissionDenied(t *testing.T) {
 		})
 	}
 }
+
+func TestLoadConversationEntriesReportsAllCandidates(t *testing.T) {
+	tmpDir := t.TempDir()
+	goldenConversationsJSON := loadGoldenConversationsJSON(t)
+
+	path := writeZipFixture(t, tmpDir, "ambiguous.zip", map[string]string{
+		"a/conversations.json": goldenConversationsJSON,
+		"b/conversations.json": goldenConversationsJSON,
+	})
+
+	_, err := LoadConversationEntries(path)
+
+	var ambiguousErr *AmbiguousConversationsFileError
+	if !errors.As(err, &ambiguousErr) {
+		t.Fatalf("expected AmbiguousConversationsFileError, got %v", err)
+	}
+
+	candidates := append([]string(nil), ambiguousErr.Candidates...)
+	sort.Strings(candidates)
+	want := []string{"a/conversations.json", "b/conversations.json"}
+	if strings.Join(candidates, ",") != strings.Join(want, ",") {
+		t.Fatalf("expected candidates %v, got %v", want, candidates)
+	}
+}
+
+func TestLoadConversationsLenient(t *testing.T) {
+	tmpDir := t.TempDir()
+	input := `[
+		{ "uuid": "bad", "chat_messages": {} },
+		{ "uuid": "good", "name": "Good", "chat_messages": [{ "sender": "human", "text": "hello" }] }
+	]`
+	path := writeZipFixture(t, tmpDir, "export.zip", map[string]string{"conversations.json": input})
+
+	if _, err := LoadConversationEntries(path); err == nil {
+		t.Fatal("expected strict load to fail on malformed conversation")
+	}
+
+	result, err := LoadConversations(path, ParseOptions{Lenient: true})
+	if err != nil {
+		t.Fatalf("LoadConversations returned error: %v", err)
+	}
+
+	assertConversationEntries(t, result.Entries(), []ConversationEntry{parsedEntry(entry("good", "Good", "human", "hello", ""), ProviderClaude, MessageKindPrompt, 0, 2)})
+	if len(result.Warnings) != 1 || result.Warnings[0].ConversationID != "bad" {
+		t.Fatalf("expected one warning for conversation %q, got %+v", "bad", result.Warnings)
+	}
+}
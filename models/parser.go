@@ -1,22 +1,99 @@
 package models
 
 import (
+	"bufio"
+	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"math"
 	"sort"
 	"strings"
 	"time"
+	"unicode/utf8"
 )
 
+const (
+	ProviderClaude     = "claude"
+	ProviderChatGPT    = "chatgpt"
+	ProviderGemini     = "gemini"
+	ProviderCopilot    = "copilot"
+	ProviderPerplexity = "perplexity"
+	ProviderOpenAI     = "openai"
+	ProviderAnthropic  = "anthropic"
+)
+
+// timestampLayouts are the textual timestamps accepted from exports that do not use
+// RFC 3339, such as spreadsheet-style CSV dates. Values without a zone are read as UTC.
+var timestampLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05Z07:00",
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04",
+	"1/2/2006 3:04:05 PM",
+	"1/2/2006 3:04 PM",
+	"1/2/2006 15:04:05",
+	"1/2/2006 15:04",
+	"2006-01-02",
+}
+
+// conversationNameMaxRunes bounds names derived from a prompt when an export has no title.
+const conversationNameMaxRunes = 80
+
+// sniffLength is how much of a document is inspected to pick a document parser.
+const sniffLength = 512
+
+var utf8BOM = []byte{0xef, 0xbb, 0xbf}
+
 type ConversationEntry struct {
+	Provider              string `json:"provider,omitempty"`
 	ConversationID        string `json:"conversationId"`
 	ConversationName      string `json:"conversationName"`
 	ConversationCreatedAt string `json:"conversationCreatedAt"`
-	Speaker               string `json:"speaker"`
-	Message               string `json:"message"`
-	MessageTimestamp      string `json:"messageTimestamp"`
+	// ConversationTitle is the generated title of an untitled conversation.
+	ConversationTitle string `json:"conversationTitle,omitempty"`
+	// MessageIndex is the message's position in its conversation, counting every kind.
+	MessageIndex     int    `json:"messageIndex"`
+	Speaker          string `json:"speaker"`
+	Message          string `json:"message"`
+	MessageTimestamp string `json:"messageTimestamp"`
+
+	Kind          string     `json:"kind,omitempty"`
+	Recipient     string     `json:"recipient,omitempty"`
+	ToolName      string     `json:"toolName,omitempty"`
+	Model         string     `json:"model,omitempty"`
+	FinishReason  string     `json:"finishReason,omitempty"`
+	Citations     []Citation `json:"citations,omitempty"`
+	SearchQueries []string   `json:"searchQueries,omitempty"`
+	TokenCount    int        `json:"tokenCount,omitempty"`
+}
+
+// ParseWarning describes a conversation that was skipped while parsing in lenient mode.
+type ParseWarning struct {
+	Index          int    `json:"index"`
+	ConversationID string `json:"conversationId"`
+	Message        string `json:"message"`
+	Err            error  `json:"-"`
+}
+
+// ParseOptions controls how strictly an export is parsed.
+type ParseOptions struct {
+	// Lenient skips malformed conversations and records a ParseWarning for each one
+	// instead of aborting the whole load.
+	Lenient bool
+}
+
+// ParseResult holds the parsed conversations and any warnings collected in lenient mode.
+type ParseResult struct {
+	Conversations []Conversation
+	Warnings      []ParseWarning
+}
+
+// Entries flattens the parsed conversations into per-message rows.
+func (r ParseResult) Entries() []ConversationEntry {
+	return FlattenConversations(r.Conversations)
 }
 
 type rawConversation struct {
@@ -27,10 +104,19 @@ type rawConversation struct {
 }
 
 type rawChatMessage struct {
-	Sender    string `json:"sender"`
-	Text      string `json:"text"`
-	Content   any    `json:"content"`
-	CreatedAt string `json:"created_at"`
+	Sender      string                `json:"sender"`
+	Text        string                `json:"text"`
+	Content     any                   `json:"content"`
+	CreatedAt   string                `json:"created_at"`
+	Attachments []rawClaudeAttachment `json:"attachments"`
+}
+
+// rawClaudeAttachment is a file pasted or uploaded into a Claude message; text files
+// carry their contents as extracted_content.
+type rawClaudeAttachment struct {
+	FileName         string `json:"file_name"`
+	FileType         string `json:"file_type"`
+	ExtractedContent string `json:"extracted_content"`
 }
 
 type rawChatGPTConversation struct {
@@ -55,64 +141,256 @@ type rawChatGPTMessage struct {
 	Content    rawChatGPTContent `json:"content"`
 	Metadata   map[string]any    `json:"metadata"`
 	Channel    *string           `json:"channel"`
+	Recipient  string            `json:"recipient"`
 }
 
 type rawChatGPTAuthor struct {
-	Role string `json:"role"`
+	Role string  `json:"role"`
+	Name *string `json:"name"`
 }
 
 type rawChatGPTContent struct {
-	ContentType string `json:"content_type"`
-	Parts       []any  `json:"parts"`
-	Text        string `json:"text"`
+	ContentType string              `json:"content_type"`
+	Parts       []any               `json:"parts"`
+	Text        string              `json:"text"`
+	Content     any                 `json:"content"`
+	Thoughts    []rawChatGPTThought `json:"thoughts"`
+	// Language is set on "code" content, e.g. code sent to the python tool.
+	Language string `json:"language"`
+	// UserProfile and UserInstructions are set on "user_editable_context" messages.
+	UserProfile      string `json:"user_profile"`
+	UserInstructions string `json:"user_instructions"`
+}
+
+// rawChatGPTThought is one step of a reasoning model's "thoughts" content.
+type rawChatGPTThought struct {
+	Summary string `json:"summary"`
+	Content string `json:"content"`
 }
 
 func ParseConversationsJSON(input io.Reader) ([]ConversationEntry, error) {
-	var rawConversations []json.RawMessage
+	result, err := ParseConversationsJSONWithOptions(input, ParseOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	return result.Entries(), nil
+}
 
-	decoder := json.NewDecoder(input)
-	if err := decoder.Decode(&rawConversations); err != nil {
-		return nil, fmt.Errorf("decode conversations json: %w", err)
+// ParseConversations parses an export document, choosing the document parser from
+// its first significant byte: HTML for Gemini "My Activity" pages, JSON for arrays and
+// objects, and CSV for delimited text.
+func ParseConversations(input io.Reader, options ParseOptions) (ParseResult, error) {
+	reader := bufio.NewReader(input)
+	if header, _ := reader.Peek(len(utf8BOM)); bytes.Equal(header, utf8BOM) {
+		if _, err := reader.Discard(len(utf8BOM)); err != nil {
+			return ParseResult{}, fmt.Errorf("skip byte order mark: %w", err)
+		}
 	}
 
-	entries := make([]ConversationEntry, 0, len(rawConversations))
+	switch firstByte := peekFirstSignificantByte(reader); {
+	case firstByte == '<':
+		conversations, err := parseGeminiActivityHTML(reader)
+		if err != nil {
+			return ParseResult{}, err
+		}
+		return ParseResult{Conversations: appendVisibleConversations(nil, conversations), Warnings: []ParseWarning{}}, nil
+	case firstByte != '[' && firstByte != '{' && looksLikeCSV(reader):
+		return parseConversationsCSV(reader, options)
+	}
+
+	return ParseConversationsJSONWithOptions(reader, options)
+}
+
+func peekFirstSignificantByte(reader *bufio.Reader) byte {
+	header, _ := reader.Peek(sniffLength)
+	trimmedHeader := bytes.TrimLeft(header, " \t\r\n")
+	if len(trimmedHeader) == 0 {
+		return 0
+	}
+
+	return trimmedHeader[0]
+}
+
+func ParseConversationsJSONWithOptions(input io.Reader, options ParseOptions) (ParseResult, error) {
+	data, err := io.ReadAll(input)
+	if err != nil {
+		return ParseResult{}, fmt.Errorf("read conversations json: %w", err)
+	}
+
+	rawConversations, err := decodeConversationRecords(data)
+	if err != nil {
+		if !options.Lenient || !isJSONLines(data) {
+			return ParseResult{}, fmt.Errorf("decode conversations json: %w", err)
+		}
+		// A JSONL log with a corrupt line: keep each line as its own record so the
+		// corrupt ones surface as warnings below instead of discarding the file.
+		rawConversations = splitJSONLines(data)
+	}
+
+	result := ParseResult{
+		Conversations: make([]Conversation, 0, len(rawConversations)),
+		Warnings:      []ParseWarning{},
+	}
 	for index, rawConversationJSON := range rawConversations {
-		conversationEntries, err := parseConversation(rawConversationJSON)
+		conversations, err := parseConversation(rawConversationJSON)
+		if err != nil {
+			if !options.Lenient {
+				return ParseResult{}, fmt.Errorf("parse conversation at index %d: %w", index, err)
+			}
+
+			result.Warnings = append(result.Warnings, ParseWarning{
+				Index:          index,
+				ConversationID: extractRawConversationID(rawConversationJSON),
+				Message:        err.Error(),
+				Err:            err,
+			})
+			continue
+		}
+		result.Conversations = appendVisibleConversations(result.Conversations, conversations)
+	}
+
+	return result, nil
+}
+
+// conversationsWrapperKeys name the array holding the records when an export wraps
+// them in an object, e.g. {"conversations": [...]}.
+var conversationsWrapperKeys = []string{"conversations", "threads"}
+
+// decodeConversationRecords returns the conversation records of a JSON document or of
+// a stream of documents such as JSONL, where every line is a record.
+func decodeConversationRecords(data []byte) ([]json.RawMessage, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	records := make([]json.RawMessage, 0, 16)
+	for {
+		var document json.RawMessage
+		if err := decoder.Decode(&document); err != nil {
+			if errors.Is(err, io.EOF) && len(records) > 0 {
+				return records, nil
+			}
+			return nil, err
+		}
+
+		documentRecords, err := documentConversationRecords(document)
 		if err != nil {
-			return nil, fmt.Errorf("parse conversation at index %d: %w", index, err)
+			return nil, err
+		}
+		records = append(records, documentRecords...)
+	}
+}
+
+// documentConversationRecords returns the records of one JSON document: the elements
+// of a top-level array, the array under a wrapper key, or the object itself.
+func documentConversationRecords(document json.RawMessage) ([]json.RawMessage, error) {
+	if trimmedDocument := bytes.TrimLeft(document, " \t\r\n"); len(trimmedDocument) > 0 && trimmedDocument[0] == '{' {
+		var fields map[string]json.RawMessage
+		if err := json.Unmarshal(document, &fields); err != nil {
+			return nil, err
+		}
+		for _, key := range conversationsWrapperKeys {
+			var records []json.RawMessage
+			if err := json.Unmarshal(fields[key], &records); err == nil && records != nil {
+				return records, nil
+			}
 		}
-		entries = append(entries, conversationEntries...)
+
+		return []json.RawMessage{document}, nil
+	}
+
+	var records []json.RawMessage
+	if err := json.Unmarshal(document, &records); err != nil {
+		return nil, err
 	}
 
-	return entries, nil
+	return records, nil
+}
+
+// isJSONLines reports whether data looks like JSONL: several lines, the first of which
+// is a complete JSON value. A pretty-printed document fails the second check.
+func isJSONLines(data []byte) bool {
+	firstLine, rest, hasMoreLines := bytes.Cut(bytes.TrimSpace(data), []byte("\n"))
+	return hasMoreLines && len(bytes.TrimSpace(rest)) > 0 && json.Valid(firstLine)
 }
 
-func parseConversation(rawConversationJSON json.RawMessage) ([]ConversationEntry, error) {
+// splitJSONLines returns the non-blank lines of a JSONL document as records. Lines
+// that are not valid JSON are kept as-is so parsing reports them individually.
+func splitJSONLines(data []byte) []json.RawMessage {
+	lines := bytes.Split(data, []byte("\n"))
+	records := make([]json.RawMessage, 0, len(lines))
+	for _, line := range lines {
+		line = bytes.TrimSpace(line)
+		if len(line) == 0 {
+			continue
+		}
+
+		lineRecords, err := documentConversationRecords(line)
+		if err != nil {
+			records = append(records, json.RawMessage(line))
+			continue
+		}
+		records = append(records, lineRecords...)
+	}
+
+	return records
+}
+
+// extractRawConversationID makes a best-effort attempt to identify a conversation
+// that failed to parse, so warnings can point at the offending record.
+func extractRawConversationID(rawConversationJSON json.RawMessage) string {
 	var conversationFields map[string]json.RawMessage
 	if err := json.Unmarshal(rawConversationJSON, &conversationFields); err != nil {
-		return nil, fmt.Errorf("decode conversation: %w", err)
+		return ""
 	}
 
-	if _, hasMapping := conversationFields["mapping"]; hasMapping {
-		var conversation rawChatGPTConversation
-		if err := json.Unmarshal(rawConversationJSON, &conversation); err != nil {
-			return nil, fmt.Errorf("decode chatgpt conversation: %w", err)
+	for _, key := range []string{"conversation_id", "conversationId", "uuid", "custom_id", "id"} {
+		var conversationID string
+		if err := json.Unmarshal(conversationFields[key], &conversationID); err == nil {
+			if trimmedID := strings.TrimSpace(conversationID); trimmedID != "" {
+				return trimmedID
+			}
 		}
-		return parseChatGPTConversation(conversation), nil
 	}
 
-	var conversation rawConversation
-	if err := json.Unmarshal(rawConversationJSON, &conversation); err != nil {
-		return nil, fmt.Errorf("decode claude conversation: %w", err)
+	return ""
+}
+
+func parseConversation(rawConversationJSON json.RawMessage) ([]Conversation, error) {
+	var conversationFields map[string]json.RawMessage
+	if err := json.Unmarshal(rawConversationJSON, &conversationFields); err != nil {
+		return nil, fmt.Errorf("decode conversation: %w", err)
 	}
 
-	return parseClaudeConversation(conversation), nil
+	return detectFormat(conversationFields).Parse(rawConversationJSON)
 }
 
-func parseClaudeConversation(conversation rawConversation) []ConversationEntry {
-	conversationCreatedAt := resolveClaudeConversationCreatedAt(conversation)
+// appendVisibleConversations drops conversations without any renderable message,
+// matching what the frontend can show, classifies messages a format left unkinded and
+// titles untitled conversations.
+func appendVisibleConversations(conversations []Conversation, candidates []Conversation) []Conversation {
+	for _, candidate := range candidates {
+		if len(candidate.Messages) == 0 {
+			continue
+		}
+		candidate.TokenCount = 0
+		for index := range candidate.Messages {
+			message := &candidate.Messages[index]
+			if message.Kind == "" {
+				message.Kind = defaultMessageKind(message.Speaker)
+			}
+			message.TokenCount = estimateMessageTokens(candidate.Provider, *message)
+			candidate.TokenCount += message.TokenCount
+		}
+		if IsGenericConversationTitle(candidate.Name) {
+			candidate.GeneratedTitle = GenerateConversationTitle(candidate.Messages)
+		}
+		conversations = append(conversations, candidate)
+	}
 
-	entries := make([]ConversationEntry, 0, len(conversation.ChatMessages))
+	return conversations
+}
+
+func parseClaudeConversation(conversation rawConversation) Conversation {
+	messages := make([]Message, 0, len(conversation.ChatMessages))
 	for _, chatMessage := range conversation.ChatMessages {
 		message := extractMessageText(chatMessage)
 		if message == "" {
@@ -124,43 +402,51 @@ func parseClaudeConversation(conversation rawConversation) []ConversationEntry {
 			speaker = "unknown"
 		}
 
-		entries = append(entries, ConversationEntry{
-			ConversationID:        conversation.UUID,
-			ConversationName:      conversation.Name,
-			ConversationCreatedAt: conversationCreatedAt,
-			Speaker:               speaker,
-			Message:               message,
-			MessageTimestamp:      extractMessageTimestamp(chatMessage),
+		messages = append(messages, Message{
+			Speaker:     speaker,
+			Text:        message,
+			Timestamp:   extractMessageTimestamp(chatMessage),
+			Attachments: extractClaudeAttachments(chatMessage),
 		})
 	}
 
-	return entries
+	return Conversation{
+		Provider:  ProviderClaude,
+		ID:        conversation.UUID,
+		Name:      conversation.Name,
+		CreatedAt: resolveClaudeConversationCreatedAt(conversation),
+		Messages:  messages,
+	}
 }
 
-func parseChatGPTConversation(conversation rawChatGPTConversation) []ConversationEntry {
+func parseChatGPTConversation(conversation rawChatGPTConversation) Conversation {
 	selectedNodeIDs := selectedChatGPTNodeIDs(conversation)
-	entries, oldestMessageTimestamp := parseChatGPTConversationEntries(conversation, selectedNodeIDs)
+	messages, oldestMessageTimestamp := parseChatGPTMessages(conversation, selectedNodeIDs)
 
 	// Older exports can self-reference current_node to a non-message root node.
 	// If current-node traversal yields no renderable entries, fall back to full graph walk.
-	if len(entries) == 0 && strings.TrimSpace(conversation.CurrentNode) != "" {
+	if len(messages) == 0 && strings.TrimSpace(conversation.CurrentNode) != "" {
 		fallbackNodeIDs := collectNodeIDsFromRoots(conversation.Mapping)
-		entries, oldestMessageTimestamp = parseChatGPTConversationEntries(conversation, fallbackNodeIDs)
+		messages, oldestMessageTimestamp = parseChatGPTMessages(conversation, fallbackNodeIDs)
 	}
 
 	conversationCreatedAt := formatUnixTimestamp(conversation.CreateTime)
 	if conversationCreatedAt == "" {
 		conversationCreatedAt = oldestMessageTimestamp
 	}
-	for index := range entries {
-		entries[index].ConversationCreatedAt = conversationCreatedAt
-	}
 
-	return entries
+	return Conversation{
+		Provider:      ProviderChatGPT,
+		ID:            strings.TrimSpace(conversation.ConversationID),
+		Name:          conversation.Title,
+		CreatedAt:     conversationCreatedAt,
+		Messages:      messages,
+		SystemContext: extractChatGPTSystemContext(conversation, selectedNodeIDs),
+	}
 }
 
-func parseChatGPTConversationEntries(conversation rawChatGPTConversation, nodeIDs []string) ([]ConversationEntry, string) {
-	entries := make([]ConversationEntry, 0, len(nodeIDs))
+func parseChatGPTMessages(conversation rawChatGPTConversation, nodeIDs []string) ([]Message, string) {
+	messages := make([]Message, 0, len(nodeIDs))
 	oldestMessageTimestamp := ""
 	for _, nodeID := range nodeIDs {
 		node, exists := conversation.Mapping[nodeID]
@@ -168,16 +454,16 @@ func parseChatGPTConversationEntries(conversation rawChatGPTConversation, nodeID
 			continue
 		}
 
-		entry, includeEntry := toChatGPTConversationEntry(conversation, node)
-		if !includeEntry {
+		message, includeMessage := toChatGPTMessage(conversation, node)
+		if !includeMessage {
 			continue
 		}
 
-		oldestMessageTimestamp = olderTimestamp(oldestMessageTimestamp, entry.MessageTimestamp)
-		entries = append(entries, entry)
+		oldestMessageTimestamp = olderTimestamp(oldestMessageTimestamp, message.Timestamp)
+		messages = append(messages, message)
 	}
 
-	return entries, oldestMessageTimestamp
+	return messages, oldestMessageTimestamp
 }
 
 func selectedChatGPTNodeIDs(conversation rawChatGPTConversation) []string {
@@ -294,17 +580,17 @@ func collectNodeIDsFromRoots(mapping map[string]rawChatGPTNode) []string {
 	return orderedNodeIDs
 }
 
-func toChatGPTConversationEntry(conversation rawChatGPTConversation, node rawChatGPTNode) (ConversationEntry, bool) {
+func toChatGPTMessage(conversation rawChatGPTConversation, node rawChatGPTNode) (Message, bool) {
 	if node.Message == nil {
-		return ConversationEntry{}, false
+		return Message{}, false
 	}
 	if isChatGPTMessageHidden(node.Message.Metadata) {
-		return ConversationEntry{}, false
+		return Message{}, false
 	}
 
 	message := extractChatGPTMessageText(node.Message.Content)
 	if message == "" {
-		return ConversationEntry{}, false
+		return Message{}, false
 	}
 
 	speaker := strings.TrimSpace(node.Message.Author.Role)
@@ -312,16 +598,38 @@ func toChatGPTConversationEntry(conversation rawChatGPTConversation, node rawCha
 		speaker = "unknown"
 	}
 
-	return ConversationEntry{
-		ConversationID:        strings.TrimSpace(conversation.ConversationID),
-		ConversationName:      conversation.Title,
-		ConversationCreatedAt: "",
-		Speaker:               speaker,
-		Message:               message,
-		MessageTimestamp:      resolveChatGPTMessageTimestamp(conversation, node),
+	metadata := node.Message.Metadata
+	kind, recipient, toolName := classifyChatGPTMessage(*node.Message)
+	return Message{
+		Speaker:       speaker,
+		Text:          message,
+		Timestamp:     resolveChatGPTMessageTimestamp(conversation, node),
+		Kind:          kind,
+		Recipient:     recipient,
+		ToolName:      toolName,
+		Model:         firstNonBlank(metadataString(metadata, "resolved_model_slug"), metadataString(metadata, "model_slug")),
+		FinishReason:  extractChatGPTFinishReason(metadata),
+		Citations:     extractChatGPTCitations(metadata),
+		SearchQueries: extractChatGPTSearchQueries(metadata),
+		CodeLanguage:  chatGPTCodeLanguage(*node.Message),
 	}, true
 }
 
+// chatGPTCodeLanguage returns the language of "code" content. ChatGPT often records
+// "unknown" for code sent to the python tool, so the recipient fills in the language.
+func chatGPTCodeLanguage(message rawChatGPTMessage) string {
+	if message.Content.ContentType != chatGPTCodeContentType {
+		return ""
+	}
+
+	language := strings.TrimSpace(message.Content.Language)
+	if (language == "" || strings.EqualFold(language, "unknown")) && strings.EqualFold(strings.TrimSpace(message.Recipient), "python") {
+		return "python"
+	}
+
+	return firstNonBlank(language, "unknown")
+}
+
 func isChatGPTMessageHidden(metadata map[string]any) bool {
 	if metadata == nil {
 		return false
@@ -336,6 +644,152 @@ func isChatGPTMessageHidden(metadata map[string]any) bool {
 	return ok && isHidden
 }
 
+const chatGPTCodeContentType = "code"
+
+// chatGPTReasoningContentTypes hold a reasoning model's thinking rather than an answer.
+var chatGPTReasoningContentTypes = map[string]bool{"thoughts": true, "reasoning_recap": true}
+
+// classifyChatGPTMessage returns the message kind, plus the recipient of a tool call
+// or the name of the tool that produced a tool result. Assistant messages addressed
+// to anyone but "all" are tool calls (web.run, python, dalle.text2im, ...), and the
+// "analysis" channel carries reasoning.
+func classifyChatGPTMessage(message rawChatGPTMessage) (string, string, string) {
+	role := strings.ToLower(strings.TrimSpace(message.Author.Role))
+	recipient := strings.TrimSpace(message.Recipient)
+	channel := ""
+	if message.Channel != nil {
+		channel = strings.TrimSpace(*message.Channel)
+	}
+
+	switch role {
+	case "user":
+		return MessageKindPrompt, "", ""
+	case "system":
+		return MessageKindSystem, "", ""
+	case "tool":
+		toolName := ""
+		if message.Author.Name != nil {
+			toolName = strings.TrimSpace(*message.Author.Name)
+		}
+		return MessageKindToolResult, "", toolName
+	case "assistant":
+		if recipient != "" && recipient != "all" {
+			return MessageKindToolCall, recipient, ""
+		}
+		if channel == "analysis" || chatGPTReasoningContentTypes[message.Content.ContentType] {
+			return MessageKindReasoning, "", ""
+		}
+		return MessageKindAnswer, "", ""
+	default:
+		return "", "", ""
+	}
+}
+
+func extractChatGPTFinishReason(metadata map[string]any) string {
+	if finishDetails, ok := metadata["finish_details"].(map[string]any); ok {
+		return metadataString(finishDetails, "type")
+	}
+
+	return ""
+}
+
+// extractChatGPTCitations returns the sources of a browsing answer. Citations
+// usually nest the page under "metadata"; older exports put title and url inline.
+func extractChatGPTCitations(metadata map[string]any) []Citation {
+	rawCitations, _ := metadata["citations"].([]any)
+	rawReferences, _ := metadata["content_references"].([]any)
+	citations := make([]Citation, 0, len(rawCitations)+len(rawReferences))
+	seenURLs := make(map[string]struct{}, len(rawCitations)+len(rawReferences))
+	addCitation := func(title string, url string) {
+		if url == "" {
+			return
+		}
+		if _, alreadySeen := seenURLs[url]; alreadySeen {
+			return
+		}
+		seenURLs[url] = struct{}{}
+		citations = append(citations, Citation{Title: title, URL: url})
+	}
+
+	for _, rawCitation := range rawCitations {
+		citationFields, ok := rawCitation.(map[string]any)
+		if !ok {
+			continue
+		}
+		if nestedFields, hasNested := citationFields["metadata"].(map[string]any); hasNested {
+			citationFields = nestedFields
+		}
+		addCitation(metadataString(citationFields, "title"), metadataString(citationFields, "url"))
+	}
+
+	// Newer exports record web results as content_references: a reference may carry a
+	// url itself, group pages under items (each with supporting_websites), or list bare
+	// safe_urls.
+	for _, rawReference := range rawReferences {
+		referenceFields, ok := rawReference.(map[string]any)
+		if !ok {
+			continue
+		}
+		addCitation(metadataString(referenceFields, "title"), metadataString(referenceFields, "url"))
+
+		items, _ := referenceFields["items"].([]any)
+		for _, rawItem := range items {
+			itemFields, isObject := rawItem.(map[string]any)
+			if !isObject {
+				continue
+			}
+			addCitation(metadataString(itemFields, "title"), metadataString(itemFields, "url"))
+
+			supportingWebsites, _ := itemFields["supporting_websites"].([]any)
+			for _, rawWebsite := range supportingWebsites {
+				if websiteFields, isWebsite := rawWebsite.(map[string]any); isWebsite {
+					addCitation(metadataString(websiteFields, "title"), metadataString(websiteFields, "url"))
+				}
+			}
+		}
+
+		safeURLs, _ := referenceFields["safe_urls"].([]any)
+		for _, rawURL := range safeURLs {
+			if url, isString := rawURL.(string); isString {
+				addCitation("", strings.TrimSpace(url))
+			}
+		}
+	}
+	if len(citations) == 0 {
+		return nil
+	}
+
+	return citations
+}
+
+// extractChatGPTSearchQueries accepts both {"q": "..."} objects and plain strings.
+func extractChatGPTSearchQueries(metadata map[string]any) []string {
+	rawQueries, _ := metadata["search_queries"].([]any)
+	queries := make([]string, 0, len(rawQueries))
+	for _, rawQuery := range rawQueries {
+		query := ""
+		switch value := rawQuery.(type) {
+		case string:
+			query = strings.TrimSpace(value)
+		case map[string]any:
+			query = metadataString(value, "q")
+		}
+		if query != "" {
+			queries = append(queries, query)
+		}
+	}
+	if len(queries) == 0 {
+		return nil
+	}
+
+	return queries
+}
+
+func metadataString(metadata map[string]any, key string) string {
+	value, _ := metadata[key].(string)
+	return strings.TrimSpace(value)
+}
+
 func extractChatGPTMessageText(content rawChatGPTContent) string {
 	parts := make([]string, 0, len(content.Parts))
 	for _, part := range content.Parts {
@@ -347,6 +801,13 @@ func extractChatGPTMessageText(content rawChatGPTContent) string {
 			parts = append(parts, fallbackText)
 		}
 	}
+	if len(parts) == 0 && chatGPTReasoningContentTypes[content.ContentType] {
+		for _, thought := range content.Thoughts {
+			collectText(strings.TrimSpace(thought.Summary+"\n"+thought.Content), &parts)
+		}
+		// reasoning_recap stores its "Thought for 12s" line as a plain string.
+		collectText(content.Content, &parts)
+	}
 
 	return strings.Join(parts, "\n")
 }
@@ -423,6 +884,26 @@ func extractMessageText(chatMessage rawChatMessage) string {
 	return strings.Join(parts, "\n")
 }
 
+func extractClaudeAttachments(chatMessage rawChatMessage) []Attachment {
+	attachments := make([]Attachment, 0, len(chatMessage.Attachments))
+	for _, rawAttachment := range chatMessage.Attachments {
+		name := strings.TrimSpace(rawAttachment.FileName)
+		if name == "" && rawAttachment.ExtractedContent == "" {
+			continue
+		}
+		attachments = append(attachments, Attachment{
+			Name:     name,
+			FileType: strings.TrimSpace(rawAttachment.FileType),
+			Content:  rawAttachment.ExtractedContent,
+		})
+	}
+	if len(attachments) == 0 {
+		return nil
+	}
+
+	return attachments
+}
+
 func extractMessageTimestamp(chatMessage rawChatMessage) string {
 	return strings.TrimSpace(chatMessage.CreatedAt)
 }
@@ -440,6 +921,88 @@ func resolveClaudeConversationCreatedAt(conversation rawConversation) string {
 	return oldestMessageTimestamp
 }
 
+// normalizeTimestamp converts a recognized timestamp to RFC 3339 in UTC and returns
+// anything else trimmed but unchanged, so no information is lost.
+func normalizeTimestamp(value string) string {
+	if parsedTime, ok := parseTimestamp(value); ok {
+		return parsedTime.UTC().Format(time.RFC3339Nano)
+	}
+
+	return strings.TrimSpace(value)
+}
+
+// parseTimestamp reads a timestamp in any of timestampLayouts.
+func parseTimestamp(value string) (time.Time, bool) {
+	trimmedValue := strings.TrimSpace(value)
+	if trimmedValue == "" {
+		return time.Time{}, false
+	}
+
+	for _, layout := range timestampLayouts {
+		if parsedTime, err := time.Parse(layout, trimmedValue); err == nil {
+			return parsedTime, true
+		}
+	}
+
+	return time.Time{}, false
+}
+
+// normalizeAssistantSpeaker maps the many names providers use for the two sides of a
+// chat onto "user" and "assistant", keeping unrecognized speakers as they are.
+func normalizeAssistantSpeaker(speaker string) string {
+	trimmedSpeaker := strings.TrimSpace(speaker)
+	switch strings.ToLower(trimmedSpeaker) {
+	case "":
+		return "unknown"
+	case "user", "human", "me":
+		return "user"
+	case "assistant", "bot", "ai", "copilot", "model":
+		return "assistant"
+	default:
+		return trimmedSpeaker
+	}
+}
+
+// resolveConversationName prefers an explicit title and falls back to the first
+// non-system message, for exports that leave untitled conversations blank.
+func resolveConversationName(title string, messages []Message) string {
+	if trimmedTitle := strings.TrimSpace(title); trimmedTitle != "" {
+		return trimmedTitle
+	}
+	for _, message := range messages {
+		if message.Speaker != "system" && message.Speaker != "developer" {
+			return conversationNameFromPrompt(message.Text)
+		}
+	}
+	if len(messages) == 0 {
+		return ""
+	}
+
+	return conversationNameFromPrompt(messages[0].Text)
+}
+
+func firstNonBlank(values ...string) string {
+	for _, value := range values {
+		if trimmedValue := strings.TrimSpace(value); trimmedValue != "" {
+			return trimmedValue
+		}
+	}
+
+	return ""
+}
+
+// conversationNameFromPrompt derives a conversation name from the first line of a prompt.
+func conversationNameFromPrompt(prompt string) string {
+	firstLine, _, _ := strings.Cut(strings.TrimSpace(prompt), "\n")
+	firstLine = strings.TrimSpace(firstLine)
+	if utf8.RuneCountInString(firstLine) <= conversationNameMaxRunes {
+		return firstLine
+	}
+
+	runes := []rune(firstLine)
+	return strings.TrimSpace(string(runes[:conversationNameMaxRunes])) + "…"
+}
+
 func olderTimestamp(current string, candidate string) string {
 	trimmedCandidate := strings.TrimSpace(candidate)
 	if trimmedCandidate == "" {
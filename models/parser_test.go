@@ -1,6 +1,7 @@
 package models
 
 import (
+	"reflect"
 	"strings"
 	"testing"
 )
@@ -40,7 +41,7 @@ func TestParseConversationsJSON(t *testing.T) {
 				}
 			]`,
 			wantEntries: []ConversationEntry{
-				entry("precedence", "Precedence", "bot", "Top level text", ""),
+				parsedEntry(entry("precedence", "Precedence", "bot", "Top level text", ""), ProviderClaude, MessageKindAnswer, 0, 4),
 			},
 		},
 		{
@@ -67,7 +68,7 @@ func TestParseConversationsJSON(t *testing.T) {
 				}
 			]`,
 			wantEntries: []ConversationEntry{
-				entry("edge", "Edge Cases", "unknown", "Who sent this?", "2026-01-01T00:00:00Z"),
+				parsedEntry(entry("edge", "Edge Cases", "unknown", "Who sent this?", "2026-01-01T00:00:00Z"), ProviderClaude, "", 0, 5),
 			},
 		},
 		{
@@ -91,8 +92,8 @@ func TestParseConversationsJSON(t *testing.T) {
 				}
 			]`,
 			wantEntries: []ConversationEntry{
-				entry("with-timestamps", "Timeline", "human", "Question", "2026-01-02T10:00:00Z"),
-				entry("with-timestamps", "Timeline", "assistant", "Answer", "2026-01-02T10:00:42Z"),
+				parsedEntry(entry("with-timestamps", "Timeline", "human", "Question", "2026-01-02T10:00:00Z"), ProviderClaude, MessageKindPrompt, 0, 2),
+				parsedEntry(entry("with-timestamps", "Timeline", "assistant", "Answer", "2026-01-02T10:00:42Z"), ProviderClaude, MessageKindAnswer, 1, 2),
 			},
 		},
 		{
@@ -117,8 +118,8 @@ func TestParseConversationsJSON(t *testing.T) {
 				}
 			]`,
 			wantEntries: []ConversationEntry{
-				entryWithCreatedAt("claude-created", "Timeline", "2026-01-01T12:00:00Z", "human", "Question", "2026-01-02T10:00:00Z"),
-				entryWithCreatedAt("claude-created", "Timeline", "2026-01-01T12:00:00Z", "assistant", "Answer", "2026-01-02T10:00:42Z"),
+				parsedEntry(entryWithCreatedAt("claude-created", "Timeline", "2026-01-01T12:00:00Z", "human", "Question", "2026-01-02T10:00:00Z"), ProviderClaude, MessageKindPrompt, 0, 2),
+				parsedEntry(entryWithCreatedAt("claude-created", "Timeline", "2026-01-01T12:00:00Z", "assistant", "Answer", "2026-01-02T10:00:42Z"), ProviderClaude, MessageKindAnswer, 1, 2),
 			},
 		},
 		{
@@ -142,8 +143,8 @@ func TestParseConversationsJSON(t *testing.T) {
 				}
 			]`,
 			wantEntries: []ConversationEntry{
-				entryWithCreatedAt("claude-fallback-created", "Timeline", "2026-01-02T10:00:00Z", "assistant", "Answer", "2026-01-02T10:00:42Z"),
-				entryWithCreatedAt("claude-fallback-created", "Timeline", "2026-01-02T10:00:00Z", "human", "Question", "2026-01-02T10:00:00Z"),
+				parsedEntry(entryWithCreatedAt("claude-fallback-created", "Timeline", "2026-01-02T10:00:00Z", "assistant", "Answer", "2026-01-02T10:00:42Z"), ProviderClaude, MessageKindAnswer, 0, 2),
+				parsedEntry(entryWithCreatedAt("claude-fallback-created", "Timeline", "2026-01-02T10:00:00Z", "human", "Question", "2026-01-02T10:00:00Z"), ProviderClaude, MessageKindPrompt, 1, 2),
 			},
 		},
 		{
@@ -163,7 +164,7 @@ func TestParseConversationsJSON(t *testing.T) {
 				}
 			]`,
 			wantEntries: []ConversationEntry{
-				entry("valid-conv", "Valid", "me", "Hello", ""),
+				parsedEntry(entry("valid-conv", "Valid", "me", "Hello", ""), ProviderClaude, "", 0, 2),
 			},
 		},
 		{
@@ -231,9 +232,9 @@ func TestParseConversationsJSON(t *testing.T) {
 				}
 			]`,
 			wantEntries: []ConversationEntry{
-				entry("cgpt-1", "ChatGPT One", "user", "Hello from user", "2023-11-14T22:13:21Z"),
-				entry("cgpt-1", "ChatGPT One", "assistant", "Hello from assistant", "2023-11-14T22:13:22Z"),
-				entry("cgpt-1", "ChatGPT One", "tool", "tool output", "2023-11-14T22:13:23Z"),
+				parsedEntry(entry("cgpt-1", "ChatGPT One", "user", "Hello from user", "2023-11-14T22:13:21Z"), ProviderChatGPT, MessageKindPrompt, 0, 3),
+				parsedEntry(entry("cgpt-1", "ChatGPT One", "assistant", "Hello from assistant", "2023-11-14T22:13:22Z"), ProviderChatGPT, MessageKindAnswer, 1, 3),
+				{Provider: ProviderChatGPT, ConversationID: "cgpt-1", ConversationName: "ChatGPT One", MessageIndex: 2, Speaker: "tool", Message: "tool output", MessageTimestamp: "2023-11-14T22:13:23Z", Kind: MessageKindToolResult, ToolName: "web.run", TokenCount: 2},
 			},
 		},
 		{
@@ -266,7 +267,7 @@ func TestParseConversationsJSON(t *testing.T) {
 				}
 			]`,
 			wantEntries: []ConversationEntry{
-				entryWithCreatedAt("cgpt-created", "ChatGPT Created", "2023-11-14T22:30:00Z", "assistant", "answer", "2023-11-14T22:30:01Z"),
+				parsedEntry(entryWithCreatedAt("cgpt-created", "ChatGPT Created", "2023-11-14T22:30:00Z", "assistant", "answer", "2023-11-14T22:30:01Z"), ProviderChatGPT, MessageKindAnswer, 0, 1),
 			},
 		},
 		{
@@ -309,8 +310,8 @@ func TestParseConversationsJSON(t *testing.T) {
 				}
 			]`,
 			wantEntries: []ConversationEntry{
-				entryWithCreatedAt("cgpt-fallback-created", "ChatGPT Missing Created", "2023-11-14T22:46:40Z", "user", "question", "2023-11-14T22:46:40Z"),
-				entryWithCreatedAt("cgpt-fallback-created", "ChatGPT Missing Created", "2023-11-14T22:46:40Z", "assistant", "answer", "2023-11-14T22:46:42Z"),
+				parsedEntry(entryWithCreatedAt("cgpt-fallback-created", "ChatGPT Missing Created", "2023-11-14T22:46:40Z", "user", "question", "2023-11-14T22:46:40Z"), ProviderChatGPT, MessageKindPrompt, 0, 1),
+				parsedEntry(entryWithCreatedAt("cgpt-fallback-created", "ChatGPT Missing Created", "2023-11-14T22:46:40Z", "assistant", "answer", "2023-11-14T22:46:42Z"), ProviderChatGPT, MessageKindAnswer, 1, 1),
 			},
 		},
 		{
@@ -343,7 +344,7 @@ func TestParseConversationsJSON(t *testing.T) {
 				}
 			]`,
 			wantEntries: []ConversationEntry{
-				entryWithCreatedAt("cgpt-self-ref", "Self Ref Root", "2023-11-14T23:03:20Z", "user", "older export message", "2023-11-14T23:03:21Z"),
+				parsedEntry(entryWithCreatedAt("cgpt-self-ref", "Self Ref Root", "2023-11-14T23:03:20Z", "user", "older export message", "2023-11-14T23:03:21Z"), ProviderChatGPT, MessageKindPrompt, 0, 3),
 			},
 		},
 		{
@@ -410,8 +411,8 @@ func TestParseConversationsJSON(t *testing.T) {
 				}
 			]`,
 			wantEntries: []ConversationEntry{
-				entry("cgpt-2", "ChatGPT Hidden", "system", "visible context", "2023-11-14T22:15:00Z"),
-				entry("cgpt-2", "ChatGPT Hidden", "user", "question text", "2023-11-14T22:15:00Z"),
+				parsedEntry(entry("cgpt-2", "ChatGPT Hidden", "system", "visible context", "2023-11-14T22:15:00Z"), ProviderChatGPT, MessageKindSystem, 0, 2),
+				parsedEntry(entry("cgpt-2", "ChatGPT Hidden", "user", "question text", "2023-11-14T22:15:00Z"), ProviderChatGPT, MessageKindPrompt, 1, 2),
 			},
 		},
 		{
@@ -459,8 +460,8 @@ func TestParseConversationsJSON(t *testing.T) {
 				}
 			]`,
 			wantEntries: []ConversationEntry{
-				entry("cgpt-3", "Thread One", "user", "thread one message", "2023-11-14T22:16:41Z"),
-				entry("cgpt-4", "Thread Two", "assistant", "thread two message", "2023-11-14T22:18:21Z"),
+				parsedEntry(entry("cgpt-3", "Thread One", "user", "thread one message", "2023-11-14T22:16:41Z"), ProviderChatGPT, MessageKindPrompt, 0, 3),
+				parsedEntry(entry("cgpt-4", "Thread Two", "assistant", "thread two message", "2023-11-14T22:18:21Z"), ProviderChatGPT, MessageKindAnswer, 0, 3),
 			},
 		},
 		{
@@ -488,7 +489,7 @@ func TestParseConversationsJSON(t *testing.T) {
 				}
 			]`,
 			wantEntries: []ConversationEntry{
-				entry("cgpt-5", "Fractional Time", "assistant", "fractional timestamp", "2023-11-14T22:23:20.25Z"),
+				parsedEntry(entry("cgpt-5", "Fractional Time", "assistant", "fractional timestamp", "2023-11-14T22:23:20.25Z"), ProviderChatGPT, MessageKindAnswer, 0, 3),
 			},
 		},
 		{
@@ -516,7 +517,7 @@ func TestParseConversationsJSON(t *testing.T) {
 				}
 			]`,
 			wantEntries: []ConversationEntry{
-				entry("cgpt-6", "Content Text Fallback", "assistant", "{\"tool\":\"web.run\"}", "2023-11-14T22:25:01Z"),
+				parsedEntry(entry("cgpt-6", "Content Text Fallback", "assistant", "{\"tool\":\"web.run\"}", "2023-11-14T22:25:01Z"), ProviderChatGPT, MessageKindAnswer, 0, 6),
 			},
 		},
 		{
@@ -544,7 +545,7 @@ func TestParseConversationsJSON(t *testing.T) {
 					}
 				]`,
 			wantEntries: []ConversationEntry{
-				entry("cgpt-utf8-1", "Café 日本語 🎷", "assistant", "¡Hola! Привет こんにちは 👋", "2023-11-14T22:26:41Z"),
+				parsedEntry(entry("cgpt-utf8-1", "Café 日本語 🎷", "assistant", "¡Hola! Привет こんにちは 👋", "2023-11-14T22:26:41Z"), ProviderChatGPT, MessageKindAnswer, 0, 9),
 			},
 		},
 		{
@@ -572,7 +573,7 @@ func TestParseConversationsJSON(t *testing.T) {
 					}
 				]`,
 			wantEntries: []ConversationEntry{
-				entry("cgpt-utf8-2", "Fallback UTF-8", "assistant", "🧪 Δοκιμή 東京 — résumé", "2023-11-14T22:28:21Z"),
+				parsedEntry(entry("cgpt-utf8-2", "Fallback UTF-8", "assistant", "🧪 Δοκιμή 東京 — résumé", "2023-11-14T22:28:21Z"), ProviderChatGPT, MessageKindAnswer, 0, 10),
 			},
 		},
 	}
@@ -594,3 +595,287 @@ func TestParseConversationsJSON(t *testing.T) {
 		})
 	}
 }
+
+func TestParseConversationsJSONWithOptions(t *testing.T) {
+	input := `[
+		{
+			"uuid": "good-1",
+			"name": "Good",
+			"chat_messages": [{ "sender": "human", "text": "first" }]
+		},
+		{
+			"uuid": "bad-claude",
+			"name": "Bad",
+			"chat_messages": "not a list"
+		},
+		{
+			"title": "Bad ChatGPT",
+			"conversation_id": "bad-chatgpt",
+			"mapping": []
+		},
+		42,
+		{
+			"uuid": "good-2",
+			"name": "Also Good",
+			"chat_messages": [{ "sender": "assistant", "text": "second" }]
+		}
+	]`
+
+	t.Run("strict mode aborts on the first malformed conversation", func(t *testing.T) {
+		_, err := ParseConversationsJSONWithOptions(strings.NewReader(input), ParseOptions{})
+		assertErrorContains(t, err, "parse conversation at index 1")
+	})
+
+	t.Run("lenient mode skips malformed conversations and reports warnings", func(t *testing.T) {
+		result, err := ParseConversationsJSONWithOptions(strings.NewReader(input), ParseOptions{Lenient: true})
+		if err != nil {
+			t.Fatalf("ParseConversationsJSONWithOptions returned error: %v", err)
+		}
+
+		assertConversationEntries(t, result.Entries(), []ConversationEntry{
+			parsedEntry(entry("good-1", "Good", "human", "first", ""), ProviderClaude, MessageKindPrompt, 0, 2),
+			parsedEntry(entry("good-2", "Also Good", "assistant", "second", ""), ProviderClaude, MessageKindAnswer, 0, 2),
+		})
+
+		wantWarnings := []struct {
+			index          int
+			conversationID string
+			errContains    string
+		}{
+			{index: 1, conversationID: "bad-claude", errContains: "decode claude conversation"},
+			{index: 2, conversationID: "bad-chatgpt", errContains: "decode chatgpt conversation"},
+			{index: 3, conversationID: "", errContains: "decode conversation"},
+		}
+		if len(result.Warnings) != len(wantWarnings) {
+			t.Fatalf("expected %d warnings, got %d: %+v", len(wantWarnings), len(result.Warnings), result.Warnings)
+		}
+		for index, want := range wantWarnings {
+			got := result.Warnings[index]
+			if got.Index != want.index || got.ConversationID != want.conversationID {
+				t.Fatalf("warning %d mismatch\nwant: index=%d id=%q\ngot:  %+v", index, want.index, want.conversationID, got)
+			}
+			assertErrorContains(t, got.Err, want.errContains)
+			if got.Message != got.Err.Error() {
+				t.Fatalf("expected warning message %q to match error %q", got.Message, got.Err.Error())
+			}
+		}
+	})
+
+	t.Run("lenient mode still fails when the export is not a json array", func(t *testing.T) {
+		_, err := ParseConversationsJSONWithOptions(strings.NewReader("{"), ParseOptions{Lenient: true})
+		assertErrorContains(t, err, "decode conversations json")
+	})
+}
+
+func TestParseConversationsJSONTagsProvider(t *testing.T) {
+	input := `[
+		{ "uuid": "claude-1", "name": "Claude", "chat_messages": [{ "sender": "human", "text": "hi claude" }] },
+		{
+			"title": "ChatGPT",
+			"conversation_id": "chatgpt-1",
+			"mapping": {
+				"root": {"id": "root", "message": null, "parent": null, "children": ["m1"]},
+				"m1": {"id": "m1", "parent": "root", "children": [], "message": {"author": {"role": "user"}, "content": {"content_type": "text", "parts": ["hi chatgpt"]}}}
+			}
+		},
+		{ "header": "Gemini Apps", "title": "Prompted hi gemini", "time": "2025-01-01T00:00:00Z" }
+	]`
+
+	entries, err := ParseConversationsJSON(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("ParseConversationsJSON returned error: %v", err)
+	}
+
+	wantProviders := []string{ProviderClaude, ProviderChatGPT, ProviderGemini}
+	if len(entries) != len(wantProviders) {
+		t.Fatalf("expected %d entries, got %d", len(wantProviders), len(entries))
+	}
+	for index, wantProvider := range wantProviders {
+		if entries[index].Provider != wantProvider {
+			t.Fatalf("entry %d: expected provider %q, got %q", index, wantProvider, entries[index].Provider)
+		}
+	}
+}
+
+func TestParseClaudeAttachments(t *testing.T) {
+	input := `[
+		{
+			"uuid": "claude-1",
+			"name": "Review",
+			"chat_messages": [
+				{
+					"sender": "human",
+					"text": "Review this file",
+					"attachments": [
+						{"file_name": "notes.txt", "file_type": "txt", "file_size": 11, "extracted_content": "hello world"},
+						{"file_name": "", "extracted_content": ""}
+					]
+				},
+				{"sender": "assistant", "text": "Looks good."}
+			]
+		}
+	]`
+
+	result, err := ParseConversations(strings.NewReader(input), ParseOptions{})
+	if err != nil {
+		t.Fatalf("ParseConversations returned error: %v", err)
+	}
+
+	messages := result.Conversations[0].Messages
+	wantAttachments := []Attachment{{Name: "notes.txt", FileType: "txt", Content: "hello world"}}
+	if !reflect.DeepEqual(messages[0].Attachments, wantAttachments) {
+		t.Fatalf("expected attachments %+v, got %+v", wantAttachments, messages[0].Attachments)
+	}
+	if messages[1].Attachments != nil {
+		t.Fatalf("expected no attachments on the answer, got %+v", messages[1].Attachments)
+	}
+}
+
+func TestConversationNameFromPrompt(t *testing.T) {
+	tests := []struct {
+		name   string
+		prompt string
+		want   string
+	}{
+		{name: "keeps short prompts", prompt: "Plan a trip", want: "Plan a trip"},
+		{name: "uses the first line only", prompt: "Summarize this:\nlong pasted text", want: "Summarize this:"},
+		{
+			name:   "truncates long prompts on rune boundaries",
+			prompt: strings.Repeat("日本", 50),
+			want:   strings.Repeat("日本", 40) + "…",
+		},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			if got := conversationNameFromPrompt(testCase.prompt); got != testCase.want {
+				t.Fatalf("expected %q, got %q", testCase.want, got)
+			}
+		})
+	}
+}
+
+func TestParseChatGPTMessageMetadata(t *testing.T) {
+	input := `[
+		{
+			"title": "Browsing",
+			"conversation_id": "meta-1",
+			"current_node": "answer",
+			"mapping": {
+				"root": {"id": "root", "message": null, "parent": null, "children": ["question"]},
+				"question": {
+					"id": "question",
+					"parent": "root",
+					"children": ["answer"],
+					"message": {"author": {"role": "user"}, "content": {"content_type": "text", "parts": ["Latest Go release?"]}, "metadata": {}}
+				},
+				"answer": {
+					"id": "answer",
+					"parent": "question",
+					"children": [],
+					"message": {
+						"author": {"role": "assistant"},
+						"content": {"content_type": "text", "parts": ["Go 1.23 is the latest release."]},
+						"metadata": {
+							"model_slug": "gpt-4o",
+							"resolved_model_slug": "gpt-4o-2024-08-06",
+							"finish_details": {"type": "stop", "stop_tokens": [200002]},
+							"citations": [
+								{"start_ix": 0, "end_ix": 10, "metadata": {"type": "webpage", "title": "Go release history", "url": "https://go.dev/doc/devel/release"}},
+								{"title": "Go blog", "url": "https://go.dev/blog"},
+								{"metadata": {"title": "Duplicate", "url": "https://go.dev/blog"}},
+								{"metadata": {"title": "No URL"}}
+							],
+							"search_queries": [{"type": "search", "q": "latest go release"}, "go 1.23", {"q": " "}]
+						}
+					}
+				}
+			}
+		}
+	]`
+
+	entries, err := ParseConversationsJSON(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("ParseConversationsJSON returned error: %v", err)
+	}
+
+	answer := parsedEntry(entry("meta-1", "Browsing", "assistant", "Go 1.23 is the latest release.", ""), ProviderChatGPT, MessageKindAnswer, 1, 10)
+	answer.Model = "gpt-4o-2024-08-06"
+	answer.FinishReason = "stop"
+	answer.Citations = []Citation{
+		{Title: "Go release history", URL: "https://go.dev/doc/devel/release"},
+		{Title: "Go blog", URL: "https://go.dev/blog"},
+	}
+	answer.SearchQueries = []string{"latest go release", "go 1.23"}
+
+	assertConversationEntries(t, entries, []ConversationEntry{
+		parsedEntry(entry("meta-1", "Browsing", "user", "Latest Go release?", ""), ProviderChatGPT, MessageKindPrompt, 0, 4),
+		answer,
+	})
+	if entries[0].Model != "" || entries[0].Citations != nil || entries[0].SearchQueries != nil {
+		t.Fatalf("expected no metadata on the user message, got %+v", entries[0])
+	}
+}
+
+func TestParseChatGPTMessageKinds(t *testing.T) {
+	input := `[
+		{
+			"title": "Tools",
+			"conversation_id": "kinds-1",
+			"current_node": "answer",
+			"mapping": {
+				"root": {"id": "root", "message": null, "parent": null, "children": ["prompt"]},
+				"prompt": {
+					"id": "prompt", "parent": "root", "children": ["thinking"],
+					"message": {"author": {"role": "user"}, "recipient": "all", "content": {"content_type": "text", "parts": ["Weather in Oslo?"]}}
+				},
+				"thinking": {
+					"id": "thinking", "parent": "prompt", "children": ["recap"],
+					"message": {"author": {"role": "assistant"}, "recipient": "all", "content": {"content_type": "thoughts", "thoughts": [{"summary": "Need live data", "content": "Search the web."}]}}
+				},
+				"recap": {
+					"id": "recap", "parent": "thinking", "children": ["call"],
+					"message": {"author": {"role": "assistant"}, "recipient": "all", "content": {"content_type": "reasoning_recap", "content": "Thought for 3s"}}
+				},
+				"call": {
+					"id": "call", "parent": "recap", "children": ["result"],
+					"message": {"author": {"role": "assistant"}, "recipient": "web.run", "channel": "commentary", "content": {"content_type": "code", "text": "{\"search_query\": [{\"q\": \"oslo weather\"}]}"}}
+				},
+				"result": {
+					"id": "result", "parent": "call", "children": ["analysis"],
+					"message": {"author": {"role": "tool", "name": "web.run"}, "recipient": "all", "content": {"content_type": "text", "parts": ["Oslo: 4°C, light rain"]}}
+				},
+				"analysis": {
+					"id": "analysis", "parent": "result", "children": ["answer"],
+					"message": {"author": {"role": "assistant"}, "recipient": "all", "channel": "analysis", "content": {"content_type": "text", "parts": ["The result mentions rain."]}}
+				},
+				"answer": {
+					"id": "answer", "parent": "analysis", "children": [],
+					"message": {"author": {"role": "assistant"}, "recipient": "all", "channel": "final", "content": {"content_type": "text", "parts": ["It is 4°C with light rain."]}}
+				}
+			}
+		}
+	]`
+
+	entries, err := ParseConversationsJSON(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("ParseConversationsJSON returned error: %v", err)
+	}
+
+	kindEntry := func(speaker string, message string, kind string, messageIndex int, tokenCount int, recipient string, toolName string) ConversationEntry {
+		kindEntry := parsedEntry(entry("kinds-1", "Tools", speaker, message, ""), ProviderChatGPT, kind, messageIndex, tokenCount)
+		kindEntry.Recipient = recipient
+		kindEntry.ToolName = toolName
+		return kindEntry
+	}
+
+	assertConversationEntries(t, entries, []ConversationEntry{
+		kindEntry("user", "Weather in Oslo?", MessageKindPrompt, 0, 4, "", ""),
+		kindEntry("assistant", "Need live data\nSearch the web.", MessageKindReasoning, 1, 8, "", ""),
+		kindEntry("assistant", "Thought for 3s", MessageKindReasoning, 2, 5, "", ""),
+		kindEntry("assistant", `{"search_query": [{"q": "oslo weather"}]}`, MessageKindToolCall, 3, 13, "web.run", ""),
+		kindEntry("tool", "Oslo: 4°C, light rain", MessageKindToolResult, 4, 9, "", "web.run"),
+		kindEntry("assistant", "The result mentions rain.", MessageKindReasoning, 5, 5, "", ""),
+		kindEntry("assistant", "It is 4°C with light rain.", MessageKindAnswer, 6, 9, "", ""),
+	})
+}
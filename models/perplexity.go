@@ -0,0 +1,75 @@
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// Perplexity exports a thread as a list of entries, each holding one query and the
+// answer to it. Every entry becomes a user message followed by an assistant message.
+
+type rawPerplexityThread struct {
+	UUID      string               `json:"uuid"`
+	ThreadID  string               `json:"thread_id"`
+	Slug      string               `json:"slug"`
+	Title     string               `json:"title"`
+	CreatedAt string               `json:"created_at"`
+	Entries   []rawPerplexityEntry `json:"entries"`
+}
+
+type rawPerplexityEntry struct {
+	QueryStr        string `json:"query_str"`
+	Query           string `json:"query"`
+	Answer          string `json:"answer"`
+	CreatedAt       string `json:"created_at"`
+	UpdatedDatetime string `json:"updated_datetime"`
+}
+
+type perplexityFormat struct{}
+
+func (perplexityFormat) Detect(fields map[string]json.RawMessage) bool {
+	_, hasEntries := fields["entries"]
+	return hasEntries
+}
+
+func (perplexityFormat) Parse(rawConversationJSON json.RawMessage) ([]Conversation, error) {
+	var thread rawPerplexityThread
+	if err := json.Unmarshal(rawConversationJSON, &thread); err != nil {
+		return nil, fmt.Errorf("decode perplexity thread: %w", err)
+	}
+
+	messages := make([]Message, 0, 2*len(thread.Entries))
+	oldestMessageTimestamp := ""
+	for _, entry := range thread.Entries {
+		query := firstNonBlank(entry.QueryStr, entry.Query)
+		timestamp := normalizeTimestamp(firstNonBlank(entry.CreatedAt, entry.UpdatedDatetime))
+		oldestMessageTimestamp = olderTimestamp(oldestMessageTimestamp, timestamp)
+		messages = appendExchange(messages, query, entry.Answer, timestamp)
+	}
+
+	createdAt := normalizeTimestamp(thread.CreatedAt)
+	if createdAt == "" {
+		createdAt = oldestMessageTimestamp
+	}
+
+	return []Conversation{{
+		Provider:  ProviderPerplexity,
+		ID:        firstNonBlank(thread.UUID, thread.ThreadID, thread.Slug),
+		Name:      resolveConversationName(thread.Title, messages),
+		CreatedAt: createdAt,
+		Messages:  messages,
+	}}, nil
+}
+
+// appendExchange appends a query/answer pair, skipping whichever side is blank.
+func appendExchange(messages []Message, query string, answer string, timestamp string) []Message {
+	if query = strings.TrimSpace(query); query != "" {
+		messages = append(messages, Message{Speaker: "user", Text: query, Timestamp: timestamp})
+	}
+	if answer = strings.TrimSpace(answer); answer != "" {
+		messages = append(messages, Message{Speaker: "assistant", Text: answer, Timestamp: timestamp})
+	}
+
+	return messages
+}
@@ -0,0 +1,72 @@
+package models
+
+import (
+	"strings"
+	"testing"
+)
+
+const (
+	perplexityThreadsJSONFixturePath = "testdata/perplexity_threads.json"
+	perplexityThreadsCSVFixturePath  = "testdata/perplexity_threads.csv"
+)
+
+func TestParsePerplexityThreadsJSON(t *testing.T) {
+	result, err := ParseConversations(strings.NewReader(loadFixture(t, perplexityThreadsJSONFixturePath)), ParseOptions{})
+	if err != nil {
+		t.Fatalf("ParseConversations returned error: %v", err)
+	}
+
+	want := []ConversationEntry{
+		perplexityEntry("pplx-thread-1", "Rust vs Go for CLIs", "2025-06-10T18:20:00.5Z", "user", "Rust or Go for a small CLI tool?", "2025-06-10T18:20:00.5Z", MessageKindPrompt, 0, 10),
+		perplexityEntry("pplx-thread-1", "Rust vs Go for CLIs", "2025-06-10T18:20:00.5Z", "assistant", "Go compiles fast and ships a single binary [1]. Rust gives finer control [2].", "2025-06-10T18:20:00.5Z", MessageKindAnswer, 1, 19),
+		perplexityEntry("pplx-thread-1", "Rust vs Go for CLIs", "2025-06-10T18:20:00.5Z", "user", "Which has better cross-compilation?", "2025-06-10T18:21:30Z", MessageKindPrompt, 2, 7),
+		perplexityEntry("pplx-thread-1", "Rust vs Go for CLIs", "2025-06-10T18:20:00.5Z", "assistant", "Go: set GOOS and GOARCH.", "2025-06-10T18:21:30Z", MessageKindAnswer, 3, 9),
+		perplexityEntry("what-is-a-monad-abc123", "What is a monad?", "2025-06-11T07:00:00Z", "user", "What is a monad?\nExplain simply.", "", MessageKindPrompt, 0, 10),
+	}
+
+	assertConversationEntries(t, result.Entries(), want)
+}
+
+func TestParsePerplexityThreadsCSV(t *testing.T) {
+	result, err := ParseConversations(strings.NewReader(loadFixture(t, perplexityThreadsCSVFixturePath)), ParseOptions{})
+	if err != nil {
+		t.Fatalf("ParseConversations returned error: %v", err)
+	}
+
+	monadID := csvConversationID(ProviderPerplexity, "row-2")
+	want := []ConversationEntry{
+		perplexityEntry("pplx-thread-1", "Rust vs Go for CLIs", "2025-06-10T18:20:00Z", "user", "Rust or Go for a small CLI tool?", "2025-06-10T18:20:00Z", MessageKindPrompt, 0, 10),
+		perplexityEntry("pplx-thread-1", "Rust vs Go for CLIs", "2025-06-10T18:20:00Z", "assistant", "Go compiles fast and ships a single binary.", "2025-06-10T18:20:00Z", MessageKindAnswer, 1, 10),
+		perplexityEntry("pplx-thread-1", "Rust vs Go for CLIs", "2025-06-10T18:20:00Z", "user", "Which has better cross-compilation?", "2025-06-10T18:21:30Z", MessageKindPrompt, 2, 7),
+		perplexityEntry("pplx-thread-1", "Rust vs Go for CLIs", "2025-06-10T18:20:00Z", "assistant", "Go: set GOOS and GOARCH.", "2025-06-10T18:21:30Z", MessageKindAnswer, 3, 9),
+		perplexityEntry(monadID, "What is a monad?", "2025-06-11T07:00:00Z", "user", "What is a monad?", "2025-06-11T07:00:00Z", MessageKindPrompt, 0, 6),
+		perplexityEntry(monadID, "What is a monad?", "2025-06-11T07:00:00Z", "assistant", "A monad wraps values with context.", "2025-06-11T07:00:00Z", MessageKindAnswer, 1, 8),
+	}
+
+	assertConversationEntries(t, result.Entries(), want)
+}
+
+func perplexityEntry(
+	conversationID string,
+	conversationName string,
+	conversationCreatedAt string,
+	speaker string,
+	message string,
+	messageTimestamp string,
+	kind string,
+	messageIndex int,
+	tokenCount int,
+) ConversationEntry {
+	return ConversationEntry{
+		Provider:              ProviderPerplexity,
+		ConversationID:        conversationID,
+		ConversationName:      conversationName,
+		ConversationCreatedAt: conversationCreatedAt,
+		Speaker:               speaker,
+		Message:               message,
+		MessageTimestamp:      messageTimestamp,
+		Kind:                  kind,
+		MessageIndex:          messageIndex,
+		TokenCount:            tokenCount,
+	}
+}
@@ -0,0 +1,238 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// SearchQuery is a parsed conversation query. A query is a list of terms that must all
+// match:
+//
+//   - plain words and "quoted phrases" match the conversation name, its generated
+//     title or any message text, ignoring case;
+//   - provider:NAME matches the provider, such as provider:claude;
+//   - tag:NAME matches a tag the user gave the conversation;
+//   - is:starred and is:noted match starred conversations and ones with notes;
+//   - has:code and has:attachment match conversations with a code block or attachment;
+//   - model:NAME matches conversations with an answer from a model containing NAME;
+//   - before:YYYY-MM-DD and after:YYYY-MM-DD match the creation date.
+//
+// A leading "-" negates a term. Words with any other prefix before a colon are plain
+// text, so pasted URLs still search as expected, and so is a quoted prefix:
+// "is:starred" searches for the text is:starred.
+type SearchQuery struct {
+	terms []searchTerm
+}
+
+type searchTerm struct {
+	field   string
+	value   string
+	date    time.Time
+	negated bool
+}
+
+// Query fields recognised before a colon.
+const (
+	queryFieldText     = ""
+	queryFieldProvider = "provider"
+	queryFieldTag      = "tag"
+	queryFieldIs       = "is"
+	queryFieldHas      = "has"
+	queryFieldModel    = "model"
+	queryFieldBefore   = "before"
+	queryFieldAfter    = "after"
+)
+
+var queryFields = map[string]struct{}{
+	queryFieldProvider: {},
+	queryFieldTag:      {},
+	queryFieldIs:       {},
+	queryFieldHas:      {},
+	queryFieldModel:    {},
+	queryFieldBefore:   {},
+	queryFieldAfter:    {},
+}
+
+// ParseSearchQuery parses query. It fails on unknown is:/has: values, malformed dates
+// and unterminated quotes, so a saved search cannot silently match nothing.
+func ParseSearchQuery(query string) (SearchQuery, error) {
+	tokens, err := splitQueryTokens(query)
+	if err != nil {
+		return SearchQuery{}, err
+	}
+
+	terms := make([]searchTerm, 0, len(tokens))
+	for _, token := range tokens {
+		term, err := parseSearchTerm(token)
+		if err != nil {
+			return SearchQuery{}, err
+		}
+		if term.value == "" && term.field == queryFieldText {
+			continue
+		}
+		terms = append(terms, term)
+	}
+
+	return SearchQuery{terms: terms}, nil
+}
+
+// IsEmpty reports whether the query has no terms and so matches every conversation.
+func (query SearchQuery) IsEmpty() bool {
+	return len(query.terms) == 0
+}
+
+// queryToken is one whitespace-separated part of a query. literal is set when a quote
+// opens before any colon, so the token cannot be read as a field.
+type queryToken struct {
+	text    string
+	literal bool
+}
+
+// splitQueryTokens splits on whitespace outside double quotes. The quotes are kept
+// out of the text; a prefix such as -"exact phrase" or tag:"two words" stays attached.
+func splitQueryTokens(query string) ([]queryToken, error) {
+	tokens := make([]queryToken, 0, 4)
+	var current strings.Builder
+	inQuotes, quoted, literal := false, false, false
+	flush := func() {
+		if current.Len() > 0 || quoted {
+			tokens = append(tokens, queryToken{text: current.String(), literal: literal})
+		}
+		current.Reset()
+		quoted, literal = false, false
+	}
+
+	for _, character := range query {
+		switch {
+		case character == '"':
+			if !quoted && !strings.Contains(current.String(), ":") {
+				literal = true
+			}
+			inQuotes = !inQuotes
+			quoted = true
+		case unicode.IsSpace(character) && !inQuotes:
+			flush()
+		default:
+			current.WriteRune(character)
+		}
+	}
+	if inQuotes {
+		return nil, fmt.Errorf("unterminated quote in query %q", query)
+	}
+	flush()
+
+	return tokens, nil
+}
+
+func parseSearchTerm(token queryToken) (searchTerm, error) {
+	text := token.text
+	term := searchTerm{}
+	if strings.HasPrefix(text, "-") && len(text) > 1 {
+		term.negated = true
+		text = text[1:]
+	}
+
+	if field, value, found := strings.Cut(text, ":"); found && !token.literal {
+		if _, known := queryFields[strings.ToLower(field)]; known {
+			term.field = strings.ToLower(field)
+			term.value = strings.ToLower(strings.TrimSpace(value))
+			return validateSearchTerm(term)
+		}
+	}
+
+	term.value = strings.ToLower(strings.TrimSpace(text))
+	return term, nil
+}
+
+func validateSearchTerm(term searchTerm) (searchTerm, error) {
+	if term.value == "" {
+		return term, fmt.Errorf("%s: needs a value", term.field)
+	}
+
+	switch term.field {
+	case queryFieldIs:
+		if term.value != "starred" && term.value != "noted" {
+			return term, fmt.Errorf("unknown filter is:%s, expected is:starred or is:noted", term.value)
+		}
+	case queryFieldHas:
+		if term.value != "code" && term.value != "attachment" {
+			return term, fmt.Errorf("unknown filter has:%s, expected has:code or has:attachment", term.value)
+		}
+	case queryFieldBefore, queryFieldAfter:
+		date, err := time.Parse("2006-01-02", term.value)
+		if err != nil {
+			return term, fmt.Errorf("%s:%s is not a YYYY-MM-DD date", term.field, term.value)
+		}
+		term.date = date
+	}
+
+	return term, nil
+}
+
+// Matches reports whether conversation matches every term of the query. annotation is
+// the user's annotation of the conversation, or nil when it has none.
+func (query SearchQuery) Matches(conversation Conversation, annotation *ConversationAnnotation) bool {
+	for _, term := range query.terms {
+		if term.matches(conversation, annotation) == term.negated {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (term searchTerm) matches(conversation Conversation, annotation *ConversationAnnotation) bool {
+	switch term.field {
+	case queryFieldProvider:
+		return strings.EqualFold(conversation.Provider, term.value)
+	case queryFieldTag:
+		if annotation == nil {
+			return false
+		}
+		for _, tag := range annotation.Tags {
+			if strings.EqualFold(tag, term.value) {
+				return true
+			}
+		}
+		return false
+	case queryFieldIs:
+		if annotation == nil {
+			return false
+		}
+		if term.value == "starred" {
+			return annotation.Starred
+		}
+		return annotation.Note != "" || len(annotation.MessageNotes) > 0
+	case queryFieldHas:
+		for _, message := range conversation.Messages {
+			if term.value == "code" && (message.CodeLanguage != "" || len(extractFencedCodeBlocks(message.Text)) > 0) {
+				return true
+			}
+			if term.value == "attachment" && len(message.Attachments) > 0 {
+				return true
+			}
+		}
+		return false
+	case queryFieldModel:
+		for _, message := range conversation.Messages {
+			if strings.Contains(strings.ToLower(message.Model), term.value) {
+				return true
+			}
+		}
+		return false
+	case queryFieldBefore, queryFieldAfter:
+		createdAt, ok := parseTimestamp(conversation.CreatedAt)
+		if !ok {
+			return false
+		}
+		if term.field == queryFieldBefore {
+			return createdAt.Before(term.date)
+		}
+		return !createdAt.Before(term.date.AddDate(0, 0, 1))
+	default:
+		return strings.Contains(strings.ToLower(conversation.GeneratedTitle), term.value) ||
+			conversationContains(conversation, term.value)
+	}
+}
@@ -0,0 +1,108 @@
+package models
+
+import "testing"
+
+func TestSearchQueryMatches(t *testing.T) {
+	conversation := Conversation{
+		Provider:  ProviderClaude,
+		ID:        "conv-1",
+		Name:      "Review of the payments PR",
+		CreatedAt: "2026-02-10T09:00:00Z",
+		Messages: []Message{
+			{Speaker: "user", Text: "Please review https://example.com/pull/42", Timestamp: "2026-02-10T09:00:00Z"},
+			{Speaker: "assistant", Text: "Looks good:\n```go\nfunc main() {}\n```", Model: "claude-sonnet-4", Timestamp: "2026-02-10T09:00:05Z"},
+		},
+	}
+	annotation := &ConversationAnnotation{Provider: ProviderClaude, ConversationID: "conv-1", Starred: true, Tags: []string{"Work"}}
+
+	tests := []struct {
+		name       string
+		query      string
+		annotation *ConversationAnnotation
+		want       bool
+	}{
+		{name: "empty query", query: "  ", want: true},
+		{name: "words in name and text", query: "payments looks", want: true},
+		{name: "missing word", query: "payments invoice", want: false},
+		{name: "quoted phrase", query: `"payments pr"`, want: true},
+		{name: "quoted phrase out of order", query: `"pr payments"`, want: false},
+		{name: "url stays text", query: "https://example.com/pull/42", want: true},
+		{name: "provider", query: "provider:claude", want: true},
+		{name: "other provider", query: "provider:chatgpt", want: false},
+		{name: "negated provider", query: "-provider:chatgpt review", want: true},
+		{name: "negated phrase", query: `-"looks good"`, want: false},
+		{name: "tag ignores case", query: "tag:work", annotation: annotation, want: true},
+		{name: "tag without annotation", query: "tag:work", want: false},
+		{name: "starred", query: "is:starred", annotation: annotation, want: true},
+		{name: "not noted", query: "-is:noted", annotation: annotation, want: true},
+		{name: "has code", query: "has:code", want: true},
+		{name: "has attachment", query: "has:attachment", want: false},
+		{name: "model", query: "model:sonnet", want: true},
+		{name: "before", query: "before:2026-02-11", want: true},
+		{name: "before same day", query: "before:2026-02-10", want: false},
+		{name: "after same day", query: "after:2026-02-10", want: false},
+		{name: "after", query: "after:2026-02-09", want: true},
+		{name: "field names ignore case", query: "Provider:Claude IS:starred", annotation: annotation, want: true},
+		{name: "quoted field is text", query: `"is:starred"`, annotation: annotation, want: false},
+		{name: "quoted unknown field is text", query: `"is:pinned"`, want: false},
+		{name: "negated quoted field is text", query: `-"is:starred"`, annotation: annotation, want: true},
+		{name: "quoted field value", query: `tag:"work"`, annotation: annotation, want: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			query, err := ParseSearchQuery(test.query)
+			if err != nil {
+				t.Fatalf("ParseSearchQuery(%q) returned error: %v", test.query, err)
+			}
+			if got := query.Matches(conversation, test.annotation); got != test.want {
+				t.Fatalf("query %q matched %v, want %v", test.query, got, test.want)
+			}
+		})
+	}
+}
+
+func TestSearchQueryHasCode(t *testing.T) {
+	tests := []struct {
+		name    string
+		message Message
+		want    bool
+	}{
+		{name: "backtick fence", message: Message{Text: "```\nls -la\n```"}, want: true},
+		{name: "tilde fence", message: Message{Text: "Run:\n~~~sh\nmake test\n~~~"}, want: true},
+		{name: "code content", message: Message{Text: "print(1)", CodeLanguage: "python"}, want: true},
+		{name: "backticks mentioned in prose", message: Message{Text: "Wrap it in ``` fences."}, want: false},
+		{name: "empty fence", message: Message{Text: "```\n```"}, want: false},
+	}
+
+	query, err := ParseSearchQuery("has:code")
+	if err != nil {
+		t.Fatalf("ParseSearchQuery returned error: %v", err)
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			conversation := Conversation{ID: "conv-1", Messages: []Message{test.message}}
+			if got := query.Matches(conversation, nil); got != test.want {
+				t.Fatalf("has:code matched %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestParseSearchQueryErrors(t *testing.T) {
+	tests := []string{
+		`"unterminated`,
+		"is:pinned",
+		"has:image",
+		"before:yesterday",
+		"tag:",
+	}
+
+	for _, query := range tests {
+		t.Run(query, func(t *testing.T) {
+			if _, err := ParseSearchQuery(query); err == nil {
+				t.Fatalf("expected ParseSearchQuery(%q) to fail", query)
+			}
+		})
+	}
+}
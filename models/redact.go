@@ -0,0 +1,247 @@
+package models
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// redactionLabels name the placeholder written in place of each kind of sensitive data.
+var redactionLabels = map[string]string{
+	SensitivePrivateKey:    "private-key",
+	SensitiveAWSCredential: "aws-key",
+	SensitiveGCPCredential: "gcp-key",
+	SensitiveAPIKey:        "api-key",
+	SensitiveJWT:           "jwt",
+	SensitiveCreditCard:    "credit-card",
+	SensitiveNationalID:    "national-id",
+	SensitiveIBAN:          "iban",
+	SensitivePhoneNumber:   "phone",
+	SensitiveEmail:         "email",
+}
+
+// redactionSkippedKeys hold identifiers and references between records. They are
+// copied as they are, even when they look like a phone or card number, so the redacted
+// copy keeps its conversation IDs and message tree.
+var redactionSkippedKeys = map[string]bool{
+	"id":             true,
+	"uuid":           true,
+	"parent":         true,
+	"children":       true,
+	"current_node":   true,
+	"conversationId": true,
+}
+
+// isRedactionSkippedKey reports whether the value of key is an identifier or reference,
+// including the *_id and *_uuid keys of every export format.
+func isRedactionSkippedKey(key string) bool {
+	return redactionSkippedKeys[key] || strings.HasSuffix(key, "_id") || strings.HasSuffix(key, "_uuid")
+}
+
+// RedactionPlaceholder returns the text that replaces a value of the given kind, such
+// as [REDACTED:aws-key].
+func RedactionPlaceholder(kind string) string {
+	label, ok := redactionLabels[kind]
+	if !ok {
+		label = strings.ReplaceAll(kind, "_", "-")
+	}
+
+	return "[REDACTED:" + label + "]"
+}
+
+// RedactText replaces every sensitive value in text with its placeholder and returns
+// the redacted text and the number of values replaced.
+func RedactText(text string) (string, int) {
+	matches := findSensitiveMatches(text)
+	if len(matches) == 0 {
+		return text, 0
+	}
+
+	var redacted strings.Builder
+	redacted.Grow(len(text))
+	offset := 0
+	for _, match := range matches {
+		redacted.WriteString(text[offset:match.start])
+		redacted.WriteString(RedactionPlaceholder(match.kind))
+		offset = match.end
+	}
+	redacted.WriteString(text[offset:])
+
+	return redacted.String(), len(matches)
+}
+
+// WriteRedactedExport copies the JSON export at sourcePath to destinationPath with every
+// sensitive value replaced by its placeholder. Only values change: keys, nesting and
+// the identifiers and references between records are kept, so the copy still loads as
+// a Claude or ChatGPT export. A number that matches, such as a card number, becomes
+// its placeholder string. Archives and compression are unwrapped and the copy is
+// written as plain JSON. It returns the number of values replaced.
+func WriteRedactedExport(sourcePath string, destinationPath string) (int, error) {
+	trimmedDestination := strings.TrimSpace(destinationPath)
+	if trimmedDestination == "" {
+		return 0, fmt.Errorf("destination path is required")
+	}
+	if samePath(sourcePath, trimmedDestination) {
+		return 0, fmt.Errorf("refusing to overwrite the original export")
+	}
+
+	output, err := os.Create(trimmedDestination)
+	if err != nil {
+		return 0, fmt.Errorf("create redacted export: %w", err)
+	}
+
+	redactions := 0
+	err = readConversationsDocument(sourcePath, func(document io.Reader) error {
+		var redactErr error
+		redactions, redactErr = redactJSONDocument(document, output)
+		return redactErr
+	})
+	closeErr := output.Close()
+	if err == nil && closeErr != nil {
+		err = fmt.Errorf("close redacted export: %w", closeErr)
+	}
+	if err != nil {
+		os.Remove(trimmedDestination)
+		return 0, err
+	}
+
+	return redactions, nil
+}
+
+func samePath(first string, second string) bool {
+	firstPath, firstErr := filepath.Abs(strings.TrimSpace(first))
+	secondPath, secondErr := filepath.Abs(strings.TrimSpace(second))
+	return firstErr == nil && secondErr == nil && firstPath == secondPath
+}
+
+// redactJSONDocument re-emits a JSON document, or JSON lines, token by token so member
+// order is kept and large exports are never held in memory as a whole.
+func redactJSONDocument(input io.Reader, output io.Writer) (int, error) {
+	reader := bufio.NewReader(input)
+	if header, _ := reader.Peek(len(utf8BOM)); bytes.Equal(header, utf8BOM) {
+		if _, err := reader.Discard(len(utf8BOM)); err != nil {
+			return 0, fmt.Errorf("skip byte order mark: %w", err)
+		}
+	}
+	if firstByte := peekFirstSignificantByte(reader); firstByte != '[' && firstByte != '{' {
+		return 0, fmt.Errorf("redacted copies can only be written for JSON exports")
+	}
+
+	decoder := json.NewDecoder(reader)
+	decoder.UseNumber()
+	writer := bufio.NewWriter(output)
+
+	// Each open object or array counts the tokens written into it; inside objects keys
+	// and values alternate. keepValues is set inside the value of a skipped key, such as
+	// a children array, where neither strings nor numbers are redacted.
+	type openContainer struct {
+		isObject   bool
+		tokenCount int
+		lastKey    string
+		keepValues bool
+	}
+	var containers []openContainer
+	topLevelValues := 0
+	redactions := 0
+
+	for {
+		token, err := decoder.Token()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return redactions, fmt.Errorf("decode json: %w", err)
+		}
+
+		delimiter, isDelimiter := token.(json.Delim)
+		isClosing := isDelimiter && (delimiter == '}' || delimiter == ']')
+		isKey := false
+		keepValues := false
+		switch {
+		case isClosing:
+		case len(containers) == 0:
+			if topLevelValues > 0 {
+				writer.WriteByte('\n')
+			}
+			topLevelValues++
+		default:
+			container := &containers[len(containers)-1]
+			switch {
+			case container.isObject && container.tokenCount%2 == 1:
+				writer.WriteByte(':')
+			case container.tokenCount > 0:
+				writer.WriteByte(',')
+			}
+			isKey = container.isObject && container.tokenCount%2 == 0
+			container.tokenCount++
+			keepValues = container.keepValues || (container.isObject && !isKey && isRedactionSkippedKey(container.lastKey))
+			if key, isString := token.(string); isKey && isString {
+				container.lastKey = key
+			}
+		}
+
+		switch value := token.(type) {
+		case json.Delim:
+			writer.WriteRune(rune(value))
+			if isClosing {
+				containers = containers[:len(containers)-1]
+			} else {
+				containers = append(containers, openContainer{isObject: value == '{', keepValues: keepValues})
+			}
+		case string:
+			if !isKey && !keepValues {
+				var count int
+				value, count = RedactText(value)
+				redactions += count
+			}
+			if err := writeJSONString(writer, value); err != nil {
+				return redactions, err
+			}
+		case json.Number:
+			if !isKey && !keepValues {
+				if redacted, count := RedactText(value.String()); count > 0 {
+					redactions += count
+					if err := writeJSONString(writer, redacted); err != nil {
+						return redactions, err
+					}
+					continue
+				}
+			}
+			writer.WriteString(value.String())
+		case bool:
+			if value {
+				writer.WriteString("true")
+			} else {
+				writer.WriteString("false")
+			}
+		case nil:
+			writer.WriteString("null")
+		}
+	}
+
+	if err := writer.Flush(); err != nil {
+		return redactions, fmt.Errorf("write redacted export: %w", err)
+	}
+
+	return redactions, nil
+}
+
+// writeJSONString quotes value without the HTML escaping json.Marshal applies, so
+// prompts containing <, > or & read the same as in the original export.
+func writeJSONString(writer *bufio.Writer, value string) error {
+	var encoded bytes.Buffer
+	encoder := json.NewEncoder(&encoded)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(value); err != nil {
+		return fmt.Errorf("encode json string: %w", err)
+	}
+
+	_, err := writer.Write(bytes.TrimSuffix(encoded.Bytes(), []byte("\n")))
+	return err
+}
@@ -0,0 +1,212 @@
+package models
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// SavedSearchesFileName is the local store file holding the user's saved searches.
+const SavedSearchesFileName = "saved-searches.json"
+
+// SavedSearches are the named queries the user saved, in the order they were added.
+type SavedSearches struct {
+	UpdatedAt string        `json:"updatedAt,omitempty"`
+	Searches  []SavedSearch `json:"searches"`
+}
+
+// SavedSearch is a named query, see SearchQuery for its syntax.
+type SavedSearch struct {
+	ID        string `json:"id"`
+	Name      string `json:"name"`
+	Query     string `json:"query"`
+	CreatedAt string `json:"createdAt,omitempty"`
+}
+
+// SmartCollection is a saved search evaluated against the loaded conversations.
+type SmartCollection struct {
+	ID            string                   `json:"id"`
+	Name          string                   `json:"name"`
+	Query         string                   `json:"query"`
+	Count         int                      `json:"count"`
+	Conversations []CollectionConversation `json:"conversations"`
+	// Error explains why the query could not be evaluated, for searches saved by an
+	// older version with a syntax this one rejects.
+	Error string `json:"error,omitempty"`
+}
+
+// CollectionConversation identifies a conversation by provider and ID, such as one of
+// a smart collection or one marked for deletion.
+type CollectionConversation struct {
+	Provider       string `json:"provider,omitempty"`
+	ConversationID string `json:"conversationId"`
+}
+
+// AddSavedSearch saves query under name. Saving under an existing name, ignoring case,
+// replaces that search's query and keeps its ID.
+func AddSavedSearch(searches SavedSearches, name string, query string, now time.Time) (SavedSearches, error) {
+	trimmedName := strings.Join(strings.Fields(name), " ")
+	trimmedQuery := strings.TrimSpace(query)
+	if trimmedName == "" {
+		return searches, fmt.Errorf("saved search name is required")
+	}
+	parsedQuery, err := ParseSearchQuery(trimmedQuery)
+	if err != nil {
+		return searches, fmt.Errorf("parse query: %w", err)
+	}
+	if parsedQuery.IsEmpty() {
+		return searches, fmt.Errorf("saved search query is required")
+	}
+
+	updated := append([]SavedSearch(nil), searches.Searches...)
+	timestamp := now.UTC().Format(time.RFC3339)
+	replaced := false
+	for index, search := range updated {
+		if strings.EqualFold(search.Name, trimmedName) {
+			updated[index].Name = trimmedName
+			updated[index].Query = trimmedQuery
+			replaced = true
+			break
+		}
+	}
+	if !replaced {
+		updated = append(updated, SavedSearch{
+			ID:        uniqueSavedSearchID(updated, trimmedName),
+			Name:      trimmedName,
+			Query:     trimmedQuery,
+			CreatedAt: timestamp,
+		})
+	}
+
+	searches.Searches = updated
+	searches.UpdatedAt = timestamp
+	return searches, nil
+}
+
+// RemoveSavedSearch drops the saved search with id.
+func RemoveSavedSearch(searches SavedSearches, id string) SavedSearches {
+	kept := make([]SavedSearch, 0, len(searches.Searches))
+	for _, search := range searches.Searches {
+		if search.ID != id {
+			kept = append(kept, search)
+		}
+	}
+
+	searches.Searches = kept
+	return searches
+}
+
+// uniqueSavedSearchID derives an ID from name, such as "work-code-reviews", with a
+// numeric suffix when another search already uses it.
+func uniqueSavedSearchID(searches []SavedSearch, name string) string {
+	var slug strings.Builder
+	for _, character := range strings.ToLower(name) {
+		switch {
+		case unicode.IsLetter(character) || unicode.IsDigit(character):
+			slug.WriteRune(character)
+		case slug.Len() > 0 && !strings.HasSuffix(slug.String(), "-"):
+			slug.WriteRune('-')
+		}
+	}
+	base := strings.TrimSuffix(slug.String(), "-")
+	if base == "" {
+		base = "search"
+	}
+
+	taken := make(map[string]struct{}, len(searches))
+	for _, search := range searches {
+		taken[search.ID] = struct{}{}
+	}
+	id := base
+	for suffix := 2; ; suffix++ {
+		if _, found := taken[id]; !found {
+			return id
+		}
+		id = base + "-" + strconv.Itoa(suffix)
+	}
+}
+
+// EvaluateSavedSearches runs every saved search against conversations, using
+// annotations for tag: and is: terms.
+func EvaluateSavedSearches(searches SavedSearches, conversations []Conversation, annotations Annotations) []SmartCollection {
+	collections := make([]SmartCollection, 0, len(searches.Searches))
+	for _, search := range searches.Searches {
+		collection := SmartCollection{
+			ID:            search.ID,
+			Name:          search.Name,
+			Query:         search.Query,
+			Conversations: []CollectionConversation{},
+		}
+		query, err := ParseSearchQuery(search.Query)
+		if err != nil {
+			collection.Error = err.Error()
+			collections = append(collections, collection)
+			continue
+		}
+
+		collection.Conversations = SelectConversations(conversations, query, annotations)
+		collection.Count = len(collection.Conversations)
+		collections = append(collections, collection)
+	}
+
+	return collections
+}
+
+// SelectConversations returns the conversations matching query, using annotations for
+// tag: and is: terms.
+func SelectConversations(conversations []Conversation, query SearchQuery, annotations Annotations) []CollectionConversation {
+	annotationsByKey := make(map[string]*ConversationAnnotation, len(annotations.Conversations))
+	for index := range annotations.Conversations {
+		annotation := &annotations.Conversations[index]
+		annotationsByKey[annotation.Provider+"\x00"+annotation.ConversationID] = annotation
+	}
+
+	selected := []CollectionConversation{}
+	for _, conversation := range conversations {
+		if query.Matches(conversation, annotationsByKey[conversation.Provider+"\x00"+conversation.ID]) {
+			selected = append(selected, CollectionConversation{
+				Provider:       conversation.Provider,
+				ConversationID: conversation.ID,
+			})
+		}
+	}
+
+	return selected
+}
+
+// LoadSavedSearches reads the saved searches from store. A missing file is an empty
+// list.
+func LoadSavedSearches(store *LocalStore) (SavedSearches, error) {
+	data, err := store.ReadFile(SavedSearchesFileName)
+	if errors.Is(err, fs.ErrNotExist) {
+		return SavedSearches{Searches: []SavedSearch{}}, nil
+	}
+	if err != nil {
+		return SavedSearches{}, fmt.Errorf("read saved searches: %w", err)
+	}
+
+	var searches SavedSearches
+	if err := json.Unmarshal(data, &searches); err != nil {
+		return SavedSearches{}, fmt.Errorf("decode saved searches: %w", err)
+	}
+	if searches.Searches == nil {
+		searches.Searches = []SavedSearch{}
+	}
+
+	return searches, nil
+}
+
+// SaveSavedSearches writes searches to store.
+func SaveSavedSearches(store *LocalStore, searches SavedSearches) error {
+	data, err := json.MarshalIndent(searches, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encode saved searches: %w", err)
+	}
+
+	return store.WriteFile(SavedSearchesFileName, data)
+}
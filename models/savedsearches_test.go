@@ -0,0 +1,100 @@
+package models
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestAddSavedSearch(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	searches := SavedSearches{Searches: []SavedSearch{}}
+
+	searches, err := AddSavedSearch(searches, " Work  code reviews ", "tag:work review", now)
+	if err != nil {
+		t.Fatalf("AddSavedSearch returned error: %v", err)
+	}
+	searches, _ = AddSavedSearch(searches, "Work: code reviews!", "tag:work", now)
+	searches, _ = AddSavedSearch(searches, "work code REVIEWS", "tag:work has:code", now)
+
+	ids := []string{}
+	for _, search := range searches.Searches {
+		ids = append(ids, search.ID)
+	}
+	if !reflect.DeepEqual(ids, []string{"work-code-reviews", "work-code-reviews-2"}) {
+		t.Fatalf("unexpected saved search ids %v", ids)
+	}
+	if first := searches.Searches[0]; first.Name != "work code REVIEWS" || first.Query != "tag:work has:code" {
+		t.Fatalf("expected the search to be replaced by name, got %+v", first)
+	}
+
+	for _, invalid := range []struct{ name, query string }{
+		{name: "", query: "tag:work"},
+		{name: "Empty", query: "  "},
+		{name: "Broken", query: "is:pinned"},
+	} {
+		if _, err := AddSavedSearch(searches, invalid.name, invalid.query, now); err == nil {
+			t.Fatalf("expected AddSavedSearch(%q, %q) to fail", invalid.name, invalid.query)
+		}
+	}
+
+	searches = RemoveSavedSearch(searches, "work-code-reviews")
+	if len(searches.Searches) != 1 || searches.Searches[0].ID != "work-code-reviews-2" {
+		t.Fatalf("unexpected searches after removal %+v", searches.Searches)
+	}
+}
+
+func TestEvaluateSavedSearches(t *testing.T) {
+	conversations := []Conversation{
+		{Provider: ProviderClaude, ID: "c1", Name: "Knee pain after running"},
+		{Provider: ProviderChatGPT, ID: "c1", Name: "Running shoes"},
+		{Provider: ProviderChatGPT, ID: "c2", Name: "Quarterly report"},
+	}
+	annotations := Annotations{Conversations: []ConversationAnnotation{
+		{Provider: ProviderClaude, ConversationID: "c1", Tags: []string{"health"}},
+	}}
+	searches := SavedSearches{Searches: []SavedSearch{
+		{ID: "health", Name: "Health questions", Query: "tag:health"},
+		{ID: "running", Name: "Running", Query: "running"},
+		{ID: "broken", Name: "Broken", Query: "is:pinned"},
+	}}
+
+	collections := EvaluateSavedSearches(searches, conversations, annotations)
+	if len(collections) != 3 {
+		t.Fatalf("expected 3 collections, got %+v", collections)
+	}
+	if got := collections[0].Conversations; !reflect.DeepEqual(got, []CollectionConversation{{Provider: ProviderClaude, ConversationID: "c1"}}) {
+		t.Fatalf("tags must match by provider and id, got %+v", got)
+	}
+	if collections[1].Count != 2 || collections[1].Name != "Running" {
+		t.Fatalf("unexpected running collection %+v", collections[1])
+	}
+	if collections[2].Error == "" || collections[2].Count != 0 || collections[2].Conversations == nil {
+		t.Fatalf("expected an error for an invalid saved query, got %+v", collections[2])
+	}
+}
+
+func TestSavedSearchesRoundTrip(t *testing.T) {
+	store, err := OpenLocalStore(filepath.Join(t.TempDir(), "chat-explorer"))
+	if err != nil {
+		t.Fatalf("OpenLocalStore returned error: %v", err)
+	}
+
+	empty, err := LoadSavedSearches(store)
+	if err != nil || empty.Searches == nil || len(empty.Searches) != 0 {
+		t.Fatalf("expected no saved searches, got %+v (%v)", empty, err)
+	}
+
+	searches := SavedSearches{
+		UpdatedAt: "2026-03-01T12:00:00Z",
+		Searches:  []SavedSearch{{ID: "health", Name: "Health questions", Query: "tag:health", CreatedAt: "2026-03-01T12:00:00Z"}},
+	}
+	if err := SaveSavedSearches(store, searches); err != nil {
+		t.Fatalf("SaveSavedSearches returned error: %v", err)
+	}
+	loaded, err := LoadSavedSearches(store)
+	if err != nil || !reflect.DeepEqual(loaded, searches) {
+		t.Fatalf("expected %+v, got %+v (%v)", searches, loaded, err)
+	}
+}
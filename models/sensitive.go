@@ -0,0 +1,323 @@
+package models
+
+import (
+	"math/big"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// Severities rank how much harm a leaked value can do: critical values grant access to
+// accounts or infrastructure, low ones only help identify a person.
+const (
+	SeverityCritical = "critical"
+	SeverityHigh     = "high"
+	SeverityMedium   = "medium"
+	SeverityLow      = "low"
+)
+
+// Kinds of sensitive data the scanner recognizes.
+const (
+	SensitivePrivateKey    = "private_key"
+	SensitiveAWSCredential = "aws_credential"
+	SensitiveGCPCredential = "gcp_credential"
+	SensitiveAPIKey        = "api_key"
+	SensitiveJWT           = "jwt"
+	SensitiveCreditCard    = "credit_card"
+	SensitiveNationalID    = "national_id"
+	SensitiveIBAN          = "iban"
+	SensitivePhoneNumber   = "phone_number"
+	SensitiveEmail         = "email"
+)
+
+// SensitiveFinding is a secret or piece of personal data found in a message or in one
+// of its attachments.
+type SensitiveFinding struct {
+	Type             string `json:"type"`
+	Severity         string `json:"severity"`
+	Provider         string `json:"provider,omitempty"`
+	ConversationID   string `json:"conversationId"`
+	ConversationName string `json:"conversationName"`
+	// MessageIndex is the position of the message within its conversation.
+	MessageIndex     int    `json:"messageIndex"`
+	Speaker          string `json:"speaker"`
+	MessageTimestamp string `json:"messageTimestamp"`
+	// Attachment names the attachment the value was found in; it is blank for values in
+	// the message text.
+	Attachment string `json:"attachment,omitempty"`
+	// Start and End are byte offsets of the value in the message text or attachment
+	// content. Line and Column locate it for people, counting from 1 in runes.
+	Start  int `json:"start"`
+	End    int `json:"end"`
+	Line   int `json:"line"`
+	Column int `json:"column"`
+	// Preview is the value with everything but its first and last characters masked.
+	Preview string `json:"preview"`
+}
+
+var severityRanks = map[string]int{SeverityCritical: 0, SeverityHigh: 1, SeverityMedium: 2, SeverityLow: 3}
+
+type sensitiveDetector struct {
+	kind     string
+	severity string
+	pattern  *regexp.Regexp
+	// valueGroup selects the capture group holding the value; 0 uses the whole match.
+	valueGroup int
+	// validate rejects values that only look like the kind, such as card numbers that
+	// fail the Luhn check.
+	validate func(value string) bool
+}
+
+// sensitiveDetectors run in order and never report text an earlier detector already
+// matched, so a credential is not reported again as the phone number inside it. The
+// check-digit validated IBAN runs before the looser card and phone patterns.
+var sensitiveDetectors = []sensitiveDetector{
+	{
+		kind:     SensitivePrivateKey,
+		severity: SeverityCritical,
+		pattern:  regexp.MustCompile(`-----BEGIN (?:[A-Z0-9]+ )*PRIVATE KEY(?: BLOCK)?-----(?:[\s\S]*?-----END (?:[A-Z0-9]+ )*PRIVATE KEY(?: BLOCK)?-----)?`),
+	},
+	{kind: SensitiveAWSCredential, severity: SeverityCritical, pattern: regexp.MustCompile(`\b(?:AKIA|ASIA|ABIA|ACCA)[A-Z0-9]{16}\b`)},
+	{
+		kind:       SensitiveAWSCredential,
+		severity:   SeverityCritical,
+		pattern:    regexp.MustCompile(`(?i)aws_?secret_?access_?key["']?\s*[:=]\s*["']?([A-Za-z0-9/+]{40})(?:[^A-Za-z0-9/+]|$)`),
+		valueGroup: 1,
+	},
+	{kind: SensitiveGCPCredential, severity: SeverityCritical, pattern: regexp.MustCompile(`\bAIza[0-9A-Za-z_-]{35}\b`)},
+	{kind: SensitiveGCPCredential, severity: SeverityCritical, pattern: regexp.MustCompile(`\bGOCSPX-[0-9A-Za-z_-]{28}\b`)},
+	{
+		kind:       SensitiveGCPCredential,
+		severity:   SeverityCritical,
+		pattern:    regexp.MustCompile(`"private_key_id"\s*:\s*"([0-9a-f]{40})"`),
+		valueGroup: 1,
+	},
+	{kind: SensitiveAPIKey, severity: SeverityHigh, pattern: regexp.MustCompile(`\bsk-(?:ant-|proj-|svcacct-|admin-)?[A-Za-z0-9_-]{20,}`)},
+	{kind: SensitiveAPIKey, severity: SeverityHigh, pattern: regexp.MustCompile(`\b(?:gh[pousr]_[A-Za-z0-9]{36,}|github_pat_[A-Za-z0-9_]{22,})\b`)},
+	{kind: SensitiveAPIKey, severity: SeverityHigh, pattern: regexp.MustCompile(`\bxox[abposr]-[A-Za-z0-9-]{10,}`)},
+	{kind: SensitiveAPIKey, severity: SeverityHigh, pattern: regexp.MustCompile(`\b[rs]k_(?:live|test)_[A-Za-z0-9]{16,}\b`)},
+	{kind: SensitiveAPIKey, severity: SeverityHigh, pattern: regexp.MustCompile(`\bhf_[A-Za-z0-9]{30,}\b`)},
+	{kind: SensitiveJWT, severity: SeverityHigh, pattern: regexp.MustCompile(`\beyJ[A-Za-z0-9_-]{5,}\.eyJ[A-Za-z0-9_-]{5,}\.[A-Za-z0-9_-]{10,}`)},
+	{
+		kind:     SensitiveIBAN,
+		severity: SeverityMedium,
+		pattern:  regexp.MustCompile(`\b[A-Z]{2}\d{2}(?: ?[A-Z0-9]{4}){2,7}(?: ?[A-Z0-9]{1,3})?\b`),
+		validate: isIBAN,
+	},
+	{kind: SensitiveCreditCard, severity: SeverityHigh, pattern: regexp.MustCompile(`\b\d(?:[ -]?\d){12,18}\b`), validate: isCreditCardNumber},
+	{kind: SensitiveNationalID, severity: SeverityHigh, pattern: regexp.MustCompile(`\b\d{3}-\d{2}-\d{4}\b`), validate: isUSSocialSecurityNumber},
+	{
+		kind:     SensitiveNationalID,
+		severity: SeverityHigh,
+		pattern:  regexp.MustCompile(`\b[A-CEGHJ-PR-TW-Z][A-CEGHJ-NPR-TW-Z] ?\d{2} ?\d{2} ?\d{2} ?[A-D]\b`),
+		validate: isUKNationalInsuranceNumber,
+	},
+	{
+		kind:     SensitivePhoneNumber,
+		severity: SeverityMedium,
+		pattern:  regexp.MustCompile(`\+\d{1,3}[ .-]?(?:\(\d{1,4}\)[ .-]?)?\d{1,4}(?:[ .-]?\d{2,4}){1,4}\b`),
+		validate: isInternationalPhoneNumber,
+	},
+	{kind: SensitivePhoneNumber, severity: SeverityMedium, pattern: regexp.MustCompile(`(?:\(\d{3}\) ?|\b\d{3}[ .-])\d{3}[ .-]\d{4}\b`)},
+	{
+		kind:     SensitiveEmail,
+		severity: SeverityLow,
+		pattern:  regexp.MustCompile(`\b[A-Za-z0-9._%+-]+@[A-Za-z0-9-]+(?:\.[A-Za-z0-9-]+)*\.[A-Za-z]{2,}\b`),
+		validate: isPersonalEmail,
+	},
+}
+
+// placeholderEmailDomains are reserved for documentation and never reach a person.
+// Entries match the domain itself and its subdomains.
+var placeholderEmailDomains = []string{"example.com", "example.org", "example.net", "example", "test", "invalid", "localhost"}
+
+// ukNationalInsuranceInvalidPrefixes are prefixes HMRC never allocates.
+var ukNationalInsuranceInvalidPrefixes = map[string]bool{"BG": true, "GB": true, "KN": true, "NK": true, "NT": true, "TN": true, "ZZ": true}
+
+// sensitiveMatch is a detected value in a text, as byte offsets.
+type sensitiveMatch struct {
+	kind     string
+	severity string
+	start    int
+	end      int
+}
+
+// ScanSensitiveData looks for credentials and personal data in every message and
+// attachment. Findings are ordered by severity, then by position in the export.
+func ScanSensitiveData(conversations []Conversation) []SensitiveFinding {
+	findings := make([]SensitiveFinding, 0, 16)
+	for _, conversation := range conversations {
+		for messageIndex, message := range conversation.Messages {
+			addFindings := func(text string, attachment string) {
+				for _, match := range findSensitiveMatches(text) {
+					line, column := textPosition(text, match.start)
+					findings = append(findings, SensitiveFinding{
+						Type:             match.kind,
+						Severity:         match.severity,
+						Provider:         conversation.Provider,
+						ConversationID:   conversation.ID,
+						ConversationName: conversation.Name,
+						MessageIndex:     messageIndex,
+						Speaker:          message.Speaker,
+						MessageTimestamp: message.Timestamp,
+						Attachment:       attachment,
+						Start:            match.start,
+						End:              match.end,
+						Line:             line,
+						Column:           column,
+						Preview:          maskSensitiveValue(text[match.start:match.end]),
+					})
+				}
+			}
+
+			addFindings(message.Text, "")
+			for attachmentIndex, attachment := range message.Attachments {
+				addFindings(attachment.Content, firstNonBlank(attachment.Name, "attachment "+strconv.Itoa(attachmentIndex+1)))
+			}
+		}
+	}
+
+	sort.SliceStable(findings, func(i, j int) bool {
+		return severityRanks[findings[i].Severity] < severityRanks[findings[j].Severity]
+	})
+
+	return findings
+}
+
+// findSensitiveMatches returns the non-overlapping sensitive values in text, in order.
+func findSensitiveMatches(text string) []sensitiveMatch {
+	if strings.TrimSpace(text) == "" {
+		return nil
+	}
+
+	var matches []sensitiveMatch
+	for _, detector := range sensitiveDetectors {
+		for _, submatch := range detector.pattern.FindAllStringSubmatchIndex(text, -1) {
+			start, end := submatch[2*detector.valueGroup], submatch[2*detector.valueGroup+1]
+			if start < 0 || overlapsSensitiveMatch(matches, start, end) {
+				continue
+			}
+			if detector.validate != nil && !detector.validate(text[start:end]) {
+				continue
+			}
+			matches = append(matches, sensitiveMatch{kind: detector.kind, severity: detector.severity, start: start, end: end})
+		}
+	}
+
+	sort.Slice(matches, func(i, j int) bool {
+		return matches[i].start < matches[j].start
+	})
+
+	return matches
+}
+
+func overlapsSensitiveMatch(matches []sensitiveMatch, start int, end int) bool {
+	for _, match := range matches {
+		if start < match.end && match.start < end {
+			return true
+		}
+	}
+
+	return false
+}
+
+// textPosition converts a byte offset into a 1-based line and rune column.
+func textPosition(text string, offset int) (int, int) {
+	before := text[:offset]
+	lineStart := strings.LastIndexByte(before, '\n') + 1
+	return strings.Count(before, "\n") + 1, utf8.RuneCountInString(before[lineStart:]) + 1
+}
+
+// maskSensitiveValue keeps just enough of a value to recognize it.
+func maskSensitiveValue(value string) string {
+	runes := []rune(value)
+	if len(runes) <= 8 {
+		return strings.Repeat("•", len(runes))
+	}
+
+	return string(runes[:4]) + "…" + string(runes[len(runes)-2:])
+}
+
+func onlyDigits(value string) string {
+	return strings.Map(func(r rune) rune {
+		if r >= '0' && r <= '9' {
+			return r
+		}
+		return -1
+	}, value)
+}
+
+// isCreditCardNumber accepts numbers of a card network's length and prefix that pass
+// the Luhn check.
+func isCreditCardNumber(value string) bool {
+	digits := onlyDigits(value)
+	if len(digits) < 13 || len(digits) > 19 || !strings.ContainsAny(digits[:1], "23456") {
+		return false
+	}
+
+	sum := 0
+	for index := len(digits) - 1; index >= 0; index-- {
+		digit := int(digits[index] - '0')
+		if (len(digits)-1-index)%2 == 1 {
+			digit *= 2
+			if digit > 9 {
+				digit -= 9
+			}
+		}
+		sum += digit
+	}
+
+	return sum%10 == 0
+}
+
+// isUSSocialSecurityNumber rejects numbers the SSA never issues: area 000, 666 or
+// 900-999, group 00 and serial 0000.
+func isUSSocialSecurityNumber(value string) bool {
+	digits := onlyDigits(value)
+	area, group, serial := digits[:3], digits[3:5], digits[5:]
+	return area != "000" && area != "666" && area[0] != '9' && group != "00" && serial != "0000"
+}
+
+func isUKNationalInsuranceNumber(value string) bool {
+	return !ukNationalInsuranceInvalidPrefixes[value[:2]]
+}
+
+// isIBAN verifies the ISO 13616 check digits: the rearranged number, with letters
+// spelled as 10-35, is 1 modulo 97.
+func isIBAN(value string) bool {
+	compact := strings.ReplaceAll(value, " ", "")
+	if len(compact) < 15 || len(compact) > 34 {
+		return false
+	}
+
+	var numeric strings.Builder
+	for _, r := range compact[4:] + compact[:4] {
+		if r >= 'A' && r <= 'Z' {
+			numeric.WriteString(strconv.Itoa(int(r-'A') + 10))
+			continue
+		}
+		numeric.WriteRune(r)
+	}
+
+	number, ok := new(big.Int).SetString(numeric.String(), 10)
+	return ok && new(big.Int).Mod(number, big.NewInt(97)).Int64() == 1
+}
+
+// isInternationalPhoneNumber accepts E.164 lengths, country code included.
+func isInternationalPhoneNumber(value string) bool {
+	digitCount := len(onlyDigits(value))
+	return digitCount >= 8 && digitCount <= 15
+}
+
+func isPersonalEmail(value string) bool {
+	domain := strings.ToLower(value[strings.LastIndexByte(value, '@')+1:])
+	for _, placeholder := range placeholderEmailDomains {
+		if domain == placeholder || strings.HasSuffix(domain, "."+placeholder) {
+			return false
+		}
+	}
+
+	return true
+}
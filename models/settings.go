@@ -0,0 +1,189 @@
+package models
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// SettingsFileName is the local store file holding the application settings.
+const SettingsFileName = "settings.json"
+
+// Theme preferences.
+const (
+	ThemeSystem = "system"
+	ThemeLight  = "light"
+	ThemeDark   = "dark"
+)
+
+// Conversation sort modes, matching the frontend's sort button.
+const (
+	SortNameAscending     = "name-asc"
+	SortNameDescending    = "name-desc"
+	SortCreatedAscending  = "created-asc"
+	SortCreatedDescending = "created-desc"
+)
+
+// maxRecentPaths bounds the opened paths remembered in the settings.
+const maxRecentPaths = 10
+
+// Window sizes below these are ignored so a restore never opens an unusable window.
+const (
+	minWindowWidth  = 640
+	minWindowHeight = 480
+)
+
+// Settings are the preferences and session state restored when the app starts.
+type Settings struct {
+	// LastOpenedPaths lists the exports opened most recently, newest first.
+	LastOpenedPaths []string `json:"lastOpenedPaths"`
+	SortMode        string   `json:"sortMode"`
+	// ExpandedConversations are the frontend panel keys of the expanded conversations
+	// of the most recently opened export.
+	ExpandedConversations []string       `json:"expandedConversations"`
+	Window                WindowSettings `json:"window"`
+	Theme                 string         `json:"theme"`
+}
+
+// WindowSettings is the size of the main window; zero means the default size.
+type WindowSettings struct {
+	Width  int `json:"width,omitempty"`
+	Height int `json:"height,omitempty"`
+}
+
+// Session is what the frontend restores when it starts: the settings, and the export
+// of the last session when it could be reopened.
+type Session struct {
+	Settings   Settings            `json:"settings"`
+	SourcePath string              `json:"sourcePath,omitempty"`
+	Entries    []ConversationEntry `json:"entries"`
+	// RestoreError explains why the export of the last session was not reopened.
+	RestoreError string `json:"restoreError,omitempty"`
+}
+
+// DefaultSettings returns the settings of a first start.
+func DefaultSettings() Settings {
+	return Settings{
+		LastOpenedPaths:       []string{},
+		SortMode:              SortCreatedAscending,
+		ExpandedConversations: []string{},
+		Theme:                 ThemeSystem,
+	}
+}
+
+// NormalizeSettings replaces unknown sort modes and themes with the defaults, drops
+// blank and duplicate paths and keys, and forgets window sizes too small to use.
+func NormalizeSettings(settings Settings) Settings {
+	defaults := DefaultSettings()
+	switch settings.SortMode {
+	case SortNameAscending, SortNameDescending, SortCreatedAscending, SortCreatedDescending:
+	default:
+		settings.SortMode = defaults.SortMode
+	}
+	switch settings.Theme {
+	case ThemeSystem, ThemeLight, ThemeDark:
+	default:
+		settings.Theme = defaults.Theme
+	}
+	if settings.Window.Width < minWindowWidth || settings.Window.Height < minWindowHeight {
+		settings.Window = WindowSettings{}
+	}
+
+	settings.LastOpenedPaths = uniqueNonBlank(settings.LastOpenedPaths)
+	if len(settings.LastOpenedPaths) > maxRecentPaths {
+		settings.LastOpenedPaths = settings.LastOpenedPaths[:maxRecentPaths]
+	}
+	settings.ExpandedConversations = uniqueNonBlank(settings.ExpandedConversations)
+
+	return settings
+}
+
+// RememberOpenedPath moves path to the front of the opened paths. Opening another
+// export than the last one forgets the expanded conversations, which belong to it.
+func RememberOpenedPath(settings Settings, path string) Settings {
+	trimmedPath := strings.TrimSpace(path)
+	if trimmedPath == "" {
+		return settings
+	}
+
+	if len(settings.LastOpenedPaths) == 0 || settings.LastOpenedPaths[0] != trimmedPath {
+		settings.ExpandedConversations = []string{}
+	}
+	settings.LastOpenedPaths = append([]string{trimmedPath}, settings.LastOpenedPaths...)
+
+	return NormalizeSettings(settings)
+}
+
+// RecentFile is one of the recently opened exports.
+type RecentFile struct {
+	Path string `json:"path"`
+	Name string `json:"name"`
+	// Exists is false once the file was moved or deleted.
+	Exists bool `json:"exists"`
+}
+
+// RecentFiles lists the opened paths of settings, newest first, noting which still
+// exist.
+func RecentFiles(settings Settings) []RecentFile {
+	files := make([]RecentFile, 0, len(settings.LastOpenedPaths))
+	for _, path := range settings.LastOpenedPaths {
+		info, err := os.Stat(path)
+		files = append(files, RecentFile{
+			Path:   path,
+			Name:   filepath.Base(path),
+			Exists: err == nil && !info.IsDir(),
+		})
+	}
+
+	return files
+}
+
+func uniqueNonBlank(values []string) []string {
+	unique := make([]string, 0, len(values))
+	seen := make(map[string]struct{}, len(values))
+	for _, value := range values {
+		trimmedValue := strings.TrimSpace(value)
+		if trimmedValue == "" {
+			continue
+		}
+		if _, duplicate := seen[trimmedValue]; duplicate {
+			continue
+		}
+		seen[trimmedValue] = struct{}{}
+		unique = append(unique, trimmedValue)
+	}
+
+	return unique
+}
+
+// LoadSettings reads the settings from store. A missing file gives the defaults.
+func LoadSettings(store *LocalStore) (Settings, error) {
+	data, err := store.ReadFile(SettingsFileName)
+	if errors.Is(err, fs.ErrNotExist) {
+		return DefaultSettings(), nil
+	}
+	if err != nil {
+		return DefaultSettings(), fmt.Errorf("read settings: %w", err)
+	}
+
+	settings := DefaultSettings()
+	if err := json.Unmarshal(data, &settings); err != nil {
+		return DefaultSettings(), fmt.Errorf("decode settings: %w", err)
+	}
+
+	return NormalizeSettings(settings), nil
+}
+
+// SaveSettings writes settings to store.
+func SaveSettings(store *LocalStore, settings Settings) error {
+	data, err := json.MarshalIndent(NormalizeSettings(settings), "", "  ")
+	if err != nil {
+		return fmt.Errorf("encode settings: %w", err)
+	}
+
+	return store.WriteFile(SettingsFileName, data)
+}
@@ -0,0 +1,134 @@
+package models
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestNormalizeSettings(t *testing.T) {
+	tests := []struct {
+		name     string
+		settings Settings
+		want     Settings
+	}{
+		{
+			name:     "zero value gets the defaults",
+			settings: Settings{},
+			want:     DefaultSettings(),
+		},
+		{
+			name: "keeps valid settings",
+			settings: Settings{
+				LastOpenedPaths:       []string{"/exports/b.zip", "/exports/a.json"},
+				SortMode:              SortNameDescending,
+				ExpandedConversations: []string{"id:conv-1"},
+				Window:                WindowSettings{Width: 1280, Height: 900},
+				Theme:                 ThemeDark,
+			},
+			want: Settings{
+				LastOpenedPaths:       []string{"/exports/b.zip", "/exports/a.json"},
+				SortMode:              SortNameDescending,
+				ExpandedConversations: []string{"id:conv-1"},
+				Window:                WindowSettings{Width: 1280, Height: 900},
+				Theme:                 ThemeDark,
+			},
+		},
+		{
+			name: "drops unknown values and unusable windows",
+			settings: Settings{
+				LastOpenedPaths:       []string{" /exports/a.json ", "", "/exports/a.json"},
+				SortMode:              "random",
+				ExpandedConversations: []string{"id:conv-1", "id:conv-1", " "},
+				Window:                WindowSettings{Width: 200, Height: 900},
+				Theme:                 "solarized",
+			},
+			want: Settings{
+				LastOpenedPaths:       []string{"/exports/a.json"},
+				SortMode:              SortCreatedAscending,
+				ExpandedConversations: []string{"id:conv-1"},
+				Theme:                 ThemeSystem,
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := NormalizeSettings(test.settings); !reflect.DeepEqual(got, test.want) {
+				t.Fatalf("expected %+v, got %+v", test.want, got)
+			}
+		})
+	}
+}
+
+func TestRememberOpenedPath(t *testing.T) {
+	settings := DefaultSettings()
+	for index := 0; index < maxRecentPaths+2; index++ {
+		settings = RememberOpenedPath(settings, fmt.Sprintf("/exports/%02d.zip", index))
+	}
+	if len(settings.LastOpenedPaths) != maxRecentPaths || settings.LastOpenedPaths[0] != "/exports/11.zip" {
+		t.Fatalf("unexpected recent paths %v", settings.LastOpenedPaths)
+	}
+
+	settings.ExpandedConversations = []string{"id:conv-1"}
+	settings = RememberOpenedPath(settings, " /exports/11.zip ")
+	if !reflect.DeepEqual(settings.ExpandedConversations, []string{"id:conv-1"}) {
+		t.Fatalf("reopening the last export must keep the expanded conversations, got %v", settings.ExpandedConversations)
+	}
+
+	settings = RememberOpenedPath(settings, "/exports/05.zip")
+	if settings.LastOpenedPaths[0] != "/exports/05.zip" || settings.LastOpenedPaths[1] != "/exports/11.zip" {
+		t.Fatalf("expected the reopened path to move to the front, got %v", settings.LastOpenedPaths)
+	}
+	if len(settings.ExpandedConversations) != 0 {
+		t.Fatalf("opening another export must forget the expanded conversations, got %v", settings.ExpandedConversations)
+	}
+}
+
+func TestRecentFiles(t *testing.T) {
+	dir := t.TempDir()
+	existing := filepath.Join(dir, "export.zip")
+	if err := os.WriteFile(existing, []byte("PK"), 0o600); err != nil {
+		t.Fatalf("write fixture: %v", err)
+	}
+	missing := filepath.Join(dir, "moved.json")
+
+	files := RecentFiles(Settings{LastOpenedPaths: []string{existing, missing, dir}})
+	want := []RecentFile{
+		{Path: existing, Name: "export.zip", Exists: true},
+		{Path: missing, Name: "moved.json", Exists: false},
+		{Path: dir, Name: filepath.Base(dir), Exists: false},
+	}
+	if !reflect.DeepEqual(files, want) {
+		t.Fatalf("expected %+v, got %+v", want, files)
+	}
+}
+
+func TestSettingsRoundTrip(t *testing.T) {
+	store, err := OpenLocalStore(filepath.Join(t.TempDir(), "chat-explorer"))
+	if err != nil {
+		t.Fatalf("OpenLocalStore returned error: %v", err)
+	}
+
+	loaded, err := LoadSettings(store)
+	if err != nil || !reflect.DeepEqual(loaded, DefaultSettings()) {
+		t.Fatalf("expected the default settings, got %+v (%v)", loaded, err)
+	}
+
+	settings := Settings{
+		LastOpenedPaths:       []string{"/exports/a.json"},
+		SortMode:              SortNameAscending,
+		ExpandedConversations: []string{"id:conv-1"},
+		Window:                WindowSettings{Width: 1280, Height: 900},
+		Theme:                 ThemeLight,
+	}
+	if err := SaveSettings(store, settings); err != nil {
+		t.Fatalf("SaveSettings returned error: %v", err)
+	}
+	loaded, err = LoadSettings(store)
+	if err != nil || !reflect.DeepEqual(loaded, settings) {
+		t.Fatalf("expected %+v, got %+v (%v)", settings, loaded, err)
+	}
+}
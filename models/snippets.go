@@ -0,0 +1,293 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+const plainTextLanguage = "text"
+
+// Snippet is a block of code found in a message: a fenced Markdown block, or a whole
+// message whose content is code.
+type Snippet struct {
+	Provider         string `json:"provider,omitempty"`
+	ConversationID   string `json:"conversationId"`
+	ConversationName string `json:"conversationName"`
+	// MessageIndex is the position of the message within its conversation.
+	MessageIndex     int    `json:"messageIndex"`
+	Speaker          string `json:"speaker"`
+	MessageTimestamp string `json:"messageTimestamp"`
+	Language         string `json:"language"`
+	Code             string `json:"code"`
+}
+
+// languageAliases folds common fence info strings onto one language name.
+var languageAliases = map[string]string{
+	"py": "python", "python3": "python",
+	"js": "javascript", "jsx": "javascript", "node": "javascript",
+	"ts": "typescript", "tsx": "typescript",
+	"sh": "shell", "bash": "shell", "zsh": "shell", "console": "shell",
+	"golang": "go",
+	"c++":    "cpp", "cxx": "cpp",
+	"c#": "csharp", "cs": "csharp",
+	"rs": "rust", "rb": "ruby", "kt": "kotlin", "yml": "yaml",
+	"ps1": "powershell", "pwsh": "powershell",
+	"md": "markdown", "plaintext": plainTextLanguage, "txt": plainTextLanguage, "unknown": plainTextLanguage,
+}
+
+// languageExtensions are the file extensions used when exporting snippets.
+var languageExtensions = map[string]string{
+	"python": ".py", "javascript": ".js", "typescript": ".ts", "shell": ".sh", "go": ".go",
+	"c": ".c", "cpp": ".cpp", "csharp": ".cs", "java": ".java", "rust": ".rs", "ruby": ".rb",
+	"kotlin": ".kt", "swift": ".swift", "php": ".php", "sql": ".sql", "html": ".html",
+	"css": ".css", "json": ".json", "yaml": ".yaml", "toml": ".toml", "xml": ".xml",
+	"markdown": ".md", "powershell": ".ps1", "r": ".r", "lua": ".lua", "dockerfile": ".dockerfile",
+}
+
+var unsafePathCharacters = regexp.MustCompile(`[^a-z0-9._-]+`)
+
+// ExtractSnippets returns every snippet of the conversations in message order.
+func ExtractSnippets(conversations []Conversation) []Snippet {
+	snippets := make([]Snippet, 0, 16)
+	for _, conversation := range conversations {
+		for messageIndex, message := range conversation.Messages {
+			newSnippet := func(language string, code string) Snippet {
+				return Snippet{
+					Provider:         conversation.Provider,
+					ConversationID:   conversation.ID,
+					ConversationName: conversation.Name,
+					MessageIndex:     messageIndex,
+					Speaker:          message.Speaker,
+					MessageTimestamp: message.Timestamp,
+					Language:         normalizeLanguage(language),
+					Code:             code,
+				}
+			}
+
+			if message.CodeLanguage != "" {
+				if strings.TrimSpace(message.Text) != "" {
+					snippets = append(snippets, newSnippet(message.CodeLanguage, message.Text))
+				}
+				continue
+			}
+
+			for _, block := range extractFencedCodeBlocks(message.Text) {
+				snippets = append(snippets, newSnippet(block.language, block.code))
+			}
+		}
+	}
+
+	return snippets
+}
+
+// SearchSnippets keeps the snippets in language (any language when blank) whose code,
+// language or conversation name contains query, ignoring case.
+func SearchSnippets(snippets []Snippet, query string, language string) []Snippet {
+	normalizedQuery := strings.ToLower(strings.TrimSpace(query))
+	normalizedLanguage := ""
+	if strings.TrimSpace(language) != "" {
+		normalizedLanguage = normalizeLanguage(language)
+	}
+
+	matches := make([]Snippet, 0, len(snippets))
+	for _, snippet := range snippets {
+		if normalizedLanguage != "" && snippet.Language != normalizedLanguage {
+			continue
+		}
+		if normalizedQuery != "" &&
+			!strings.Contains(strings.ToLower(snippet.Code), normalizedQuery) &&
+			!strings.Contains(snippet.Language, normalizedQuery) &&
+			!strings.Contains(strings.ToLower(snippet.ConversationName), normalizedQuery) {
+			continue
+		}
+		matches = append(matches, snippet)
+	}
+
+	return matches
+}
+
+// ExportSnippets writes each snippet to dir/<language>/<conversation>-<message>.<ext>
+// and returns the number of files written. Existing files are never overwritten: a
+// numbered suffix is added instead.
+func ExportSnippets(snippets []Snippet, dir string) (int, error) {
+	trimmedDir := strings.TrimSpace(dir)
+	if trimmedDir == "" {
+		return 0, fmt.Errorf("export directory is required")
+	}
+
+	for index, snippet := range snippets {
+		language := sanitizePathSegment(snippet.Language, plainTextLanguage)
+		languageDir := filepath.Join(trimmedDir, language)
+		if err := os.MkdirAll(languageDir, 0o755); err != nil {
+			return index, fmt.Errorf("create snippet directory %s: %w", languageDir, err)
+		}
+
+		baseName := fmt.Sprintf(
+			"%s-%03d",
+			sanitizePathSegment(firstNonBlank(snippet.ConversationName, snippet.ConversationID), "conversation"),
+			snippet.MessageIndex+1,
+		)
+		if err := writeNewSnippetFile(languageDir, baseName, snippetExtension(language), snippet.Code); err != nil {
+			return index, err
+		}
+	}
+
+	return len(snippets), nil
+}
+
+type fencedCodeBlock struct {
+	language string
+	code     string
+}
+
+// extractFencedCodeBlocks finds Markdown code fences (``` or ~~~, indented by at most
+// three spaces). As in CommonMark, an unclosed fence runs to the end of the text.
+func extractFencedCodeBlocks(text string) []fencedCodeBlock {
+	if !strings.Contains(text, "```") && !strings.Contains(text, "~~~") {
+		return nil
+	}
+
+	blocks := make([]fencedCodeBlock, 0, 2)
+	var (
+		inBlock    bool
+		fence      string
+		language   string
+		blockLines []string
+	)
+	for _, line := range strings.Split(text, "\n") {
+		marker, info, isFence := parseCodeFence(line)
+		if !inBlock {
+			if isFence {
+				inBlock, fence, blockLines = true, marker, nil
+				language = ""
+				if fields := strings.Fields(info); len(fields) > 0 {
+					language = fields[0]
+				}
+			}
+			continue
+		}
+
+		if isFence && strings.TrimSpace(info) == "" && marker[0] == fence[0] && len(marker) >= len(fence) {
+			blocks = appendCodeBlock(blocks, language, blockLines)
+			inBlock = false
+			continue
+		}
+		blockLines = append(blockLines, strings.TrimSuffix(line, "\r"))
+	}
+	if inBlock {
+		blocks = appendCodeBlock(blocks, language, blockLines)
+	}
+
+	return blocks
+}
+
+func appendCodeBlock(blocks []fencedCodeBlock, language string, lines []string) []fencedCodeBlock {
+	code := strings.TrimRight(strings.Join(lines, "\n"), " \t\n")
+	if strings.TrimSpace(code) == "" {
+		return blocks
+	}
+
+	return append(blocks, fencedCodeBlock{language: language, code: code})
+}
+
+// parseCodeFence reports whether line opens or closes a code fence, returning the fence
+// marker and the info string after it.
+func parseCodeFence(line string) (string, string, bool) {
+	trimmedLine := strings.TrimRight(line, "\r")
+	indentation := len(trimmedLine) - len(strings.TrimLeft(trimmedLine, " "))
+	if indentation > 3 {
+		return "", "", false
+	}
+	trimmedLine = trimmedLine[indentation:]
+
+	for _, fenceCharacter := range []byte{'`', '~'} {
+		markerLength := 0
+		for markerLength < len(trimmedLine) && trimmedLine[markerLength] == fenceCharacter {
+			markerLength++
+		}
+		if markerLength < 3 {
+			continue
+		}
+
+		info := trimmedLine[markerLength:]
+		// A backtick fence's info string may not contain backticks (it would be inline code).
+		if fenceCharacter == '`' && strings.Contains(info, "`") {
+			return "", "", false
+		}
+		return trimmedLine[:markerLength], strings.TrimSpace(info), true
+	}
+
+	return "", "", false
+}
+
+func normalizeLanguage(language string) string {
+	normalizedLanguage := strings.ToLower(strings.TrimSpace(language))
+	if normalizedLanguage == "" {
+		return plainTextLanguage
+	}
+	if alias, exists := languageAliases[normalizedLanguage]; exists {
+		return alias
+	}
+
+	return normalizedLanguage
+}
+
+func snippetExtension(language string) string {
+	if extension, exists := languageExtensions[language]; exists {
+		return extension
+	}
+
+	return ".txt"
+}
+
+func sanitizePathSegment(value string, fallback string) string {
+	segment := strings.Trim(unsafePathCharacters.ReplaceAllString(strings.ToLower(value), "-"), "-.")
+	if segment == "" {
+		return fallback
+	}
+	if len(segment) > 60 {
+		segment = strings.TrimRight(segment[:60], "-.")
+	}
+
+	return segment
+}
+
+// writeNewSnippetFile writes code to dir/<baseName><extension>, or to the first
+// dir/<baseName>-<n><extension> that does not exist yet.
+func writeNewSnippetFile(dir string, baseName string, extension string, code string) error {
+	path := filepath.Join(dir, baseName+extension)
+	for suffix := 2; ; suffix++ {
+		file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o644)
+		if errors.Is(err, fs.ErrExist) {
+			path = filepath.Join(dir, fmt.Sprintf("%s-%d%s", baseName, suffix, extension))
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("create snippet %s: %w", path, err)
+		}
+
+		_, writeErr := file.WriteString(ensureTrailingNewline(code))
+		closeErr := file.Close()
+		if writeErr != nil {
+			return fmt.Errorf("write snippet %s: %w", path, writeErr)
+		}
+		if closeErr != nil {
+			return fmt.Errorf("close snippet %s: %w", path, closeErr)
+		}
+
+		return nil
+	}
+}
+
+func ensureTrailingNewline(text string) string {
+	if strings.HasSuffix(text, "\n") {
+		return text
+	}
+
+	return text + "\n"
+}
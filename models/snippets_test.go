@@ -0,0 +1,220 @@
+package models
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestExtractFencedCodeBlocks(t *testing.T) {
+	tests := []struct {
+		name     string
+		text     string
+		expected []fencedCodeBlock
+	}{
+		{name: "no fences", text: "Just prose with `inline` code.", expected: nil},
+		{
+			name:     "language and prose around",
+			text:     "Try this:\n```go\nfmt.Println(\"hi\")\n```\nDone.",
+			expected: []fencedCodeBlock{{language: "go", code: `fmt.Println("hi")`}},
+		},
+		{
+			name: "several blocks without language",
+			text: "```\nfirst\n```\nand\n~~~ python title=x\nsecond\n~~~",
+			expected: []fencedCodeBlock{
+				{language: "", code: "first"},
+				{language: "python", code: "second"},
+			},
+		},
+		{
+			name:     "longer fence keeps shorter fences inside",
+			text:     "````markdown\n```js\nx()\n```\n````",
+			expected: []fencedCodeBlock{{language: "markdown", code: "```js\nx()\n```"}},
+		},
+		{
+			name:     "unclosed fence runs to the end",
+			text:     "```sh\nls -la\n",
+			expected: []fencedCodeBlock{{language: "sh", code: "ls -la"}},
+		},
+		{
+			name:     "windows line endings",
+			text:     "```py\r\nprint(1)\r\n```\r\n",
+			expected: []fencedCodeBlock{{language: "py", code: "print(1)"}},
+		},
+		{name: "empty block", text: "```go\n\n```", expected: []fencedCodeBlock{}},
+		{name: "indented four spaces is not a fence", text: "    ```go\n    x\n    ```", expected: []fencedCodeBlock{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := extractFencedCodeBlocks(tt.text)
+			if len(got) == 0 && len(tt.expected) == 0 {
+				return
+			}
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Fatalf("expected %+v, got %+v", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestExtractSnippets(t *testing.T) {
+	input := `[
+		{
+			"title": "Plotting",
+			"conversation_id": "cgpt-code",
+			"current_node": "answer",
+			"mapping": {
+				"prompt": {
+					"id": "prompt", "parent": null, "children": ["call"],
+					"message": {"author": {"role": "user"}, "content": {"content_type": "text", "parts": ["Plot y = x^2"]}}
+				},
+				"call": {
+					"id": "call", "parent": "prompt", "children": ["answer"],
+					"message": {"author": {"role": "assistant"}, "recipient": "python", "content": {"content_type": "code", "language": "unknown", "text": "import matplotlib.pyplot as plt\nplt.plot([1, 2], [1, 4])"}}
+				},
+				"answer": {
+					"id": "answer", "parent": "call", "children": [],
+					"message": {"author": {"role": "assistant"}, "recipient": "all", "content": {"content_type": "text", "parts": ["Run it yourself with:\n` + "```bash" + `\npython plot.py\n` + "```" + `"]}}
+				}
+			}
+		}
+	]`
+
+	result, err := ParseConversations(strings.NewReader(input), ParseOptions{})
+	if err != nil {
+		t.Fatalf("ParseConversations returned error: %v", err)
+	}
+
+	got := ExtractSnippets(result.Conversations)
+	expected := []Snippet{
+		{
+			Provider:         ProviderChatGPT,
+			ConversationID:   "cgpt-code",
+			ConversationName: "Plotting",
+			MessageIndex:     1,
+			Speaker:          "assistant",
+			MessageTimestamp: got[0].MessageTimestamp,
+			Language:         "python",
+			Code:             "import matplotlib.pyplot as plt\nplt.plot([1, 2], [1, 4])",
+		},
+		{
+			Provider:         ProviderChatGPT,
+			ConversationID:   "cgpt-code",
+			ConversationName: "Plotting",
+			MessageIndex:     2,
+			Speaker:          "assistant",
+			MessageTimestamp: got[1].MessageTimestamp,
+			Language:         "shell",
+			Code:             "python plot.py",
+		},
+	}
+	if !reflect.DeepEqual(got, expected) {
+		t.Fatalf("unexpected snippets:\n got: %+v\nwant: %+v", got, expected)
+	}
+}
+
+func TestSearchSnippets(t *testing.T) {
+	snippets := []Snippet{
+		{ConversationName: "Plotting", Language: "python", Code: "plt.plot(x, y)"},
+		{ConversationName: "Deploy", Language: "shell", Code: "kubectl apply -f app.yaml"},
+		{ConversationName: "Python tips", Language: "text", Code: "pip install rich"},
+	}
+
+	tests := []struct {
+		name     string
+		query    string
+		language string
+		expected []string
+	}{
+		{name: "everything", expected: []string{"Plotting", "Deploy", "Python tips"}},
+		{name: "code match ignores case", query: "KUBECTL", expected: []string{"Deploy"}},
+		{name: "language or conversation name", query: "python", expected: []string{"Plotting", "Python tips"}},
+		{name: "language filter uses aliases", language: "py", expected: []string{"Plotting"}},
+		{name: "query and language", query: "pip", language: "python", expected: []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			names := make([]string, 0)
+			for _, snippet := range SearchSnippets(snippets, tt.query, tt.language) {
+				names = append(names, snippet.ConversationName)
+			}
+			if !reflect.DeepEqual(names, tt.expected) {
+				t.Fatalf("expected %v, got %v", tt.expected, names)
+			}
+		})
+	}
+}
+
+func TestExportSnippets(t *testing.T) {
+	dir := t.TempDir()
+	snippets := []Snippet{
+		{ConversationID: "conv-1", ConversationName: "Plot: y = x²!", MessageIndex: 1, Language: "python", Code: "print(1)"},
+		{ConversationID: "conv-1", ConversationName: "Plot: y = x²!", MessageIndex: 1, Language: "python", Code: "print(2)\n"},
+		{ConversationID: "conv-2", MessageIndex: 0, Language: "../weird lang", Code: "???"},
+	}
+
+	count, err := ExportSnippets(snippets, dir)
+	if err != nil {
+		t.Fatalf("ExportSnippets returned error: %v", err)
+	}
+	if count != 3 {
+		t.Fatalf("expected 3 snippets written, got %d", count)
+	}
+
+	expectedFiles := map[string]string{
+		filepath.Join("python", "plot-y-x-002.py"):    "print(1)\n",
+		filepath.Join("python", "plot-y-x-002-2.py"):  "print(2)\n",
+		filepath.Join("weird-lang", "conv-2-001.txt"): "???\n",
+	}
+	for relativePath, expectedContent := range expectedFiles {
+		content, readErr := os.ReadFile(filepath.Join(dir, relativePath))
+		if readErr != nil {
+			t.Fatalf("expected %s to be written: %v", relativePath, readErr)
+		}
+		if string(content) != expectedContent {
+			t.Fatalf("%s: expected %q, got %q", relativePath, expectedContent, string(content))
+		}
+	}
+}
+
+func TestExportSnippetsKeepsExistingFiles(t *testing.T) {
+	dir := t.TempDir()
+	existingPath := filepath.Join(dir, "go", "notes-001.go")
+	if err := os.MkdirAll(filepath.Dir(existingPath), 0o755); err != nil {
+		t.Fatalf("MkdirAll returned error: %v", err)
+	}
+	if err := os.WriteFile(existingPath, []byte("keep me\n"), 0o644); err != nil {
+		t.Fatalf("WriteFile returned error: %v", err)
+	}
+
+	snippet := Snippet{ConversationID: "conv-1", ConversationName: "Notes", Language: "go", Code: "package main"}
+	for run := 0; run < 2; run++ {
+		if _, err := ExportSnippets([]Snippet{snippet}, dir); err != nil {
+			t.Fatalf("ExportSnippets returned error: %v", err)
+		}
+	}
+
+	expectedFiles := map[string]string{
+		"notes-001.go":   "keep me\n",
+		"notes-001-2.go": "package main\n",
+		"notes-001-3.go": "package main\n",
+	}
+	for name, expectedContent := range expectedFiles {
+		content, err := os.ReadFile(filepath.Join(dir, "go", name))
+		if err != nil {
+			t.Fatalf("expected %s to exist: %v", name, err)
+		}
+		if string(content) != expectedContent {
+			t.Fatalf("%s: expected %q, got %q", name, expectedContent, string(content))
+		}
+	}
+}
+
+func TestExportSnippetsRequiresDirectory(t *testing.T) {
+	_, err := ExportSnippets([]Snippet{{Code: "x"}}, "  ")
+	assertErrorContains(t, err, "export directory is required")
+}
@@ -0,0 +1,202 @@
+package models
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+const longestConversationsLimit = 10
+
+// Statistics aggregates usage over a set of conversations. Periods are computed in the
+// location passed to ComputeStatistics; messages without a usable timestamp fall back
+// to their conversation's CreatedAt and are left out of the time series otherwise.
+type Statistics struct {
+	ConversationCount         int                  `json:"conversationCount"`
+	MessageCount              int                  `json:"messageCount"`
+	TokenCount                int                  `json:"tokenCount"`
+	AverageConversationLength float64              `json:"averageConversationLength"`
+	Daily                     []PeriodCount        `json:"daily"`
+	Weekly                    []PeriodCount        `json:"weekly"`
+	Monthly                   []PeriodCount        `json:"monthly"`
+	MessagesPerSpeaker        []LabelCount         `json:"messagesPerSpeaker"`
+	MessagesPerModel          []LabelCount         `json:"messagesPerModel"`
+	BusiestHours              []HourCount          `json:"busiestHours"`
+	LongestConversations      []ConversationLength `json:"longestConversations"`
+}
+
+// PeriodCount counts the conversations started, and the messages and tokens sent, in one period. Period
+// is "2006-01-02" for days, the ISO week ("2006-W01") for weeks and "2006-01" for months.
+type PeriodCount struct {
+	Period        string `json:"period"`
+	Conversations int    `json:"conversations"`
+	Messages      int    `json:"messages"`
+	Tokens        int    `json:"tokens"`
+}
+
+// LabelCount counts the messages and tokens sharing a label such as a speaker or model.
+type LabelCount struct {
+	Label    string `json:"label"`
+	Messages int    `json:"messages"`
+	Tokens   int    `json:"tokens"`
+}
+
+// HourCount counts the messages sent during one hour of the day (0-23).
+type HourCount struct {
+	Hour     int `json:"hour"`
+	Messages int `json:"messages"`
+}
+
+// ConversationLength identifies a conversation and its number of messages.
+type ConversationLength struct {
+	Provider       string `json:"provider,omitempty"`
+	ConversationID string `json:"conversationId"`
+	Name           string `json:"name"`
+	Messages       int    `json:"messages"`
+	Tokens         int    `json:"tokens"`
+}
+
+type periodKey func(time.Time) string
+
+var statisticsPeriods = []periodKey{dayPeriod, weekPeriod, monthPeriod}
+
+// ComputeStatistics aggregates the conversations, bucketing timestamps in location.
+func ComputeStatistics(conversations []Conversation, location *time.Location) Statistics {
+	if location == nil {
+		location = time.UTC
+	}
+
+	periodCounts := make([]map[string]*PeriodCount, len(statisticsPeriods))
+	for index := range periodCounts {
+		periodCounts[index] = make(map[string]*PeriodCount)
+	}
+	countPeriods := func(moment time.Time, conversations int, messages int, tokens int) {
+		for index, period := range statisticsPeriods {
+			key := period(moment)
+			count, exists := periodCounts[index][key]
+			if !exists {
+				count = &PeriodCount{Period: key}
+				periodCounts[index][key] = count
+			}
+			count.Conversations += conversations
+			count.Messages += messages
+			count.Tokens += tokens
+		}
+	}
+
+	statistics := Statistics{BusiestHours: make([]HourCount, 24)}
+	for hour := range statistics.BusiestHours {
+		statistics.BusiestHours[hour].Hour = hour
+	}
+	speakerCounts := make(map[string]*LabelCount)
+	modelCounts := make(map[string]*LabelCount)
+	lengths := make([]ConversationLength, 0, len(conversations))
+
+	for _, conversation := range conversations {
+		statistics.ConversationCount++
+		statistics.MessageCount += len(conversation.Messages)
+		statistics.TokenCount += conversation.TokenCount
+		lengths = append(lengths, ConversationLength{
+			Provider:       conversation.Provider,
+			ConversationID: conversation.ID,
+			Name:           conversation.Name,
+			Messages:       len(conversation.Messages),
+			Tokens:         conversation.TokenCount,
+		})
+
+		conversationTime, hasConversationTime := parseTimestamp(conversation.CreatedAt)
+		if hasConversationTime {
+			countPeriods(conversationTime.In(location), 1, 0, 0)
+		}
+
+		for _, message := range conversation.Messages {
+			countLabel(speakerCounts, message.Speaker, message.TokenCount)
+			if model := strings.TrimSpace(message.Model); model != "" {
+				countLabel(modelCounts, model, message.TokenCount)
+			}
+
+			messageTime, hasMessageTime := parseTimestamp(message.Timestamp)
+			if !hasMessageTime {
+				messageTime, hasMessageTime = conversationTime, hasConversationTime
+			}
+			if !hasMessageTime {
+				continue
+			}
+
+			localTime := messageTime.In(location)
+			countPeriods(localTime, 0, 1, message.TokenCount)
+			statistics.BusiestHours[localTime.Hour()].Messages++
+		}
+	}
+
+	if statistics.ConversationCount > 0 {
+		statistics.AverageConversationLength = float64(statistics.MessageCount) / float64(statistics.ConversationCount)
+	}
+	statistics.Daily = sortedPeriodCounts(periodCounts[0])
+	statistics.Weekly = sortedPeriodCounts(periodCounts[1])
+	statistics.Monthly = sortedPeriodCounts(periodCounts[2])
+	statistics.MessagesPerSpeaker = sortedLabelCounts(speakerCounts)
+	statistics.MessagesPerModel = sortedLabelCounts(modelCounts)
+
+	sort.SliceStable(lengths, func(i, j int) bool {
+		return lengths[i].Messages > lengths[j].Messages
+	})
+	if len(lengths) > longestConversationsLimit {
+		lengths = lengths[:longestConversationsLimit]
+	}
+	statistics.LongestConversations = lengths
+
+	return statistics
+}
+
+func dayPeriod(moment time.Time) string {
+	return moment.Format("2006-01-02")
+}
+
+func weekPeriod(moment time.Time) string {
+	year, week := moment.ISOWeek()
+	return fmt.Sprintf("%04d-W%02d", year, week)
+}
+
+func monthPeriod(moment time.Time) string {
+	return moment.Format("2006-01")
+}
+
+func sortedPeriodCounts(counts map[string]*PeriodCount) []PeriodCount {
+	periods := make([]PeriodCount, 0, len(counts))
+	for _, count := range counts {
+		periods = append(periods, *count)
+	}
+	sort.Slice(periods, func(i, j int) bool {
+		return periods[i].Period < periods[j].Period
+	})
+
+	return periods
+}
+
+func countLabel(counts map[string]*LabelCount, label string, tokens int) {
+	count, exists := counts[label]
+	if !exists {
+		count = &LabelCount{Label: label}
+		counts[label] = count
+	}
+	count.Messages++
+	count.Tokens += tokens
+}
+
+// sortedLabelCounts orders labels by message count, then alphabetically.
+func sortedLabelCounts(counts map[string]*LabelCount) []LabelCount {
+	labels := make([]LabelCount, 0, len(counts))
+	for _, count := range counts {
+		labels = append(labels, *count)
+	}
+	sort.Slice(labels, func(i, j int) bool {
+		if labels[i].Messages != labels[j].Messages {
+			return labels[i].Messages > labels[j].Messages
+		}
+		return labels[i].Label < labels[j].Label
+	})
+
+	return labels
+}
@@ -0,0 +1,138 @@
+package models
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestComputeStatistics(t *testing.T) {
+	conversations := []Conversation{
+		{
+			Provider:   ProviderChatGPT,
+			ID:         "long",
+			Name:       "Long",
+			CreatedAt:  "2024-01-01T23:30:00Z",
+			TokenCount: 100,
+			Messages: []Message{
+				{Speaker: "user", Text: "a", Timestamp: "2024-01-01T23:30:00Z", TokenCount: 10},
+				{Speaker: "assistant", Text: "b", Timestamp: "2024-01-01T23:45:00.5Z", Model: "gpt-4o", TokenCount: 20},
+				{Speaker: "user", Text: "c", Timestamp: "2024-01-08T10:00:00Z", TokenCount: 30},
+				{Speaker: "assistant", Text: "d", Timestamp: "2024-01-08T10:01:00Z", Model: "gpt-4o", TokenCount: 40},
+			},
+		},
+		{
+			Provider:   ProviderClaude,
+			ID:         "short",
+			Name:       "Short",
+			CreatedAt:  "2024-02-03T08:00:00Z",
+			TokenCount: 7,
+			Messages: []Message{
+				{Speaker: "user", Text: "e", TokenCount: 3},
+				{Speaker: "assistant", Text: "f", Timestamp: "2024-02-03T08:05:00Z", Model: "claude-3-opus", TokenCount: 4},
+			},
+		},
+		{
+			Provider: ProviderCopilot,
+			ID:       "undated",
+			Name:     "Undated",
+			Messages: []Message{{Speaker: "user", Text: "g"}},
+		},
+	}
+
+	location := time.FixedZone("UTC+1", 60*60)
+	got := ComputeStatistics(conversations, location)
+
+	if got.ConversationCount != 3 || got.MessageCount != 7 || got.TokenCount != 107 {
+		t.Fatalf("unexpected totals: %d conversations, %d messages, %d tokens", got.ConversationCount, got.MessageCount, got.TokenCount)
+	}
+	if got.AverageConversationLength != 7.0/3.0 {
+		t.Fatalf("unexpected average conversation length %v", got.AverageConversationLength)
+	}
+
+	// 23:30 UTC on Jan 1 is already Jan 2 in UTC+1.
+	assertPeriodCounts(t, "daily", got.Daily, []PeriodCount{
+		{Period: "2024-01-02", Conversations: 1, Messages: 2, Tokens: 30},
+		{Period: "2024-01-08", Messages: 2, Tokens: 70},
+		{Period: "2024-02-03", Conversations: 1, Messages: 2, Tokens: 7},
+	})
+	assertPeriodCounts(t, "weekly", got.Weekly, []PeriodCount{
+		{Period: "2024-W01", Conversations: 1, Messages: 2, Tokens: 30},
+		{Period: "2024-W02", Messages: 2, Tokens: 70},
+		{Period: "2024-W05", Conversations: 1, Messages: 2, Tokens: 7},
+	})
+	assertPeriodCounts(t, "monthly", got.Monthly, []PeriodCount{
+		{Period: "2024-01", Conversations: 1, Messages: 4, Tokens: 100},
+		{Period: "2024-02", Conversations: 1, Messages: 2, Tokens: 7},
+	})
+
+	if expected := []LabelCount{{Label: "user", Messages: 4, Tokens: 43}, {Label: "assistant", Messages: 3, Tokens: 64}}; !reflect.DeepEqual(got.MessagesPerSpeaker, expected) {
+		t.Fatalf("unexpected speaker counts %+v", got.MessagesPerSpeaker)
+	}
+	if expected := []LabelCount{{Label: "gpt-4o", Messages: 2, Tokens: 60}, {Label: "claude-3-opus", Messages: 1, Tokens: 4}}; !reflect.DeepEqual(got.MessagesPerModel, expected) {
+		t.Fatalf("unexpected model counts %+v", got.MessagesPerModel)
+	}
+
+	if len(got.BusiestHours) != 24 {
+		t.Fatalf("expected 24 hour buckets, got %d", len(got.BusiestHours))
+	}
+	hourMessages := map[int]int{}
+	for _, hour := range got.BusiestHours {
+		if hour.Messages > 0 {
+			hourMessages[hour.Hour] = hour.Messages
+		}
+	}
+	if expected := map[int]int{0: 2, 11: 2, 9: 2}; !reflect.DeepEqual(hourMessages, expected) {
+		t.Fatalf("unexpected busiest hours %+v", hourMessages)
+	}
+
+	expectedLongest := []ConversationLength{
+		{Provider: ProviderChatGPT, ConversationID: "long", Name: "Long", Messages: 4, Tokens: 100},
+		{Provider: ProviderClaude, ConversationID: "short", Name: "Short", Messages: 2, Tokens: 7},
+		{Provider: ProviderCopilot, ConversationID: "undated", Name: "Undated", Messages: 1},
+	}
+	if !reflect.DeepEqual(got.LongestConversations, expectedLongest) {
+		t.Fatalf("unexpected longest conversations %+v", got.LongestConversations)
+	}
+}
+
+func TestComputeStatisticsEmpty(t *testing.T) {
+	got := ComputeStatistics(nil, nil)
+
+	if got.ConversationCount != 0 || got.MessageCount != 0 || got.AverageConversationLength != 0 {
+		t.Fatalf("expected zero totals, got %+v", got)
+	}
+	if len(got.Daily) != 0 || len(got.LongestConversations) != 0 {
+		t.Fatalf("expected empty series, got %+v", got)
+	}
+	if len(got.BusiestHours) != 24 {
+		t.Fatalf("expected 24 hour buckets, got %d", len(got.BusiestHours))
+	}
+}
+
+func TestComputeStatisticsLimitsLongestConversations(t *testing.T) {
+	conversations := make([]Conversation, 0, longestConversationsLimit+2)
+	for index := 0; index < longestConversationsLimit+2; index++ {
+		messages := make([]Message, index+1)
+		for messageIndex := range messages {
+			messages[messageIndex] = Message{Speaker: "user", Text: "hi"}
+		}
+		conversations = append(conversations, Conversation{ID: string(rune('a' + index)), Messages: messages})
+	}
+
+	got := ComputeStatistics(conversations, time.UTC)
+	if len(got.LongestConversations) != longestConversationsLimit {
+		t.Fatalf("expected %d longest conversations, got %d", longestConversationsLimit, len(got.LongestConversations))
+	}
+	if got.LongestConversations[0].Messages != longestConversationsLimit+2 {
+		t.Fatalf("expected the longest conversation first, got %+v", got.LongestConversations[0])
+	}
+}
+
+func assertPeriodCounts(t *testing.T, name string, got []PeriodCount, expected []PeriodCount) {
+	t.Helper()
+
+	if !reflect.DeepEqual(got, expected) {
+		t.Fatalf("unexpected %s counts:\n got: %+v\nwant: %+v", name, got, expected)
+	}
+}
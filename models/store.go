@@ -0,0 +1,406 @@
+package models
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/chacha20poly1305"
+)
+
+// vaultFileName holds the key derivation parameters of an encrypted store. Its presence
+// is what marks the store as encrypted.
+const vaultFileName = "vault.json"
+
+// vaultVerifier is encrypted with the derived key so Unlock can tell a wrong passphrase
+// from a right one without touching any stored data.
+const vaultVerifier = "chat-explorer vault"
+
+// encryptedFileMagic prefixes every encrypted file, followed by the nonce and the
+// XChaCha20-Poly1305 ciphertext.
+var encryptedFileMagic = []byte("CEV1")
+
+var (
+	// ErrStoreLocked is returned when an encrypted store is used before Unlock.
+	ErrStoreLocked = errors.New("local library is locked")
+	// ErrIncorrectPassphrase is returned when a passphrase does not open the store.
+	ErrIncorrectPassphrase = errors.New("incorrect passphrase")
+)
+
+// argon2Parameters are the Argon2id cost settings, saved with the salt so they can be
+// raised later without locking out existing stores.
+type argon2Parameters struct {
+	Time    uint32 `json:"time"`
+	Memory  uint32 `json:"memoryKiB"`
+	Threads uint8  `json:"threads"`
+}
+
+// defaultArgon2Parameters follow the RFC 9106 recommendation for memory-constrained
+// environments.
+var defaultArgon2Parameters = argon2Parameters{Time: 3, Memory: 64 * 1024, Threads: 4}
+
+// maxArgon2Parameters bound the cost read from vault.json, so a tampered header cannot
+// make Unlock exhaust the machine's memory or run for hours.
+var maxArgon2Parameters = argon2Parameters{Time: 16, Memory: 1024 * 1024, Threads: 16}
+
+// validate checks the parameters are within the RFC 9106 minimums and
+// maxArgon2Parameters.
+func (params argon2Parameters) validate() error {
+	switch {
+	case params.Time < 1 || params.Time > maxArgon2Parameters.Time:
+		return fmt.Errorf("argon2 time %d is outside 1 to %d", params.Time, maxArgon2Parameters.Time)
+	case params.Threads < 1 || params.Threads > maxArgon2Parameters.Threads:
+		return fmt.Errorf("argon2 threads %d is outside 1 to %d", params.Threads, maxArgon2Parameters.Threads)
+	case params.Memory < 8*uint32(params.Threads) || params.Memory > maxArgon2Parameters.Memory:
+		return fmt.Errorf("argon2 memory %d KiB is outside %d to %d KiB", params.Memory, 8*uint32(params.Threads), maxArgon2Parameters.Memory)
+	}
+
+	return nil
+}
+
+// vaultHeader is the content of vault.json. Verifier authenticates every other field,
+// so the header cannot be changed without the passphrase.
+type vaultHeader struct {
+	Version  int              `json:"version"`
+	KDF      string           `json:"kdf"`
+	Params   argon2Parameters `json:"params"`
+	Salt     []byte           `json:"salt"`
+	Verifier []byte           `json:"verifier"`
+	// Migrated is set once every file that predates encryption has been encrypted. From
+	// then on a plain file in the store is refused rather than trusted.
+	Migrated bool `json:"migrated"`
+}
+
+// authenticatedData returns the additional data the verifier is sealed with: every
+// header field but the verifier itself.
+func (header *vaultHeader) authenticatedData() string {
+	fields := *header
+	fields.Verifier = nil
+	// Marshalling a struct of plain values cannot fail.
+	data, _ := json.Marshal(fields)
+
+	return vaultFileName + "\x00" + string(data)
+}
+
+// sealVerifier replaces the verifier with vaultVerifier sealed under key. It is called
+// after every change to the header.
+func (header *vaultHeader) sealVerifier(key []byte) error {
+	verifier, err := seal(key, []byte(vaultVerifier), header.authenticatedData())
+	if err != nil {
+		return err
+	}
+	header.Verifier = verifier
+
+	return nil
+}
+
+// verify checks that key opens the verifier. A header whose Migrated flag was reset
+// after the migration finished is reported as modified rather than trusted.
+func (header *vaultHeader) verify(key []byte) error {
+	verifier, err := openSealed(key, header.Verifier, header.authenticatedData())
+	if err == nil && string(verifier) == vaultVerifier {
+		return nil
+	}
+
+	if !header.Migrated {
+		migrated := *header
+		migrated.Migrated = true
+		if _, err := openSealed(key, header.Verifier, migrated.authenticatedData()); err == nil {
+			return fmt.Errorf("%s was modified: the library was already encrypted", vaultFileName)
+		}
+	}
+
+	return ErrIncorrectPassphrase
+}
+
+// LocalStore keeps the files the app persists in one directory. Once encryption is
+// enabled every file is sealed with XChaCha20-Poly1305 under a key derived from a
+// passphrase with Argon2id, and the store must be unlocked before it is used.
+type LocalStore struct {
+	dir string
+
+	mu     sync.RWMutex
+	header *vaultHeader
+	key    []byte
+}
+
+// StoreStatus reports whether the local store is encrypted and still locked.
+type StoreStatus struct {
+	Encrypted bool `json:"encrypted"`
+	Locked    bool `json:"locked"`
+}
+
+// OpenLocalStore opens the store kept in dir. The directory is created on first write.
+func OpenLocalStore(dir string) (*LocalStore, error) {
+	store := &LocalStore{dir: dir}
+	data, err := os.ReadFile(filepath.Join(dir, vaultFileName))
+	if errors.Is(err, fs.ErrNotExist) {
+		return store, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read %s: %w", vaultFileName, err)
+	}
+
+	var header vaultHeader
+	if err := json.Unmarshal(data, &header); err != nil {
+		return nil, fmt.Errorf("decode %s: %w", vaultFileName, err)
+	}
+	if header.KDF != "argon2id" || len(header.Salt) == 0 || len(header.Verifier) == 0 {
+		return nil, fmt.Errorf("unsupported %s", vaultFileName)
+	}
+	if err := header.Params.validate(); err != nil {
+		return nil, fmt.Errorf("unsupported %s: %w", vaultFileName, err)
+	}
+	store.header = &header
+
+	return store, nil
+}
+
+// Status reports whether the store is encrypted and locked.
+func (s *LocalStore) Status() StoreStatus {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return StoreStatus{Encrypted: s.header != nil, Locked: s.header != nil && s.key == nil}
+}
+
+// Unlock derives the key from passphrase and checks it against the store. It finishes
+// encrypting the files of a migration that was interrupted.
+func (s *LocalStore) Unlock(passphrase string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.header == nil {
+		return fmt.Errorf("local library is not encrypted")
+	}
+
+	key := deriveStoreKey(passphrase, s.header.Salt, s.header.Params)
+	if err := s.header.verify(key); err != nil {
+		return err
+	}
+	s.key = key
+
+	if !s.header.Migrated {
+		return s.migrateLocked()
+	}
+
+	return nil
+}
+
+// Lock forgets the key until the next Unlock.
+func (s *LocalStore) Lock() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.key = nil
+}
+
+// EnableEncryption protects the store with passphrase and encrypts the files already
+// in it. The store stays unlocked afterwards.
+func (s *LocalStore) EnableEncryption(passphrase string) error {
+	if strings.TrimSpace(passphrase) == "" {
+		return fmt.Errorf("passphrase is required")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.header != nil {
+		return fmt.Errorf("local library is already encrypted")
+	}
+
+	salt := make([]byte, 16)
+	if _, err := rand.Read(salt); err != nil {
+		return fmt.Errorf("generate salt: %w", err)
+	}
+	key := deriveStoreKey(passphrase, salt, defaultArgon2Parameters)
+	header := &vaultHeader{Version: 1, KDF: "argon2id", Params: defaultArgon2Parameters, Salt: salt}
+	if err := header.sealVerifier(key); err != nil {
+		return err
+	}
+
+	// The header is written first: plain files left behind by an interrupted migration
+	// are still readable, and are encrypted on the next Unlock.
+	if err := writeVaultHeader(s.dir, header); err != nil {
+		return err
+	}
+	s.header = header
+	s.key = key
+
+	return s.migrateLocked()
+}
+
+// migrateLocked encrypts every plain file in the store, then records in the header that
+// the migration is complete.
+func (s *LocalStore) migrateLocked() error {
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		return fmt.Errorf("read %s: %w", s.dir, err)
+	}
+	for _, entry := range entries {
+		name := entry.Name()
+		if !entry.Type().IsRegular() || name == vaultFileName || strings.HasPrefix(name, ".") {
+			continue
+		}
+
+		data, err := os.ReadFile(filepath.Join(s.dir, name))
+		if err != nil {
+			return fmt.Errorf("read %s: %w", name, err)
+		}
+		if bytes.HasPrefix(data, encryptedFileMagic) {
+			continue
+		}
+		if err := s.writeLocked(name, data); err != nil {
+			return err
+		}
+	}
+
+	migrated := *s.header
+	migrated.Migrated = true
+	if err := migrated.sealVerifier(s.key); err != nil {
+		return err
+	}
+	if err := writeVaultHeader(s.dir, &migrated); err != nil {
+		return err
+	}
+	s.header = &migrated
+
+	return nil
+}
+
+func writeVaultHeader(dir string, header *vaultHeader) error {
+	headerData, err := json.MarshalIndent(header, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encode %s: %w", vaultFileName, err)
+	}
+
+	return writeFileAtomically(filepath.Join(dir, vaultFileName), headerData)
+}
+
+// ReadFile returns the contents of the named file, decrypted when the store is
+// encrypted. Missing files return an error wrapping fs.ErrNotExist. Once an encrypted
+// store is migrated, plain files are refused: they were not written by the store.
+func (s *LocalStore) ReadFile(name string) ([]byte, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if s.header != nil && s.key == nil {
+		return nil, ErrStoreLocked
+	}
+
+	data, err := os.ReadFile(filepath.Join(s.dir, name))
+	if err != nil {
+		return nil, fmt.Errorf("read %s: %w", name, err)
+	}
+	if !bytes.HasPrefix(data, encryptedFileMagic) {
+		if s.header != nil && s.header.Migrated {
+			return nil, fmt.Errorf("read %s: file is not encrypted but the local library is", name)
+		}
+		return data, nil
+	}
+	if s.key == nil {
+		return nil, fmt.Errorf("read %s: file is encrypted but the local library is not", name)
+	}
+
+	plaintext, err := openSealed(s.key, data[len(encryptedFileMagic):], name)
+	if err != nil {
+		return nil, fmt.Errorf("decrypt %s: %w", name, err)
+	}
+
+	return plaintext, nil
+}
+
+// WriteFile replaces the named file atomically, encrypting it when the store is
+// encrypted.
+func (s *LocalStore) WriteFile(name string, data []byte) error {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.writeLocked(name, data)
+}
+
+func (s *LocalStore) writeLocked(name string, data []byte) error {
+	if s.header == nil {
+		return writeFileAtomically(filepath.Join(s.dir, name), data)
+	}
+	if s.key == nil {
+		return ErrStoreLocked
+	}
+
+	sealed, err := seal(s.key, data, name)
+	if err != nil {
+		return err
+	}
+
+	return writeFileAtomically(filepath.Join(s.dir, name), append(append([]byte(nil), encryptedFileMagic...), sealed...))
+}
+
+func deriveStoreKey(passphrase string, salt []byte, params argon2Parameters) []byte {
+	return argon2.IDKey([]byte(passphrase), salt, params.Time, params.Memory, params.Threads, chacha20poly1305.KeySize)
+}
+
+// seal encrypts plaintext and returns the random nonce followed by the ciphertext.
+// The file name is authenticated so sealed files cannot be swapped for each other.
+func seal(key []byte, plaintext []byte, name string) ([]byte, error) {
+	aead, err := chacha20poly1305.NewX(key)
+	if err != nil {
+		return nil, fmt.Errorf("create cipher: %w", err)
+	}
+
+	nonce := make([]byte, aead.NonceSize(), aead.NonceSize()+len(plaintext)+aead.Overhead())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, fmt.Errorf("generate nonce: %w", err)
+	}
+
+	return aead.Seal(nonce, nonce, plaintext, []byte(name)), nil
+}
+
+func openSealed(key []byte, sealed []byte, name string) ([]byte, error) {
+	aead, err := chacha20poly1305.NewX(key)
+	if err != nil {
+		return nil, fmt.Errorf("create cipher: %w", err)
+	}
+	if len(sealed) < aead.NonceSize() {
+		return nil, fmt.Errorf("ciphertext is truncated")
+	}
+
+	return aead.Open(nil, sealed[:aead.NonceSize()], sealed[aead.NonceSize():], []byte(name))
+}
+
+// writeFileAtomically replaces path through a temporary file in the same directory, so
+// an interrupted save never leaves a truncated file behind. The directory is created
+// readable by the user only.
+func writeFileAtomically(path string, data []byte) error {
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return fmt.Errorf("create %s: %w", dir, err)
+	}
+
+	temporaryFile, err := os.CreateTemp(dir, "."+filepath.Base(path)+".*")
+	if err != nil {
+		return fmt.Errorf("create temporary file: %w", err)
+	}
+	temporaryPath := temporaryFile.Name()
+	_, writeErr := temporaryFile.Write(data)
+	closeErr := temporaryFile.Close()
+	if writeErr == nil {
+		writeErr = closeErr
+	}
+	if writeErr == nil {
+		writeErr = os.Rename(temporaryPath, path)
+	}
+	if writeErr != nil {
+		os.Remove(temporaryPath)
+		return fmt.Errorf("write %s: %w", path, writeErr)
+	}
+
+	return nil
+}
@@ -0,0 +1,231 @@
+package models
+
+import (
+	"bytes"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLocalStorePlain(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "chat-explorer")
+	store, err := OpenLocalStore(dir)
+	if err != nil {
+		t.Fatalf("OpenLocalStore returned error: %v", err)
+	}
+	if status := store.Status(); status.Encrypted || status.Locked {
+		t.Fatalf("expected a plain unlocked store, got %+v", status)
+	}
+
+	if _, err := store.ReadFile("plan.json"); !errors.Is(err, fs.ErrNotExist) {
+		t.Fatalf("expected a not-exist error, got %v", err)
+	}
+	if err := store.WriteFile("plan.json", []byte(`{"items":[]}`)); err != nil {
+		t.Fatalf("WriteFile returned error: %v", err)
+	}
+
+	onDisk, err := os.ReadFile(filepath.Join(dir, "plan.json"))
+	if err != nil || string(onDisk) != `{"items":[]}` {
+		t.Fatalf("expected the plain file on disk, got %q (%v)", onDisk, err)
+	}
+	if err := store.Unlock("secret"); err == nil {
+		t.Fatal("expected Unlock to fail on a plain store")
+	}
+}
+
+func TestLocalStoreEncryption(t *testing.T) {
+	dir := t.TempDir()
+	store, err := OpenLocalStore(dir)
+	if err != nil {
+		t.Fatalf("OpenLocalStore returned error: %v", err)
+	}
+	plaintext := []byte(`{"items":[{"conversationName":"Tax return"}]}`)
+	if err := store.WriteFile("plan.json", plaintext); err != nil {
+		t.Fatalf("WriteFile returned error: %v", err)
+	}
+
+	assertErrorContains(t, store.EnableEncryption("  "), "passphrase is required")
+	if err := store.EnableEncryption("correct horse"); err != nil {
+		t.Fatalf("EnableEncryption returned error: %v", err)
+	}
+	assertErrorContains(t, store.EnableEncryption("correct horse"), "already encrypted")
+	if err := store.WriteFile("settings.json", []byte(`{"theme":"dark"}`)); err != nil {
+		t.Fatalf("WriteFile returned error: %v", err)
+	}
+
+	for _, name := range []string{"plan.json", "settings.json"} {
+		onDisk, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			t.Fatalf("failed to read %s: %v", name, err)
+		}
+		if !bytes.HasPrefix(onDisk, encryptedFileMagic) || bytes.Contains(onDisk, []byte("items")) || bytes.Contains(onDisk, []byte("theme")) {
+			t.Fatalf("expected %s to be encrypted on disk, got %q", name, onDisk)
+		}
+	}
+
+	reopened, err := OpenLocalStore(dir)
+	if err != nil {
+		t.Fatalf("OpenLocalStore returned error: %v", err)
+	}
+	if status := reopened.Status(); !status.Encrypted || !status.Locked {
+		t.Fatalf("expected an encrypted locked store, got %+v", status)
+	}
+	if _, err := reopened.ReadFile("plan.json"); !errors.Is(err, ErrStoreLocked) {
+		t.Fatalf("expected ErrStoreLocked, got %v", err)
+	}
+	if err := reopened.WriteFile("plan.json", plaintext); !errors.Is(err, ErrStoreLocked) {
+		t.Fatalf("expected ErrStoreLocked, got %v", err)
+	}
+	if err := reopened.Unlock("wrong"); !errors.Is(err, ErrIncorrectPassphrase) {
+		t.Fatalf("expected ErrIncorrectPassphrase, got %v", err)
+	}
+	if err := reopened.Unlock("correct horse"); err != nil {
+		t.Fatalf("Unlock returned error: %v", err)
+	}
+
+	got, err := reopened.ReadFile("plan.json")
+	if err != nil {
+		t.Fatalf("ReadFile returned error: %v", err)
+	}
+	if !bytes.Equal(got, plaintext) {
+		t.Fatalf("expected %q, got %q", plaintext, got)
+	}
+
+	// Sealed files are bound to their name, so swapping them is detected.
+	settings, _ := os.ReadFile(filepath.Join(dir, "settings.json"))
+	if err := os.WriteFile(filepath.Join(dir, "plan.json"), settings, 0o600); err != nil {
+		t.Fatalf("failed to swap files: %v", err)
+	}
+	if _, err := reopened.ReadFile("plan.json"); err == nil {
+		t.Fatal("expected a swapped file to fail authentication")
+	}
+
+	// Once migrated, a plain file dropped into the store is not trusted.
+	if err := os.WriteFile(filepath.Join(dir, "annotations.json"), []byte(`{"starred":true}`), 0o600); err != nil {
+		t.Fatalf("failed to write plain file: %v", err)
+	}
+	_, err = reopened.ReadFile("annotations.json")
+	assertErrorContains(t, err, "file is not encrypted")
+
+	reopened.Lock()
+	if status := reopened.Status(); !status.Locked {
+		t.Fatalf("expected the store to be locked again, got %+v", status)
+	}
+}
+
+func TestLocalStoreFinishesInterruptedMigration(t *testing.T) {
+	dir := t.TempDir()
+	store, err := OpenLocalStore(dir)
+	if err != nil {
+		t.Fatalf("OpenLocalStore returned error: %v", err)
+	}
+	if err := store.EnableEncryption("correct horse"); err != nil {
+		t.Fatalf("EnableEncryption returned error: %v", err)
+	}
+
+	// Simulate a migration that stopped after the header was written.
+	header := *store.header
+	header.Migrated = false
+	if err := header.sealVerifier(store.key); err != nil {
+		t.Fatalf("sealVerifier returned error: %v", err)
+	}
+	if err := writeVaultHeader(dir, &header); err != nil {
+		t.Fatalf("writeVaultHeader returned error: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "plan.json"), []byte(`{"items":[]}`), 0o600); err != nil {
+		t.Fatalf("failed to write plain file: %v", err)
+	}
+
+	reopened, err := OpenLocalStore(dir)
+	if err != nil {
+		t.Fatalf("OpenLocalStore returned error: %v", err)
+	}
+	if err := reopened.Unlock("correct horse"); err != nil {
+		t.Fatalf("Unlock returned error: %v", err)
+	}
+
+	onDisk, err := os.ReadFile(filepath.Join(dir, "plan.json"))
+	if err != nil || !bytes.HasPrefix(onDisk, encryptedFileMagic) {
+		t.Fatalf("expected Unlock to encrypt the plain file, got %q (%v)", onDisk, err)
+	}
+	if got, err := reopened.ReadFile("plan.json"); err != nil || string(got) != `{"items":[]}` {
+		t.Fatalf("expected the migrated file to read back, got %q (%v)", got, err)
+	}
+	if !reopened.header.Migrated {
+		t.Fatal("expected the migration to be recorded in the header")
+	}
+}
+
+func TestLocalStoreRejectsModifiedHeader(t *testing.T) {
+	dir := t.TempDir()
+	store, err := OpenLocalStore(dir)
+	if err != nil {
+		t.Fatalf("OpenLocalStore returned error: %v", err)
+	}
+	if err := store.EnableEncryption("correct horse"); err != nil {
+		t.Fatalf("EnableEncryption returned error: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		modify  func(header *vaultHeader)
+		wantErr string
+	}{
+		{name: "reset migration flag", modify: func(header *vaultHeader) { header.Migrated = false }, wantErr: "vault.json was modified"},
+		{name: "changed version", modify: func(header *vaultHeader) { header.Version = 7 }, wantErr: ErrIncorrectPassphrase.Error()},
+		{name: "changed parameters", modify: func(header *vaultHeader) { header.Params.Time++ }, wantErr: ErrIncorrectPassphrase.Error()},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			modifiedDir := t.TempDir()
+			header := *store.header
+			tt.modify(&header)
+			if err := writeVaultHeader(modifiedDir, &header); err != nil {
+				t.Fatalf("writeVaultHeader returned error: %v", err)
+			}
+			// A plain file dropped next to the header must not be trusted.
+			plainPath := filepath.Join(modifiedDir, "plan.json")
+			if err := os.WriteFile(plainPath, []byte(`{"items":[]}`), 0o600); err != nil {
+				t.Fatalf("failed to write plain file: %v", err)
+			}
+
+			modified, err := OpenLocalStore(modifiedDir)
+			if err != nil {
+				t.Fatalf("OpenLocalStore returned error: %v", err)
+			}
+			assertErrorContains(t, modified.Unlock("correct horse"), tt.wantErr)
+			if onDisk, err := os.ReadFile(plainPath); err != nil || bytes.HasPrefix(onDisk, encryptedFileMagic) {
+				t.Fatalf("expected the plain file to be left alone, got %q (%v)", onDisk, err)
+			}
+		})
+	}
+}
+
+func TestOpenLocalStoreValidatesArgon2Parameters(t *testing.T) {
+	tests := []struct {
+		name    string
+		params  argon2Parameters
+		wantErr string
+	}{
+		{name: "zero time", params: argon2Parameters{Time: 0, Memory: 64 * 1024, Threads: 4}, wantErr: "argon2 time 0"},
+		{name: "excessive memory", params: argon2Parameters{Time: 3, Memory: 4 * 1024 * 1024, Threads: 4}, wantErr: "argon2 memory 4194304 KiB"},
+		{name: "too little memory for the threads", params: argon2Parameters{Time: 3, Memory: 16, Threads: 4}, wantErr: "argon2 memory 16 KiB"},
+		{name: "zero threads", params: argon2Parameters{Time: 3, Memory: 64 * 1024, Threads: 0}, wantErr: "argon2 threads 0"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			header := &vaultHeader{Version: 1, KDF: "argon2id", Params: tt.params, Salt: []byte("salt"), Verifier: []byte("verifier")}
+			if err := writeVaultHeader(dir, header); err != nil {
+				t.Fatalf("writeVaultHeader returned error: %v", err)
+			}
+
+			_, err := OpenLocalStore(dir)
+			assertErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
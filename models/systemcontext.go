@@ -0,0 +1,137 @@
+package models
+
+import (
+	"sort"
+	"strings"
+	"time"
+)
+
+const chatGPTUserEditableContextType = "user_editable_context"
+
+// codeFence wraps the user's own text inside ChatGPT's custom-instruction preamble.
+const codeFence = "```"
+
+// CustomInstructionsVersion is one distinct set of custom instructions and the
+// conversations that were started with it.
+type CustomInstructionsVersion struct {
+	UserProfile      string   `json:"userProfile,omitempty"`
+	UserInstructions string   `json:"userInstructions,omitempty"`
+	FirstSeen        string   `json:"firstSeen"`
+	LastSeen         string   `json:"lastSeen"`
+	ConversationIDs  []string `json:"conversationIds"`
+}
+
+// CustomInstructionsTimeline groups conversations by their system context and returns
+// the distinct versions ordered by when they were first seen. Versions whose
+// conversations have no usable timestamp sort last.
+func CustomInstructionsTimeline(conversations []Conversation) []CustomInstructionsVersion {
+	versions := make([]CustomInstructionsVersion, 0, 4)
+	versionIndexes := make(map[SystemContext]int, 4)
+	for _, conversation := range conversations {
+		if conversation.SystemContext == nil {
+			continue
+		}
+
+		context := *conversation.SystemContext
+		index, exists := versionIndexes[context]
+		if !exists {
+			index = len(versions)
+			versionIndexes[context] = index
+			versions = append(versions, CustomInstructionsVersion{
+				UserProfile:      context.UserProfile,
+				UserInstructions: context.UserInstructions,
+			})
+		}
+
+		version := &versions[index]
+		version.FirstSeen = olderTimestamp(version.FirstSeen, conversation.CreatedAt)
+		version.LastSeen = newerTimestamp(version.LastSeen, conversation.CreatedAt)
+		version.ConversationIDs = append(version.ConversationIDs, conversation.ID)
+	}
+
+	sort.SliceStable(versions, func(i, j int) bool {
+		left, leftErr := time.Parse(time.RFC3339Nano, versions[i].FirstSeen)
+		right, rightErr := time.Parse(time.RFC3339Nano, versions[j].FirstSeen)
+		if leftErr != nil || rightErr != nil {
+			return leftErr == nil && rightErr != nil
+		}
+		return left.Before(right)
+	})
+
+	return versions
+}
+
+// extractChatGPTSystemContext reads the hidden user_editable_context message ChatGPT
+// stores at the start of conversations held with custom instructions enabled.
+func extractChatGPTSystemContext(conversation rawChatGPTConversation, nodeIDs []string) *SystemContext {
+	for _, nodeID := range nodeIDs {
+		node, exists := conversation.Mapping[nodeID]
+		if !exists || node.Message == nil || node.Message.Content.ContentType != chatGPTUserEditableContextType {
+			continue
+		}
+
+		context := chatGPTUserEditableContext(*node.Message)
+		if context.UserProfile == "" && context.UserInstructions == "" {
+			continue
+		}
+		return &context
+	}
+
+	return nil
+}
+
+// chatGPTUserEditableContext prefers the raw texts recorded in the message metadata and
+// falls back to unwrapping the fenced blocks of the preamble in the message content.
+func chatGPTUserEditableContext(message rawChatGPTMessage) SystemContext {
+	contextData, _ := message.Metadata["user_context_message_data"].(map[string]any)
+
+	return SystemContext{
+		UserProfile: firstNonBlank(
+			metadataString(contextData, "about_user_message"),
+			unwrapCodeFence(message.Content.UserProfile),
+		),
+		UserInstructions: firstNonBlank(
+			metadataString(contextData, "about_model_message"),
+			unwrapCodeFence(message.Content.UserInstructions),
+		),
+	}
+}
+
+// unwrapCodeFence returns the text between the first and last code fence, or the
+// trimmed text when it is not fenced.
+func unwrapCodeFence(text string) string {
+	start := strings.Index(text, codeFence)
+	end := strings.LastIndex(text, codeFence)
+	if start < 0 || end <= start {
+		return strings.TrimSpace(text)
+	}
+
+	return strings.TrimSpace(text[start+len(codeFence) : end])
+}
+
+// newerTimestamp mirrors olderTimestamp, keeping the later of two RFC 3339 timestamps.
+func newerTimestamp(current string, candidate string) string {
+	trimmedCandidate := strings.TrimSpace(candidate)
+	if trimmedCandidate == "" {
+		return current
+	}
+
+	trimmedCurrent := strings.TrimSpace(current)
+	if trimmedCurrent == "" {
+		return trimmedCandidate
+	}
+
+	currentTime, currentErr := time.Parse(time.RFC3339Nano, trimmedCurrent)
+	candidateTime, candidateErr := time.Parse(time.RFC3339Nano, trimmedCandidate)
+	if currentErr == nil && candidateErr == nil {
+		if candidateTime.After(currentTime) {
+			return trimmedCandidate
+		}
+		return trimmedCurrent
+	}
+	if currentErr != nil && candidateErr == nil {
+		return trimmedCandidate
+	}
+
+	return trimmedCurrent
+}
@@ -0,0 +1,145 @@
+package models
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseChatGPTSystemContext(t *testing.T) {
+	input := `[
+		{
+			"title": "From metadata",
+			"conversation_id": "context-1",
+			"current_node": "prompt",
+			"mapping": {
+				"root": {"id": "root", "message": null, "parent": null, "children": ["context"]},
+				"context": {
+					"id": "context", "parent": "root", "children": ["prompt"],
+					"message": {
+						"author": {"role": "user"},
+						"content": {"content_type": "user_editable_context", "user_profile": "User profile:\n` + "```" + `Likes jazz\n` + "```" + `", "user_instructions": "ignored"},
+						"metadata": {
+							"is_visually_hidden_from_conversation": true,
+							"user_context_message_data": {"about_user_message": "Likes jazz\n", "about_model_message": "Be concise."}
+						}
+					}
+				},
+				"prompt": {
+					"id": "prompt", "parent": "context", "children": [],
+					"message": {"author": {"role": "user"}, "content": {"content_type": "text", "parts": ["Hello"]}}
+				}
+			}
+		},
+		{
+			"title": "From content",
+			"conversation_id": "context-2",
+			"current_node": "prompt",
+			"mapping": {
+				"context": {
+					"id": "context", "parent": null, "children": ["prompt"],
+					"message": {
+						"author": {"role": "user"},
+						"content": {"content_type": "user_editable_context", "user_profile": "", "user_instructions": "The user provided the additional info:\n` + "```" + `Answer in French.` + "```" + `"},
+						"metadata": {"is_visually_hidden_from_conversation": true}
+					}
+				},
+				"prompt": {
+					"id": "prompt", "parent": "context", "children": [],
+					"message": {"author": {"role": "user"}, "content": {"content_type": "text", "parts": ["Bonjour"]}}
+				}
+			}
+		},
+		{
+			"title": "No context",
+			"conversation_id": "context-3",
+			"mapping": {
+				"prompt": {
+					"id": "prompt", "parent": null, "children": [],
+					"message": {"author": {"role": "user"}, "content": {"content_type": "text", "parts": ["Hi"]}}
+				}
+			}
+		}
+	]`
+
+	result, err := ParseConversationsJSONWithOptions(strings.NewReader(input), ParseOptions{})
+	if err != nil {
+		t.Fatalf("ParseConversationsJSONWithOptions returned error: %v", err)
+	}
+	if len(result.Conversations) != 3 {
+		t.Fatalf("expected 3 conversations, got %d", len(result.Conversations))
+	}
+
+	expected := []*SystemContext{
+		{UserProfile: "Likes jazz", UserInstructions: "Be concise."},
+		{UserInstructions: "Answer in French."},
+		nil,
+	}
+	for index, conversation := range result.Conversations {
+		if !reflect.DeepEqual(conversation.SystemContext, expected[index]) {
+			t.Fatalf("conversation %s: expected system context %+v, got %+v", conversation.ID, expected[index], conversation.SystemContext)
+		}
+		if len(conversation.Messages) != 1 {
+			t.Fatalf("conversation %s: expected the hidden context message to stay out of messages, got %+v", conversation.ID, conversation.Messages)
+		}
+	}
+}
+
+func TestCustomInstructionsTimeline(t *testing.T) {
+	concise := &SystemContext{UserInstructions: "Be concise."}
+	french := &SystemContext{UserProfile: "Lives in Lyon", UserInstructions: "Answer in French."}
+
+	conversations := []Conversation{
+		{ID: "late-french", CreatedAt: "2024-03-01T00:00:00Z", SystemContext: french},
+		{ID: "plain", CreatedAt: "2024-01-15T00:00:00Z"},
+		{ID: "early-concise", CreatedAt: "2024-01-01T00:00:00Z", SystemContext: concise},
+		{ID: "undated-concise", SystemContext: &SystemContext{UserInstructions: "Be concise."}},
+		{ID: "mid-concise", CreatedAt: "2024-02-01T00:00:00Z", SystemContext: concise},
+		{ID: "undated-other", SystemContext: &SystemContext{UserInstructions: "Use metric units."}},
+	}
+
+	got := CustomInstructionsTimeline(conversations)
+	expected := []CustomInstructionsVersion{
+		{
+			UserInstructions: "Be concise.",
+			FirstSeen:        "2024-01-01T00:00:00Z",
+			LastSeen:         "2024-02-01T00:00:00Z",
+			ConversationIDs:  []string{"early-concise", "undated-concise", "mid-concise"},
+		},
+		{
+			UserProfile:      "Lives in Lyon",
+			UserInstructions: "Answer in French.",
+			FirstSeen:        "2024-03-01T00:00:00Z",
+			LastSeen:         "2024-03-01T00:00:00Z",
+			ConversationIDs:  []string{"late-french"},
+		},
+		{
+			UserInstructions: "Use metric units.",
+			ConversationIDs:  []string{"undated-other"},
+		},
+	}
+	if !reflect.DeepEqual(got, expected) {
+		t.Fatalf("unexpected timeline:\n got: %+v\nwant: %+v", got, expected)
+	}
+}
+
+func TestUnwrapCodeFence(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{name: "fenced", input: "Preamble:\n```Likes jazz\n```", expected: "Likes jazz"},
+		{name: "unfenced", input: "  Likes jazz  ", expected: "Likes jazz"},
+		{name: "single fence", input: "```Likes jazz", expected: "```Likes jazz"},
+		{name: "empty", input: "", expected: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := unwrapCodeFence(tt.input); got != tt.expected {
+				t.Fatalf("expected %q, got %q", tt.expected, got)
+			}
+		})
+	}
+}
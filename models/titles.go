@@ -0,0 +1,193 @@
+package models
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+const (
+	// titleSourceWordLimit is how much of the first prompt is mined for keyphrases.
+	titleSourceWordLimit = 80
+	// maxTitlePhraseWords splits longer runs of content words into separate phrases.
+	maxTitlePhraseWords = 4
+	// maxTitleWords and maxTitleLength bound the span of the prompt used as a title.
+	maxTitleWords  = 8
+	maxTitleLength = 60
+	// maxTitlePhrases is how many keyphrases a title may combine.
+	maxTitlePhrases = 3
+)
+
+// genericTitlePattern matches the placeholder names providers give conversations they
+// have not titled, such as ChatGPT's "New chat" or Gemini's "Conversation with Gemini".
+var genericTitlePattern = regexp.MustCompile(`(?i)^(?:(?:new|untitled)(?: (?:chat|conversation))?|chat|conversation|conversation with \w+)(?: \d+)?$`)
+
+var (
+	titleWordPattern  = regexp.MustCompile(`[\p{L}\p{N}][\p{L}\p{N}'’_.+#-]*[\p{L}\p{N}+#]|[\p{L}\p{N}]`)
+	fencedCodePattern = regexp.MustCompile("(?s)```.*?(?:```|$)")
+	sentenceBreak     = regexp.MustCompile(`[.?!;:](?:\s|$)|\n`)
+)
+
+// titleStopWords extend the common stop words with the requests that open most
+// prompts ("can you help me write…") and carry nothing about the subject.
+var titleStopWords = map[string]struct{}{
+	"am": {}, "any": {}, "been": {}, "best": {}, "create": {}, "did": {}, "explain": {},
+	"get": {}, "give": {}, "good": {}, "had": {}, "has": {}, "have": {}, "hello": {},
+	"help": {}, "hey": {}, "hi": {}, "i'd": {}, "i'm": {}, "i've": {}, "just": {}, "know": {},
+	"let": {}, "like": {}, "make": {}, "need": {}, "not": {}, "now": {}, "ok": {}, "some": {},
+	"tell": {}, "thanks": {}, "than": {}, "them": {}, "they": {}, "try": {}, "trying": {},
+	"use": {}, "want": {}, "way": {}, "were": {}, "where": {}, "write": {}, "us": {},
+}
+
+// IsGenericConversationTitle reports whether name is blank or a provider placeholder.
+func IsGenericConversationTitle(name string) bool {
+	trimmed := strings.TrimSpace(name)
+	return trimmed == "" || genericTitlePattern.MatchString(trimmed)
+}
+
+type titleWord struct {
+	text       string
+	stem       string
+	start, end int
+}
+
+type titlePhrase struct {
+	first, last int // indexes into the prompt words
+	score       float64
+}
+
+// GenerateConversationTitle builds a title from the first exchange with a RAKE-style
+// keyphrase extraction: the first prompt is split into phrases at stop words and
+// punctuation, words are scored by how many content words they co-occur with and how
+// often the first answer repeats them, and the shortest span of the prompt covering the
+// best phrases becomes the title. It returns "" when the prompt has no content words.
+func GenerateConversationTitle(messages []Message) string {
+	prompt, answer := firstExchange(messages)
+	prompt = fencedCodePattern.ReplaceAllString(prompt, " ")
+	words := titleWords(prompt, titleSourceWordLimit)
+	phrases := titlePhrases(prompt, words)
+	if len(phrases) == 0 {
+		return ""
+	}
+
+	frequencies := make(map[string]float64)
+	degrees := make(map[string]float64)
+	for _, phrase := range phrases {
+		for index := phrase.first; index <= phrase.last; index++ {
+			frequencies[words[index].stem]++
+			degrees[words[index].stem] += float64(phrase.last - phrase.first + 1)
+		}
+	}
+	// Words the answer repeats are more likely to be the subject than incidental detail.
+	for _, answerWord := range titleWords(fencedCodePattern.ReplaceAllString(answer, " "), titleSourceWordLimit*4) {
+		if _, seen := frequencies[answerWord.stem]; seen {
+			degrees[answerWord.stem] += 0.5
+		}
+	}
+	for index := range phrases {
+		phrase := &phrases[index]
+		for wordIndex := phrase.first; wordIndex <= phrase.last; wordIndex++ {
+			stem := words[wordIndex].stem
+			phrase.score += degrees[stem] / frequencies[stem]
+		}
+	}
+
+	ranked := make([]titlePhrase, len(phrases))
+	copy(ranked, phrases)
+	sort.SliceStable(ranked, func(i, j int) bool {
+		return ranked[i].score > ranked[j].score
+	})
+
+	first, last := ranked[0].first, ranked[0].last
+	for _, phrase := range ranked[1:min(maxTitlePhrases, len(ranked))] {
+		spanFirst, spanLast := min(first, phrase.first), max(last, phrase.last)
+		if spanLast-spanFirst+1 > maxTitleWords || hasSentenceBreak(prompt[words[spanFirst].start:words[spanLast].end]) {
+			continue
+		}
+		first, last = spanFirst, spanLast
+	}
+
+	title := strings.Join(strings.Fields(prompt[words[first].start:words[last].end]), " ")
+	if utf8.RuneCountInString(title) > maxTitleLength {
+		title = strings.Join(strings.Fields(prompt[words[ranked[0].first].start:words[ranked[0].last].end]), " ")
+	}
+
+	return capitalizeFirst(title)
+}
+
+// firstExchange returns the text of the first prompt and of the answer that follows it.
+func firstExchange(messages []Message) (string, string) {
+	for index, message := range messages {
+		if message.Kind != MessageKindPrompt || strings.TrimSpace(message.Text) == "" {
+			continue
+		}
+		for _, reply := range messages[index+1:] {
+			if reply.Kind == MessageKindAnswer {
+				return message.Text, reply.Text
+			}
+		}
+		return message.Text, ""
+	}
+
+	return "", ""
+}
+
+func titleWords(text string, limit int) []titleWord {
+	locations := titleWordPattern.FindAllStringIndex(text, limit)
+	words := make([]titleWord, 0, len(locations))
+	for _, location := range locations {
+		word := text[location[0]:location[1]]
+		lowered := strings.ToLower(strings.ReplaceAll(word, "’", "'"))
+		words = append(words, titleWord{text: word, stem: stemWord(lowered), start: location[0], end: location[1]})
+	}
+
+	return words
+}
+
+// titlePhrases splits the words into runs of content words, breaking at stop words,
+// punctuation and every maxTitlePhraseWords words.
+func titlePhrases(text string, words []titleWord) []titlePhrase {
+	phrases := make([]titlePhrase, 0, 8)
+	current := -1
+	for index, word := range words {
+		if isTitleStopWord(word.text) {
+			current = -1
+			continue
+		}
+		joinsPrevious := current >= 0 &&
+			index-phrases[current].first < maxTitlePhraseWords &&
+			strings.TrimSpace(text[words[index-1].end:word.start]) == ""
+		if joinsPrevious {
+			phrases[current].last = index
+			continue
+		}
+		phrases = append(phrases, titlePhrase{first: index, last: index})
+		current = len(phrases) - 1
+	}
+
+	return phrases
+}
+
+func isTitleStopWord(word string) bool {
+	lowered := strings.ToLower(strings.ReplaceAll(word, "’", "'"))
+	if _, isStopWord := commonStopWords[lowered]; isStopWord {
+		return true
+	}
+	_, isStopWord := titleStopWords[lowered]
+	return isStopWord
+}
+
+func hasSentenceBreak(text string) bool {
+	return sentenceBreak.MatchString(text)
+}
+
+func capitalizeFirst(text string) string {
+	first, size := utf8.DecodeRuneInString(text)
+	if first == utf8.RuneError {
+		return text
+	}
+
+	return string(unicode.ToUpper(first)) + text[size:]
+}
@@ -0,0 +1,102 @@
+package models
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestIsGenericConversationTitle(t *testing.T) {
+	tests := []struct {
+		name     string
+		expected bool
+	}{
+		{name: "", expected: true},
+		{name: "  ", expected: true},
+		{name: "New chat", expected: true},
+		{name: "new conversation", expected: true},
+		{name: "New chat 3", expected: true},
+		{name: "Untitled", expected: true},
+		{name: "Conversation with Gemini", expected: true},
+		{name: "New chat ideas for the team", expected: false},
+		{name: "Kubernetes pods", expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsGenericConversationTitle(tt.name); got != tt.expected {
+				t.Fatalf("expected %v for %q, got %v", tt.expected, tt.name, got)
+			}
+		})
+	}
+}
+
+func TestGenerateConversationTitle(t *testing.T) {
+	exchange := func(prompt string, answer string) []Message {
+		return []Message{
+			{Speaker: "system", Text: "You are a helpful assistant.", Kind: MessageKindSystem},
+			{Speaker: "human", Text: prompt, Kind: MessageKindPrompt},
+			{Speaker: "assistant", Text: answer, Kind: MessageKindAnswer},
+		}
+	}
+
+	tests := []struct {
+		name     string
+		messages []Message
+		expected string
+	}{
+		{
+			name:     "question",
+			messages: exchange("How do I deploy my Go service to Kubernetes?", "Build an image and apply a Kubernetes Deployment."),
+			expected: "Deploy my Go service to Kubernetes",
+		},
+		{
+			name:     "request preamble",
+			messages: exchange("Hi! Can you help me write a cover letter for a data analyst job?", "Here is a cover letter for the data analyst role."),
+			expected: "Cover letter for a data analyst job",
+		},
+		{
+			name:     "code is ignored",
+			messages: exchange("Why does this panic?\n```go\nvar m map[string]int\nm[\"a\"] = 1\n```", "Writing to a nil map panics."),
+			expected: "Panic",
+		},
+		{
+			name:     "keeps dotted names",
+			messages: exchange("node.js streams backpressure", ""),
+			expected: "Node.js streams backpressure",
+		},
+		{
+			name:     "stop words only",
+			messages: exchange("Can you help me?", "Sure."),
+			expected: "",
+		},
+		{name: "no prompt", messages: []Message{{Speaker: "assistant", Text: "Hello there", Kind: MessageKindAnswer}}, expected: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := GenerateConversationTitle(tt.messages); got != tt.expected {
+				t.Fatalf("expected %q, got %q", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestParseGeneratesTitlesForUntitledConversations(t *testing.T) {
+	result, err := ParseConversations(strings.NewReader(`[
+		{"uuid": "c1", "name": "", "chat_messages": [{"sender": "human", "text": "Plan a trip to Kyoto in spring"}]},
+		{"uuid": "c2", "name": "Packing list", "chat_messages": [{"sender": "human", "text": "What should I pack for Kyoto?"}]}
+	]`), ParseOptions{})
+	if err != nil {
+		t.Fatalf("ParseConversations returned error: %v", err)
+	}
+
+	if got := result.Conversations[0]; got.Name != "" || got.GeneratedTitle != "Plan a trip to Kyoto" {
+		t.Fatalf("expected a generated title next to the blank name, got %q / %q", got.Name, got.GeneratedTitle)
+	}
+	if got := result.Conversations[1]; got.GeneratedTitle != "" {
+		t.Fatalf("expected titled conversations to keep only their name, got %q", got.GeneratedTitle)
+	}
+	if entries := result.Entries(); entries[0].ConversationTitle != "Plan a trip to Kyoto" {
+		t.Fatalf("expected entries to carry the generated title, got %+v", entries[0])
+	}
+}
@@ -0,0 +1,133 @@
+package models
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"sync"
+	"unicode/utf8"
+
+	"github.com/pkoukk/tiktoken-go"
+	tiktokenloader "github.com/pkoukk/tiktoken-go-loader"
+)
+
+// Tokenizers used to estimate token counts. The BPE vocabularies ship inside the
+// binary, so counting never downloads anything.
+const (
+	TokenizerO200K  = "o200k_base"
+	TokenizerCL100K = "cl100k_base"
+	// TokenizerClaude approximates Anthropic's tokenizer, which is not published.
+	TokenizerClaude = "claude"
+)
+
+// claudeTokenRatio scales cl100k counts to approximate Claude's tokenizer, which
+// splits the same text into roughly ten percent more tokens.
+const claudeTokenRatio = 1.1
+
+// charactersPerToken is the rule-of-thumb fallback used if a vocabulary fails to load.
+const charactersPerToken = 4
+
+// o200kModelPrefixes are the OpenAI model families tokenized with o200k_base.
+var o200kModelPrefixes = []string{"gpt-4o", "gpt-4.1", "gpt-4.5", "gpt-5", "o1", "o3", "o4", "chatgpt-4o"}
+
+// cl100kModelPrefixes are the older OpenAI model families tokenized with cl100k_base.
+var cl100kModelPrefixes = []string{"gpt-4", "gpt-3.5", "text-embedding"}
+
+type bpeTokenizer struct {
+	once     sync.Once
+	encoding *tiktoken.Tiktoken
+	err      error
+}
+
+var (
+	bpeTokenizers = map[string]*bpeTokenizer{
+		TokenizerO200K:  {},
+		TokenizerCL100K: {},
+	}
+	offlineLoaderOnce sync.Once
+)
+
+// CountTokens estimates how many tokens text occupies with the given tokenizer.
+func CountTokens(text string, tokenizer string) (int, error) {
+	if text == "" {
+		return 0, nil
+	}
+
+	if tokenizer == TokenizerClaude {
+		count, err := CountTokens(text, TokenizerCL100K)
+		if err != nil {
+			return 0, err
+		}
+		return int(math.Ceil(float64(count) * claudeTokenRatio)), nil
+	}
+
+	encoding, err := loadBPETokenizer(tokenizer)
+	if err != nil {
+		return 0, err
+	}
+
+	return len(encoding.EncodeOrdinary(text)), nil
+}
+
+// TokenizerForModel picks the tokenizer for a model, falling back to the provider's
+// usual tokenizer when the model is unknown or not recorded.
+func TokenizerForModel(provider string, model string) string {
+	normalizedModel := strings.ToLower(strings.TrimSpace(model))
+	switch {
+	case strings.HasPrefix(normalizedModel, "claude"):
+		return TokenizerClaude
+	case hasAnyPrefix(normalizedModel, o200kModelPrefixes):
+		return TokenizerO200K
+	case hasAnyPrefix(normalizedModel, cl100kModelPrefixes):
+		return TokenizerCL100K
+	}
+
+	switch provider {
+	case ProviderClaude, ProviderAnthropic:
+		return TokenizerClaude
+	case ProviderChatGPT:
+		return TokenizerO200K
+	default:
+		return TokenizerCL100K
+	}
+}
+
+// estimateMessageTokens counts a message's tokens, falling back to a character-based
+// estimate so a missing vocabulary never fails a load.
+func estimateMessageTokens(provider string, message Message) int {
+	count, err := CountTokens(message.Text, TokenizerForModel(provider, message.Model))
+	if err != nil {
+		return (utf8.RuneCountInString(message.Text) + charactersPerToken - 1) / charactersPerToken
+	}
+
+	return count
+}
+
+func loadBPETokenizer(name string) (*tiktoken.Tiktoken, error) {
+	tokenizer, exists := bpeTokenizers[name]
+	if !exists {
+		return nil, fmt.Errorf("unknown tokenizer %q", name)
+	}
+
+	offlineLoaderOnce.Do(func() {
+		tiktoken.SetBpeLoader(tiktokenloader.NewOfflineLoader())
+	})
+	tokenizer.once.Do(func() {
+		tokenizer.encoding, tokenizer.err = tiktoken.GetEncoding(name)
+		if tokenizer.err != nil {
+			tokenizer.err = fmt.Errorf("load %s vocabulary: %w", name, tokenizer.err)
+		}
+	})
+
+	return tokenizer.encoding, tokenizer.err
+}
+
+func hasAnyPrefix(value string, prefixes []string) bool {
+	for _, prefix := range prefixes {
+		if strings.HasPrefix(value, prefix) {
+			return true
+		}
+	}
+
+	return false
+}
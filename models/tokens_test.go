@@ -0,0 +1,95 @@
+package models
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCountTokens(t *testing.T) {
+	tests := []struct {
+		name      string
+		text      string
+		tokenizer string
+		expected  int
+	}{
+		{name: "empty text", text: "", tokenizer: TokenizerCL100K, expected: 0},
+		{name: "cl100k ascii", text: "Hello, how are you today?", tokenizer: TokenizerCL100K, expected: 7},
+		{name: "cl100k multilingual", text: "¡Hola! Привет こんにちは 👋", tokenizer: TokenizerCL100K, expected: 10},
+		{name: "o200k multilingual", text: "¡Hola! Привет こんにちは 👋", tokenizer: TokenizerO200K, expected: 9},
+		{name: "claude scales cl100k", text: "Hello, how are you today?", tokenizer: TokenizerClaude, expected: 8},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := CountTokens(tt.text, tt.tokenizer)
+			if err != nil {
+				t.Fatalf("CountTokens returned error: %v", err)
+			}
+			if got != tt.expected {
+				t.Fatalf("expected %d tokens, got %d", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestCountTokensUnknownTokenizer(t *testing.T) {
+	_, err := CountTokens("hello", "p50k_base")
+	assertErrorContains(t, err, `unknown tokenizer "p50k_base"`)
+}
+
+func TestTokenizerForModel(t *testing.T) {
+	tests := []struct {
+		name     string
+		provider string
+		model    string
+		expected string
+	}{
+		{name: "gpt-4o", provider: ProviderChatGPT, model: "gpt-4o-mini", expected: TokenizerO200K},
+		{name: "reasoning model", provider: ProviderOpenAI, model: "o3-mini", expected: TokenizerO200K},
+		{name: "gpt-4 turbo", provider: ProviderChatGPT, model: "gpt-4-turbo", expected: TokenizerCL100K},
+		{name: "gpt-3.5", provider: ProviderOpenAI, model: "gpt-3.5-turbo-0125", expected: TokenizerCL100K},
+		{name: "claude model", provider: ProviderAnthropic, model: "claude-3-5-sonnet-20241022", expected: TokenizerClaude},
+		{name: "claude export without model", provider: ProviderClaude, expected: TokenizerClaude},
+		{name: "chatgpt export without model", provider: ProviderChatGPT, expected: TokenizerO200K},
+		{name: "unknown provider", provider: ProviderGemini, model: "gemini-1.5-pro", expected: TokenizerCL100K},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := TokenizerForModel(tt.provider, tt.model); got != tt.expected {
+				t.Fatalf("expected %s, got %s", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestParseConversationsCountsTokens(t *testing.T) {
+	input := `[
+		{
+			"uuid": "conv-tokens",
+			"name": "Tokens",
+			"chat_messages": [
+				{"sender": "human", "text": "Hello, how are you today?"},
+				{"sender": "assistant", "text": "Hello, how are you today?"}
+			]
+		}
+	]`
+
+	result, err := ParseConversations(strings.NewReader(input), ParseOptions{})
+	if err != nil {
+		t.Fatalf("ParseConversations returned error: %v", err)
+	}
+
+	conversation := result.Conversations[0]
+	for _, message := range conversation.Messages {
+		if message.TokenCount != 8 {
+			t.Fatalf("expected 8 claude-style tokens per message, got %d", message.TokenCount)
+		}
+	}
+	if conversation.TokenCount != 16 {
+		t.Fatalf("expected the conversation to total 16 tokens, got %d", conversation.TokenCount)
+	}
+	if entries := result.Entries(); entries[0].TokenCount != 8 {
+		t.Fatalf("expected entries to carry token counts, got %d", entries[0].TokenCount)
+	}
+}
@@ -0,0 +1,366 @@
+package models
+
+import (
+	"math"
+	"math/rand"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+const (
+	// maxTopicCount caps the automatic number of clusters.
+	maxTopicCount = 20
+	// topicKeywordCount is how many of a cluster's heaviest terms are reported; the first
+	// topicLabelKeywordCount of them make up its label.
+	topicKeywordCount      = 5
+	topicLabelKeywordCount = 3
+	// kMeansIterations bounds the refinement; assignments usually settle much sooner.
+	kMeansIterations = 30
+	// otherTopicLabel groups conversations without any clusterable words.
+	otherTopicLabel = "Other"
+)
+
+// Topic is a cluster of conversations about the same subject, labelled with the terms
+// that weigh most in it.
+type Topic struct {
+	Label         string              `json:"label"`
+	Keywords      []string            `json:"keywords"`
+	Conversations []TopicConversation `json:"conversations"`
+}
+
+// TopicConversation is a conversation assigned to a topic.
+type TopicConversation struct {
+	Provider         string `json:"provider,omitempty"`
+	ConversationID   string `json:"conversationId"`
+	ConversationName string `json:"conversationName"`
+}
+
+// sparseVector maps term indexes to weights.
+type sparseVector map[int]float64
+
+// ClusterConversations groups the conversations by content with spherical k-means over
+// TF-IDF vectors of their names, prompts and answers. A topicCount of zero or less picks
+// one from the number of conversations. Topics are ordered by size, largest first, and
+// conversations without any usable words end up in a final "Other" topic. Seeding is
+// deterministic, so the same export always yields the same topics.
+func ClusterConversations(conversations []Conversation, topicCount int) []Topic {
+	terms, documents := topicDocuments(conversations)
+
+	clusteredIndexes := make([]int, 0, len(documents))
+	otherIndexes := make([]int, 0)
+	for index, document := range documents {
+		if len(document) == 0 {
+			otherIndexes = append(otherIndexes, index)
+			continue
+		}
+		clusteredIndexes = append(clusteredIndexes, index)
+	}
+
+	if topicCount <= 0 {
+		topicCount = int(math.Round(math.Sqrt(float64(len(clusteredIndexes)) / 2)))
+		topicCount = min(max(topicCount, 1), maxTopicCount)
+	}
+	topicCount = min(topicCount, len(clusteredIndexes))
+
+	vectors := make([]sparseVector, len(clusteredIndexes))
+	for i, documentIndex := range clusteredIndexes {
+		vectors[i] = documents[documentIndex]
+	}
+	assignments, centroids := sphericalKMeans(vectors, topicCount, len(terms))
+
+	topics := make([]Topic, 0, topicCount+1)
+	for cluster, centroid := range centroids {
+		topic := Topic{Keywords: topKeywords(centroid, terms, topicKeywordCount)}
+		for i, assignment := range assignments {
+			if assignment == cluster {
+				topic.Conversations = append(topic.Conversations, topicConversation(conversations[clusteredIndexes[i]]))
+			}
+		}
+		if len(topic.Conversations) == 0 {
+			continue
+		}
+		topic.Label = strings.Join(topic.Keywords[:min(topicLabelKeywordCount, len(topic.Keywords))], ", ")
+		topics = append(topics, topic)
+	}
+	sort.SliceStable(topics, func(i, j int) bool {
+		return len(topics[i].Conversations) > len(topics[j].Conversations)
+	})
+
+	if len(otherIndexes) > 0 {
+		other := Topic{Label: otherTopicLabel, Keywords: []string{}}
+		for _, index := range otherIndexes {
+			other.Conversations = append(other.Conversations, topicConversation(conversations[index]))
+		}
+		topics = append(topics, other)
+	}
+
+	return topics
+}
+
+func topicConversation(conversation Conversation) TopicConversation {
+	return TopicConversation{
+		Provider:         conversation.Provider,
+		ConversationID:   conversation.ID,
+		ConversationName: conversation.Name,
+	}
+}
+
+// topicDocuments returns the vocabulary and one unit-length TF-IDF vector per
+// conversation. Terms found in a single conversation cannot link it to another and
+// terms found in all of them cannot tell them apart, so both are left out.
+func topicDocuments(conversations []Conversation) ([]string, []sparseVector) {
+	termCounts := make([]map[string]int, len(conversations))
+	documentFrequencies := make(map[string]int)
+	for index, conversation := range conversations {
+		counts := make(map[string]int)
+		addTopicTerms(counts, conversation.Name)
+		for _, message := range conversation.Messages {
+			if isTopicMessage(message) {
+				addTopicTerms(counts, message.Text)
+			}
+		}
+		for term := range counts {
+			documentFrequencies[term]++
+		}
+		termCounts[index] = counts
+	}
+
+	terms := make([]string, 0, len(documentFrequencies))
+	for term, frequency := range documentFrequencies {
+		if frequency < 2 || (frequency == len(conversations) && len(conversations) > 2) {
+			continue
+		}
+		terms = append(terms, term)
+	}
+	sort.Strings(terms)
+	termIndexes := make(map[string]int, len(terms))
+	for index, term := range terms {
+		termIndexes[term] = index
+	}
+
+	documents := make([]sparseVector, len(conversations))
+	for index, counts := range termCounts {
+		vector := make(sparseVector)
+		for term, count := range counts {
+			termIndex, ok := termIndexes[term]
+			if !ok {
+				continue
+			}
+			inverseFrequency := math.Log(float64(len(conversations)) / float64(documentFrequencies[term]))
+			vector[termIndex] = (1 + math.Log(float64(count))) * (1 + inverseFrequency)
+		}
+		normalizeSparseVector(vector)
+		documents[index] = vector
+	}
+
+	return terms, documents
+}
+
+// addTopicTerms counts the words of text worth clustering on: at least three
+// characters, not all digits and not a stop word.
+func addTopicTerms(counts map[string]int, text string) {
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	for _, word := range words {
+		if len([]rune(word)) < 3 || onlyDigits(word) == word {
+			continue
+		}
+		if _, isStopWord := commonStopWords[word]; isStopWord {
+			continue
+		}
+		if _, isStopWord := topicStopWords[word]; isStopWord {
+			continue
+		}
+		counts[word]++
+	}
+}
+
+// topicStopWords are words common in chats that would otherwise label every cluster.
+var topicStopWords = map[string]struct{}{
+	"also": {}, "any": {}, "been": {}, "being": {}, "chat": {}, "have": {}, "here": {},
+	"just": {}, "like": {}, "make": {}, "more": {}, "new": {}, "not": {}, "one": {},
+	"only": {}, "other": {}, "out": {}, "some": {}, "sure": {}, "than": {}, "them": {},
+	"they": {}, "thanks": {}, "use": {}, "using": {}, "want": {}, "were": {}, "where": {},
+	"while": {}, "yes": {},
+}
+
+// sphericalKMeans clusters unit vectors by cosine similarity. Seeds are chosen with
+// k-means++ from a fixed random source.
+func sphericalKMeans(vectors []sparseVector, k int, dimensions int) ([]int, [][]float64) {
+	if k <= 0 || len(vectors) == 0 {
+		return nil, nil
+	}
+
+	random := rand.New(rand.NewSource(1))
+	centroids := make([][]float64, 0, k)
+	centroids = append(centroids, denseVector(vectors[random.Intn(len(vectors))], dimensions))
+	distances := make([]float64, len(vectors))
+	for len(centroids) < k {
+		var total float64
+		for i, vector := range vectors {
+			distances[i] = math.Inf(1)
+			for _, centroid := range centroids {
+				distances[i] = math.Min(distances[i], 1-sparseDot(vector, centroid))
+			}
+			distances[i] = math.Max(distances[i], 0)
+			total += distances[i] * distances[i]
+		}
+		if total == 0 {
+			break
+		}
+
+		target := random.Float64() * total
+		chosen := len(vectors) - 1
+		for i, distance := range distances {
+			target -= distance * distance
+			if target <= 0 {
+				chosen = i
+				break
+			}
+		}
+		centroids = append(centroids, denseVector(vectors[chosen], dimensions))
+	}
+
+	assignments := make([]int, len(vectors))
+	for iteration := 0; iteration < kMeansIterations; iteration++ {
+		changed := false
+		for i, vector := range vectors {
+			best, bestSimilarity := 0, math.Inf(-1)
+			for cluster, centroid := range centroids {
+				if similarity := sparseDot(vector, centroid); similarity > bestSimilarity {
+					best, bestSimilarity = cluster, similarity
+				}
+			}
+			if iteration == 0 || assignments[i] != best {
+				changed = true
+			}
+			assignments[i] = best
+		}
+		if !changed {
+			break
+		}
+
+		for cluster := range centroids {
+			centroid := make([]float64, dimensions)
+			members := 0
+			for i, vector := range vectors {
+				if assignments[i] != cluster {
+					continue
+				}
+				members++
+				for term, weight := range vector {
+					centroid[term] += weight
+				}
+			}
+			if members == 0 {
+				continue
+			}
+			normalizeDenseVector(centroid)
+			centroids[cluster] = centroid
+		}
+	}
+
+	return assignments, centroids
+}
+
+func topKeywords(centroid []float64, terms []string, count int) []string {
+	termIndexes := make([]int, 0, len(centroid))
+	for index, weight := range centroid {
+		if weight > 0 {
+			termIndexes = append(termIndexes, index)
+		}
+	}
+	sort.SliceStable(termIndexes, func(i, j int) bool {
+		if centroid[termIndexes[i]] != centroid[termIndexes[j]] {
+			return centroid[termIndexes[i]] > centroid[termIndexes[j]]
+		}
+		return terms[termIndexes[i]] < terms[termIndexes[j]]
+	})
+
+	keywords := make([]string, 0, count)
+	for _, index := range termIndexes[:min(count, len(termIndexes))] {
+		keywords = append(keywords, terms[index])
+	}
+
+	return keywords
+}
+
+func sparseDot(vector sparseVector, dense []float64) float64 {
+	var dot float64
+	for term, weight := range vector {
+		dot += weight * dense[term]
+	}
+	return dot
+}
+
+func denseVector(vector sparseVector, dimensions int) []float64 {
+	dense := make([]float64, dimensions)
+	for term, weight := range vector {
+		dense[term] = weight
+	}
+	return dense
+}
+
+func normalizeSparseVector(vector sparseVector) {
+	var norm float64
+	for _, weight := range vector {
+		norm += weight * weight
+	}
+	if norm == 0 {
+		return
+	}
+	norm = math.Sqrt(norm)
+	for term := range vector {
+		vector[term] /= norm
+	}
+}
+
+func normalizeDenseVector(vector []float64) {
+	var norm float64
+	for _, weight := range vector {
+		norm += weight * weight
+	}
+	if norm == 0 {
+		return
+	}
+	norm = math.Sqrt(norm)
+	for i := range vector {
+		vector[i] /= norm
+	}
+}
+
+// isTopicMessage keeps the human-facing exchange; tool traffic and reasoning
+// would crowd out the subjects people talk about.
+func isTopicMessage(message Message) bool {
+	if strings.TrimSpace(message.Text) == "" {
+		return false
+	}
+
+	return message.Kind == "" || message.Kind == MessageKindPrompt || message.Kind == MessageKindAnswer
+}
+
+// stemSuffixes are stripped longest first, keeping at least three characters of stem.
+var stemSuffixes = []string{"ational", "ization", "ments", "ingly", "ment", "tion", "ness", "ing", "ies", "ied", "ed", "es", "ly", "s"}
+
+func stemWord(word string) string {
+	for _, suffix := range stemSuffixes {
+		if strings.HasSuffix(word, suffix) && len([]rune(word))-len([]rune(suffix)) >= 3 {
+			return strings.TrimSuffix(word, suffix)
+		}
+	}
+
+	return word
+}
+
+// commonStopWords carry no meaning on their own and are skipped by topics and titles.
+var commonStopWords = map[string]struct{}{
+	"a": {}, "about": {}, "an": {}, "and": {}, "are": {}, "as": {}, "at": {}, "be": {}, "but": {},
+	"by": {}, "can": {}, "could": {}, "do": {}, "does": {}, "for": {}, "from": {}, "how": {},
+	"i": {}, "if": {}, "in": {}, "into": {}, "is": {}, "it": {}, "its": {}, "me": {}, "my": {},
+	"of": {}, "on": {}, "or": {}, "our": {}, "please": {}, "should": {}, "so": {}, "that": {},
+	"the": {}, "their": {}, "then": {}, "there": {}, "these": {}, "this": {}, "to": {}, "was": {},
+	"we": {}, "what": {}, "when": {}, "which": {}, "who": {}, "why": {}, "will": {}, "with": {},
+	"would": {}, "you": {}, "your": {},
+}
@@ -0,0 +1,92 @@
+package models
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func topicFixture() []Conversation {
+	conversation := func(id string, name string, texts ...string) Conversation {
+		messages := make([]Message, 0, len(texts))
+		for _, text := range texts {
+			messages = append(messages, Message{Speaker: "human", Text: text, Kind: MessageKindPrompt})
+		}
+		return Conversation{Provider: "chatgpt", ID: id, Name: name, Messages: messages}
+	}
+
+	return []Conversation{
+		conversation("k1", "New chat", "My kubernetes pods keep restarting in the cluster", "The kubernetes deployment uses helm"),
+		conversation("t1", "New chat", "Plan a travel itinerary for Japan with a flight and hotel"),
+		conversation("k2", "New chat", "How do I scale kubernetes pods with helm?"),
+		conversation("t2", "Trip", "Cheap flight and hotel options for travel to Japan in spring"),
+		conversation("k3", "Cluster upgrade", "Upgrading the kubernetes cluster broke the pods"),
+		conversation("t3", "New chat", "Travel insurance for my Japan flight"),
+		conversation("e1", "New chat", "?!", "ok"),
+	}
+}
+
+func topicIDs(topic Topic) []string {
+	ids := make([]string, 0, len(topic.Conversations))
+	for _, conversation := range topic.Conversations {
+		ids = append(ids, conversation.ConversationID)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+func TestClusterConversations(t *testing.T) {
+	topics := ClusterConversations(topicFixture(), 0)
+	if len(topics) != 3 {
+		t.Fatalf("expected two topics and Other, got %+v", topics)
+	}
+
+	clusters := [][]string{topicIDs(topics[0]), topicIDs(topics[1])}
+	sort.Slice(clusters, func(i, j int) bool { return clusters[i][0] < clusters[j][0] })
+	if !reflect.DeepEqual(clusters, [][]string{{"k1", "k2", "k3"}, {"t1", "t2", "t3"}}) {
+		t.Fatalf("unexpected clusters %v", clusters)
+	}
+
+	for _, topic := range topics[:2] {
+		expected := "kubernetes"
+		if topic.Conversations[0].ConversationID[0] == 't' {
+			expected = "travel"
+		}
+		found := false
+		for _, keyword := range topic.Keywords {
+			found = found || keyword == expected
+		}
+		if !found || topic.Label == "" || len(topic.Keywords) > topicKeywordCount {
+			t.Fatalf("expected %q among the keywords of %+v", expected, topic)
+		}
+	}
+
+	if topics[2].Label != otherTopicLabel || !reflect.DeepEqual(topicIDs(topics[2]), []string{"e1"}) {
+		t.Fatalf("expected the wordless conversation under Other, got %+v", topics[2])
+	}
+
+	if again := ClusterConversations(topicFixture(), 0); !reflect.DeepEqual(again, topics) {
+		t.Fatalf("expected clustering to be deterministic")
+	}
+}
+
+func TestClusterConversationsTopicCount(t *testing.T) {
+	tests := []struct {
+		name          string
+		conversations []Conversation
+		topicCount    int
+		expected      int
+	}{
+		{name: "empty", conversations: nil, topicCount: 0, expected: 0},
+		{name: "explicit count", conversations: topicFixture(), topicCount: 1, expected: 2},
+		{name: "no shared words", conversations: topicFixture()[:2], topicCount: 10, expected: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ClusterConversations(tt.conversations, tt.topicCount); len(got) != tt.expected {
+				t.Fatalf("expected %d topics, got %+v", tt.expected, got)
+			}
+		})
+	}
+}